@@ -19,8 +19,10 @@ const (
 	StaleBindingIDLabel             string         = "services.cloud.sap.com/stale"
 	StaleBindingRotationOfLabel     string         = "services.cloud.sap.com/rotationOf"
 	ForceRotateAnnotation           string         = "services.cloud.sap.com/forceRotate"
+	ForceReconcileAnnotation        string         = "services.cloud.sap.com/force-reconcile"
 	PreventDeletion                 string         = "services.cloud.sap.com/preventDeletion"
 	UseInstanceMetadataNameInSecret string         = "services.cloud.sap.com/useInstanceMetadataName"
+	PausedAnnotation                string         = "services.cloud.sap.com/paused"
 )
 
 type HTTPStatusCodeError struct {
@@ -63,8 +65,17 @@ const (
 	// ConditionPendingTermination resource is waiting for termination pre-conditions
 	ConditionPendingTermination = "PendingTermination"
 
+	// ConditionSecretDrifted represents if the data of the binding secret was edited and differs from the binding
+	ConditionSecretDrifted = "SecretDrifted"
+
+	// ConditionUpgradeAvailable represents if the service plan of the instance has a newer maintenance info version than the instance
+	ConditionUpgradeAvailable = "UpgradeAvailable"
+
 	// ConditionShared represents information about the instance share situation
 	ConditionShared = "Shared"
+
+	// ConditionPaused represents if the reconciliation of the resource is paused by the paused annotation of the resource or its namespace
+	ConditionPaused = "Paused"
 )
 
 // +kubebuilder:object:generate=false
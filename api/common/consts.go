@@ -6,6 +6,20 @@ const (
 	InstanceSecretRefLabel    = "services.cloud.sap.com/secret-ref_"
 	WatchSecretAnnotation     = "services.cloud.sap.com/watch-secret-"
 
+	// V1FieldsAnnotation keeps the v1 only fields of resources served as v1alpha1
+	V1FieldsAnnotation = "services.cloud.sap.com/v1-fields"
+	// V1Alpha1FieldsAnnotation keeps the v1alpha1 only fields of resources stored as v1
+	V1Alpha1FieldsAnnotation = "services.cloud.sap.com/v1alpha1-fields"
+	// SecretTemplateHashAnnotation holds the hash of the referenced secret template a binding secret was rendered with
+	SecretTemplateHashAnnotation = "services.cloud.sap.com/secret-template-hash"
+
+	// SecretDataHashAnnotation holds the hash of the data of a binding secret as it was written by the operator, to detect edits
+	SecretDataHashAnnotation = "services.cloud.sap.com/secret-data-hash"
+	// SecretHashAnnotation holds the hash of the binding secret on the pod template of the workloads rolled out after credentials rotation
+	SecretHashAnnotation = "services.cloud.sap.com/secret-hash"
+	// ServiceBindingIOSecretTypePrefix is the prefix of the type of secrets compliant with the Service Binding for Kubernetes specification
+	ServiceBindingIOSecretTypePrefix = "servicebinding.io/"
+
 	NamespaceLabel = "_namespace"
 	K8sNameLabel   = "_k8sname"
 	ClusterIDLabel = "_clusterid"
@@ -35,11 +49,41 @@ const (
 	Blocked = "Blocked"
 	Unknown = "Unknown"
 
+	Recovered = "Recovered"
+
+	// Force reconcile
+	ForceReconciled      = "ForceReconciled"
+	ForceReconcileFailed = "ForceReconcileFailed"
+
+	// Paused reconciliation
+	Paused          = "Paused"
+	PausedNamespace = "NamespacePaused"
+
 	// Cred Rotation
-	CredPreparing = "Preparing"
-	CredRotating  = "Rotating"
+	CredPreparing      = "Preparing"
+	CredRotating       = "Rotating"
+	CredRollingOut     = "RollingOut"
+	CredRotated        = "Rotated"
+	CredRotationFailed = "RotationFailed"
+
+	// Stale bindings
+	StaleBindingDeleted = "StaleBindingDeleted"
+
+	// Secret drift
+	SecretDataChanged = "SecretDataChanged"
+
+	// SM labels
+	LabelSyncFailed = "LabelSyncFailed"
+
+	// Maintenance info upgrade
+	MaintenanceInfoOutdated = "MaintenanceInfoOutdated"
+	MaintenanceInfoUpToDate = "MaintenanceInfoUpToDate"
 
 	// Constance for seceret template
 	InstanceKey    = "instance"
 	CredentialsKey = "credentials"
+	ParametersKey  = "parameters"
+	BindingKey     = "binding"
+	SubaccountKey  = "subaccount"
+	VersionKey     = "version"
 )
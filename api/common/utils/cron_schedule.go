@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a cron expression with the minute, hour, day of month, month and day of week fields
+type CronSchedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	// the days match if either the day of month or the day of week matches, unless one of them starts with *, e.g. * or */2
+	dayOfMonthStar, dayOfWeekStar bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField     = cronField{name: "minute", min: 0, max: 59}
+	hourField       = cronField{name: "hour", min: 0, max: 23}
+	dayOfMonthField = cronField{name: "day of month", min: 1, max: 31}
+	monthField      = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is Sunday as well
+	dayOfWeekField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCronSchedule parses a standard cron expression, e.g. "0 2 * * SAT", or one of the @yearly, @monthly, @weekly, @daily and @hourly macros
+func ParseCronSchedule(expression string) (*CronSchedule, error) {
+	if macro, ok := cronMacros[strings.ToLower(strings.TrimSpace(expression))]; ok {
+		expression = macro
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, found %d", expression, len(fields))
+	}
+
+	schedule := &CronSchedule{}
+	var err error
+	if schedule.minute, err = parseCronField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = parseCronField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfMonth, err = parseCronField(fields[2], dayOfMonthField); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = parseCronField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfWeek, err = parseCronField(fields[4], dayOfWeekField); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+	// like Vixie cron, a field that starts with * is unrestricted even with a step
+	schedule.dayOfMonthStar = strings.HasPrefix(fields[2], "*")
+	schedule.dayOfWeekStar = strings.HasPrefix(fields[4], "*")
+	return schedule, nil
+}
+
+// parseCronField returns the bits of the values of a comma separated list of values, ranges and steps
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in the %s field", part[i+1:], f.name)
+			}
+			rangePart = part[:i]
+		}
+
+		var start, end int
+		switch {
+		case rangePart == "*":
+			start, end = f.min, f.max
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = parseCronValue(bounds[0], f); err != nil {
+				return 0, err
+			}
+			if end, err = parseCronValue(bounds[1], f); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range %q in the %s field", rangePart, f.name)
+			}
+		default:
+			var err error
+			if start, err = parseCronValue(rangePart, f); err != nil {
+				return 0, err
+			}
+			end = start
+			if step > 1 {
+				end = f.max
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(value string, f cronField) (int, error) {
+	if number, ok := f.names[strings.ToLower(value)]; ok {
+		return number, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < f.min || number > f.max {
+		return 0, fmt.Errorf("invalid value %q in the %s field, expected %d-%d", value, f.name, f.min, f.max)
+	}
+	return number, nil
+}
+
+// Next returns the first time of the schedule after t in the location of t, or the zero time if there is none in the next years
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + 5
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+	for s.month&(1<<uint(t.Month())) == 0 {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+	for !s.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+	return t
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	dayOfMonth := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.dayOfMonthStar || s.dayOfWeekStar {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
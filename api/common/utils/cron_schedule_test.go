@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cron schedule", func() {
+	parse := func(expression string) *CronSchedule {
+		schedule, err := ParseCronSchedule(expression)
+		Expect(err).ToNot(HaveOccurred())
+		return schedule
+	}
+
+	Context("ParseCronSchedule", func() {
+		It("should fail for an invalid expression", func() {
+			_, err := ParseCronSchedule("0 2 * *")
+			Expect(err).To(MatchError(`invalid cron expression "0 2 * *": expected 5 fields, found 4`))
+			_, err = ParseCronSchedule("0 24 * * *")
+			Expect(err).To(MatchError(`invalid value "24" in the hour field, expected 0-23`))
+			_, err = ParseCronSchedule("0 2 * * MON-FOO")
+			Expect(err).To(MatchError(`invalid value "FOO" in the day of week field, expected 0-7`))
+			_, err = ParseCronSchedule("*/0 * * * *")
+			Expect(err).To(MatchError(`invalid step "0" in the minute field`))
+		})
+	})
+
+	Context("Next", func() {
+		from := time.Date(2024, time.March, 6, 10, 17, 30, 0, time.UTC) // Wednesday
+
+		It("should return the next matching minute", func() {
+			Expect(parse("*/15 * * * *").Next(from)).To(Equal(time.Date(2024, time.March, 6, 10, 30, 0, 0, time.UTC)))
+			Expect(parse("@hourly").Next(from)).To(Equal(time.Date(2024, time.March, 6, 11, 0, 0, 0, time.UTC)))
+		})
+
+		It("should return a time after the given time", func() {
+			exact := time.Date(2024, time.March, 6, 10, 30, 0, 0, time.UTC)
+			Expect(parse("30 10 * * *").Next(exact)).To(Equal(time.Date(2024, time.March, 7, 10, 30, 0, 0, time.UTC)))
+		})
+
+		It("should support day names, lists and ranges", func() {
+			Expect(parse("0 2 * * SAT,SUN").Next(from)).To(Equal(time.Date(2024, time.March, 9, 2, 0, 0, 0, time.UTC)))
+			Expect(parse("0 2 * * 1-5").Next(from)).To(Equal(time.Date(2024, time.March, 7, 2, 0, 0, 0, time.UTC)))
+			Expect(parse("0 0 * * 7").Next(from)).To(Equal(time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should match either the day of month or the day of week if both are restricted", func() {
+			Expect(parse("0 0 1 * MON").Next(from)).To(Equal(time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC)))
+			Expect(parse("0 0 1 * *").Next(from)).To(Equal(time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should match both the day of month and the day of week if one of them is a step of *", func() {
+			Expect(parse("0 0 */2 * 1").Next(from)).To(Equal(time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC)))
+			Expect(parse("0 0 */2 * 1").Next(time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC))).
+				To(Equal(time.Date(2024, time.March, 25, 0, 0, 0, 0, time.UTC)))
+			Expect(parse("0 0 1 * */2").Next(from)).To(Equal(time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should wrap to the next year", func() {
+			Expect(parse("0 0 29 2 *").Next(from)).To(Equal(time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should use the location of the given time", func() {
+			berlin, err := time.LoadLocation("Europe/Berlin")
+			Expect(err).ToNot(HaveOccurred())
+			next := parse("0 2 * * *").Next(from.In(berlin))
+			Expect(next).To(Equal(time.Date(2024, time.March, 7, 2, 0, 0, 0, berlin)))
+			Expect(next.UTC()).To(Equal(time.Date(2024, time.March, 7, 1, 0, 0, 0, time.UTC)))
+		})
+	})
+})
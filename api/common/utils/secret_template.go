@@ -293,7 +293,10 @@ func CreateSecretFromTemplate(templateName, secretTemplate string, option string
 	if err != nil {
 		return nil, errors.Wrap(err, "the Secret template is invalid")
 	}
+	return secretFromManifest(secretManifest)
+}
 
+func secretFromManifest(secretManifest []byte) (*corev1.Secret, error) {
 	secret := &corev1.Secret{}
 	if err := yaml.Unmarshal(secretManifest, secret); err != nil {
 		return nil, errors.Wrap(err, "the Secret template is invalid: It does not result in a valid Secret YAML")
@@ -351,7 +354,10 @@ func executeTemplate(templateName, text, option string, parameters map[string]in
 	if err != nil {
 		return nil, err
 	}
+	return execute(t, option, parameters)
+}
 
+func execute(t *template.Template, option string, parameters map[string]interface{}) ([]byte, error) {
 	var buf bytes.Buffer
 	var writer io.Writer = &LimitedWriter{
 		W: &buf,
@@ -363,17 +369,24 @@ func executeTemplate(templateName, text, option string, parameters map[string]in
 			return err
 		},
 	}
-	err = t.Option(option).Execute(writer, parameters)
-	if err != nil {
+	if err := t.Option(option).Execute(writer, parameters); err != nil {
 		return nil, err
 	}
 
 	return buf.Bytes(), nil
 }
 
-func GetSecretDataForTemplate(Credential map[string]interface{}, instance map[string]string) map[string]interface{} {
+// SecretTemplateDataVersion is the version of the data secret templates are executed on, data is only added in new versions:
+// 1 - credentials and instance
+// 2 - version, binding, subaccount and instance.parameters
+const SecretTemplateDataVersion = 2
+
+func GetSecretDataForTemplate(Credential map[string]interface{}, instance, binding, subaccount map[string]interface{}) map[string]interface{} {
 	return map[string]interface{}{
+		common.VersionKey:     SecretTemplateDataVersion,
 		common.CredentialsKey: Credential,
 		common.InstanceKey:    instance,
+		common.BindingKey:     binding,
+		common.SubaccountKey:  subaccount,
 	}
 }
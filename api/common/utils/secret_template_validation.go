@@ -0,0 +1,316 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template/parse"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	"github.com/pkg/errors"
+)
+
+// sampleValue is the value of every key of an open map a secret template refers to when it is validated
+const sampleValue = "sample"
+
+// openPaths are the paths of the template data maps with arbitrary keys
+var openPaths = [][]string{
+	{common.CredentialsKey},
+	{common.ParametersKey},
+	{common.InstanceKey, common.ParametersKey},
+	{common.BindingKey, "labels"},
+}
+
+// sampleData returns the data a secret template is validated with, it has the keys of the real data
+func sampleData(parameters map[string]string) map[string]interface{} {
+	data := GetSecretDataForTemplate(
+		map[string]interface{}{},
+		map[string]interface{}{
+			"instance_name": "sample-instance",
+			"instance_guid": "00000000-0000-0000-0000-000000000000",
+			"plan":          "sample-plan",
+			"label":         "sample-offering",
+			"type":          "sample-offering",
+			"tags":          "sample-tag",
+			"parameters":    map[string]interface{}{},
+		},
+		map[string]interface{}{
+			"name":                           "sample-binding",
+			"namespace":                      "sample-namespace",
+			"external_name":                  "sample-binding",
+			"binding_id":                     "00000000-0000-0000-0000-000000000000",
+			"labels":                         map[string]interface{}{},
+			"last_credentials_rotation_time": "2000-01-01T00:00:00Z",
+		},
+		map[string]interface{}{
+			"id":          "00000000-0000-0000-0000-000000000000",
+			"data_center": "sample-data-center",
+		},
+	)
+	if parameters != nil {
+		sampleParameters := map[string]interface{}{}
+		for key, value := range parameters {
+			sampleParameters[key] = value
+		}
+		data[common.ParametersKey] = sampleParameters
+	}
+	return data
+}
+
+var templateErrorPattern = regexp.MustCompile(`(?s)^template: [^:]*:(\d+)(?::(\d+))?: (.*)$`)
+var quotedTokenPattern = regexp.MustCompile(`"([^"]+)"`)
+
+// TemplateError is an error at a specific line and column of a secret template
+type TemplateError struct {
+	Line int
+	// Column is 0 if the column is unknown
+	Column  int
+	Message string
+}
+
+func (e *TemplateError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("the Secret template is invalid: line %d, column %d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("the Secret template is invalid: line %d: %s", e.Line, e.Message)
+}
+
+// ValidateSecretTemplate parses the secret template and executes it on sample credentials and instance data,
+// it returns the errors CreateSecretFromTemplate would return for the real data, as far as they don't depend on the credentials.
+// Set parameters for templates that are rendered with template parameters.
+func ValidateSecretTemplate(templateName, text string, parameters map[string]string) error {
+	t, err := ParseTemplate(templateName, text)
+	if err != nil {
+		return toTemplateError(text, err)
+	}
+
+	data := sampleData(parameters)
+	v := &templateValidator{text: text, data: data}
+	if err := v.walk(t.Root, []string{}); err != nil {
+		return err
+	}
+
+	// the keys of the data are validated above, the open maps can have any key
+	secretManifest, err := execute(t, "missingkey=zero", data)
+	if err != nil {
+		return toTemplateError(text, err)
+	}
+	_, err = secretFromManifest(secretManifest)
+	return err
+}
+
+// templateValidator validates the data keys a template refers to and adds the keys of open maps it refers to
+type templateValidator struct {
+	text string
+	data map[string]interface{}
+}
+
+// walk validates the node, dot is the path of the data the dot refers to in the node, it is nil if unknown
+func (v *templateValidator) walk(node parse.Node, dot []string) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := v.walk(child, dot); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return v.walkPipe(n.Pipe, dot)
+	case *parse.TemplateNode:
+		return v.walkPipe(n.Pipe, dot)
+	case *parse.IfNode:
+		return v.walkBranch(&n.BranchNode, dot, dot)
+	case *parse.WithNode:
+		return v.walkBranch(&n.BranchNode, dot, v.pipePath(n.Pipe, dot))
+	case *parse.RangeNode:
+		if path := v.pipePath(n.Pipe, dot); len(path) > 0 {
+			v.resolve(path, []interface{}{})
+		}
+		return v.walkBranch(&n.BranchNode, dot, nil)
+	}
+	return nil
+}
+
+func (v *templateValidator) walkBranch(n *parse.BranchNode, dot, listDot []string) error {
+	if err := v.walkPipe(n.Pipe, dot); err != nil {
+		return err
+	}
+	if err := v.walk(n.List, listDot); err != nil {
+		return err
+	}
+	return v.walk(n.ElseList, dot)
+}
+
+func (v *templateValidator) walkPipe(pipe *parse.PipeNode, dot []string) error {
+	if pipe == nil {
+		return nil
+	}
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			if err := v.walkArg(arg, dot); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *templateValidator) walkArg(arg parse.Node, dot []string) error {
+	switch n := arg.(type) {
+	case *parse.FieldNode:
+		if dot != nil {
+			return v.validatePath(n, n.Ident, len(dot), argPath(n, dot))
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			return v.validatePath(n, n.Ident, -1, argPath(n, dot))
+		}
+	case *parse.ChainNode:
+		return v.walkArg(n.Node, dot)
+	case *parse.PipeNode:
+		return v.walkPipe(n, dot)
+	}
+	return nil
+}
+
+// pipePath returns the data path of a pipe that consists of a single field, or nil
+func (v *templateValidator) pipePath(pipe *parse.PipeNode, dot []string) []string {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return nil
+	}
+	return argPath(pipe.Cmds[0].Args[0], dot)
+}
+
+// validatePath validates the data path of a field or a variable node, path[i] is idents[i-identOffset] of the node
+func (v *templateValidator) validatePath(node parse.Node, idents []string, identOffset int, path []string) error {
+	if i := v.resolve(path, sampleValue); i >= 0 {
+		return v.errorAt(node, idents, i-identOffset, "map has no entry for key %q", path[i])
+	}
+	return nil
+}
+
+// resolve returns the index of the first key of the path that is not in the data, or -1.
+// Keys of open maps are added with the sample value, lists replace sample values but never maps.
+func (v *templateValidator) resolve(path []string, sample interface{}) int {
+	current := v.data
+	for i, key := range path {
+		if isOpenPath(path[:i]) {
+			addSample(current, path[i:], sample)
+			return -1
+		}
+		value, ok := current[key]
+		if !ok {
+			return i
+		}
+		if current, ok = value.(map[string]interface{}); !ok {
+			// errors of fields of other values are found by executing the template
+			return -1
+		}
+	}
+	return -1
+}
+
+func addSample(m map[string]interface{}, path []string, sample interface{}) {
+	for _, key := range path[:len(path)-1] {
+		nested, ok := m[key].(map[string]interface{})
+		if !ok {
+			nested = map[string]interface{}{}
+			m[key] = nested
+		}
+		m = nested
+	}
+
+	key := path[len(path)-1]
+	switch m[key].(type) {
+	case nil:
+		m[key] = sample
+	case string:
+		if _, isList := sample.([]interface{}); isList {
+			m[key] = sample
+		}
+	}
+}
+
+func isOpenPath(path []string) bool {
+	for _, openPath := range openPaths {
+		if slices.Equal(path, openPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorAt returns an error at the ident of a field or a variable node
+func (v *templateValidator) errorAt(node parse.Node, idents []string, index int, format string, args ...interface{}) error {
+	offset := int(node.Position())
+	if len(idents) > 1 {
+		// the position of a node with several idents is the position of the dot before its second ident
+		offset -= len(idents[0]) + 1
+		for _, ident := range idents[:index] {
+			offset += len(ident) + 1
+		}
+	}
+	before := v.text[:offset]
+	return &TemplateError{
+		Line:    strings.Count(before, "\n") + 1,
+		Column:  offset - strings.LastIndex(before, "\n"),
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// argPath returns the data path of a field or a variable of the root data, or nil
+func argPath(arg parse.Node, dot []string) []string {
+	switch n := arg.(type) {
+	case *parse.FieldNode:
+		if dot == nil {
+			return nil
+		}
+		return append(append([]string{}, dot...), n.Ident...)
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" {
+			return n.Ident[1:]
+		}
+	case *parse.DotNode:
+		return dot
+	}
+	return nil
+}
+
+// toTemplateError converts the position in a parse or an execution error of text/template into a TemplateError
+func toTemplateError(text string, err error) error {
+	match := templateErrorPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return errors.Wrap(err, "the Secret template is invalid")
+	}
+
+	templateErr := &TemplateError{Message: match[3]}
+	templateErr.Line, _ = strconv.Atoi(match[1])
+	if len(match[2]) > 0 {
+		// execution errors have a zero based column
+		column, _ := strconv.Atoi(match[2])
+		templateErr.Column = column + 1
+		return templateErr
+	}
+
+	// parse errors have no column, point to the token of the error or to the last action of the line
+	lines := strings.Split(text, "\n")
+	if templateErr.Line < 1 || templateErr.Line > len(lines) {
+		return templateErr
+	}
+	line := lines[templateErr.Line-1]
+	if token := quotedTokenPattern.FindStringSubmatch(templateErr.Message); token != nil {
+		if i := strings.Index(line, token[1]); i >= 0 {
+			templateErr.Column = i + 1
+			return templateErr
+		}
+	}
+	if i := strings.LastIndex(line, "{{"); i >= 0 {
+		templateErr.Column = i + 1
+	}
+	return templateErr
+}
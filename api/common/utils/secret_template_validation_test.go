@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"github.com/lithammer/dedent"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Secret template validation", func() {
+
+	It("should succeed for a valid template", func() {
+		secretTemplate := dedent.Dedent(`
+				apiVersion: v1
+				kind: Secret
+				metadata:
+				  labels:
+				    plan: {{ .instance.plan }}
+				stringData:
+				  user: {{ .credentials.uaa.clientid | quote }}
+				  port: {{ .credentials.port | int | add 1 | quote }}
+				  {{- range $url := .credentials.urls }}
+				  url: {{ $url }}
+				  {{- end }}
+			`)
+		Expect(ValidateSecretTemplate("test", secretTemplate, nil)).To(Succeed())
+	})
+
+	It("should fail with the line and column of a parse error", func() {
+		err := ValidateSecretTemplate("test", "stringData:\n  key: {{ .credentials.key ", nil)
+		Expect(err).To(Equal(&TemplateError{Line: 2, Column: 8, Message: "unclosed action"}))
+	})
+
+	It("should fail with the line and column of an execution error", func() {
+		err := ValidateSecretTemplate("test", "stringData:\n\n  key: {{ fail \"boom\" }}", nil)
+		Expect(err).To(BeAssignableToTypeOf(&TemplateError{}))
+		templateErr := err.(*TemplateError)
+		Expect(templateErr.Line).To(Equal(3))
+		Expect(templateErr.Column).To(Equal(11))
+		Expect(templateErr.Message).To(ContainSubstring("boom"))
+	})
+
+	It("should fail with the position of an unknown key", func() {
+		err := ValidateSecretTemplate("test", "stringData:\n  key: {{ .credentials.key }}{{ $.unknown.key }}", nil)
+		Expect(err).To(Equal(&TemplateError{Line: 2, Column: 34, Message: `map has no entry for key "unknown"`}))
+	})
+
+	It("should validate the parameters only if the template has parameters", func() {
+		secretTemplate := "stringData:\n  env: {{ .parameters.env }}"
+		Expect(ValidateSecretTemplate("test", secretTemplate, nil)).To(MatchError(ContainSubstring(`map has no entry for key "parameters"`)))
+		Expect(ValidateSecretTemplate("test", secretTemplate, map[string]string{"env": "dev"})).To(Succeed())
+	})
+
+	It("should succeed for a template that refers to the binding and subaccount data", func() {
+		secretTemplate := dedent.Dedent(`
+				stringData:
+				  {{- if ge .version 2 }}
+				  binding: {{ .binding.namespace }}/{{ .binding.name }}
+				  {{- end }}
+				  subaccount: {{ .subaccount.id }}/{{ .subaccount.data_center }}
+				  env: {{ index .binding.labels.env 0 }}
+				  db: {{ .instance.parameters.db.name }}
+			`)
+		Expect(ValidateSecretTemplate("test", secretTemplate, nil)).To(Succeed())
+	})
+
+	It("should fail with the position of an unknown binding key", func() {
+		err := ValidateSecretTemplate("test", "stringData:\n  key: {{ .binding.unknown }}", nil)
+		Expect(err).To(Equal(&TemplateError{Line: 2, Column: 19, Message: `map has no entry for key "unknown"`}))
+	})
+
+	It("should fail if the generated secret is not valid", func() {
+		err := ValidateSecretTemplate("test", "stringData: {{ .credentials.key }}", nil)
+		Expect(err).To(MatchError(ContainSubstring("It does not result in a valid Secret YAML")))
+	})
+})
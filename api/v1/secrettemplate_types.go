@@ -0,0 +1,84 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	SecretTemplateKind        = "SecretTemplate"
+	ClusterSecretTemplateKind = "ClusterSecretTemplate"
+)
+
+// SecretTemplateSpec defines a secret template shared by service bindings
+type SecretTemplateSpec struct {
+	// Template is a Go template of a Secret, it is rendered with the same data as spec.secretTemplate of a ServiceBinding
+	// and the template parameters under .parameters
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Template string `json:"template"`
+
+	// Parameters are the default template parameters, bindings can override them in spec.secretTemplateRef.parameters
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name="Age",type=date
+
+// SecretTemplate is the Schema for the secrettemplates API, it holds a secret template for the service bindings of its namespace
+type SecretTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SecretTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecretTemplateList contains a list of SecretTemplate
+type SecretTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecretTemplate `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name="Age",type=date
+
+// ClusterSecretTemplate is the Schema for the clustersecrettemplates API, it holds a secret template for the service bindings of all namespaces
+type ClusterSecretTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SecretTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterSecretTemplateList contains a list of ClusterSecretTemplate
+type ClusterSecretTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterSecretTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SecretTemplate{}, &SecretTemplateList{}, &ClusterSecretTemplate{}, &ClusterSecretTemplateList{})
+}
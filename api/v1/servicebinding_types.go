@@ -17,7 +17,11 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/SAP/sap-btp-service-operator/api/common"
+	commonutils "github.com/SAP/sap-btp-service-operator/api/common/utils"
 	"github.com/SAP/sap-btp-service-operator/client/sm/types"
 	v1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -62,6 +66,11 @@ type ServiceBindingSpec struct {
 	// +optional
 	SecretRootKey *string `json:"secretRootKey,omitempty"`
 
+	// SecretFormat is the format of the credentials in the secret, it cannot be used with secretKey or secretRootKey.
+	// If not specified, the top level credentials are stored under their own keys, nested credentials as JSON.
+	// +optional
+	SecretFormat *SecretFormat `json:"secretFormat,omitempty"`
+
 	// Parameters for the binding.
 	//
 	// The Parameters field is NOT secret or secured in any way and should
@@ -99,6 +108,112 @@ type ServiceBindingSpec struct {
 	// +optional
 	// +kubebuilder:pruning:PreserveUnknownFields
 	SecretTemplate string `json:"secretTemplate,omitempty"`
+
+	// SecretTemplateRef refers to a SecretTemplate or ClusterSecretTemplate used instead of an inline secretTemplate,
+	// the secret is rendered again whenever the referenced template changes
+	// +optional
+	SecretTemplateRef *SecretTemplateReference `json:"secretTemplateRef,omitempty"`
+
+	// ServiceBindingIO makes the secret compliant with the Service Binding for Kubernetes specification (https://servicebinding.io),
+	// the secret gets the servicebinding.io/<type> secret type and the type and provider entries
+	// +optional
+	ServiceBindingIO *ServiceBindingIO `json:"serviceBindingIO,omitempty"`
+
+	// SecretDriftPolicy defines what happens when the data of the secret is edited:
+	// Restore (default) - the secret is restored from the binding;
+	// Report - the secret is kept and the SecretDrifted condition is set
+	// +optional
+	// +kubebuilder:validation:Enum=Restore;Report
+	SecretDriftPolicy string `json:"secretDriftPolicy,omitempty"`
+
+	// SecretSink is where the credentials are stored:
+	// Kubernetes - in the secret spec.secretName;
+	// KV - in the key-value store configured by the sap-btp-secret-sink secret, only the path is kept in the status.
+	// Defaults to the type of the sap-btp-secret-sink secret of the namespace or the cluster, or to Kubernetes if there is none,
+	// and must be that type if the secret has enforce: "true"
+	// +optional
+	// +kubebuilder:validation:Enum=Kubernetes;KV
+	SecretSink string `json:"secretSink,omitempty"`
+}
+
+// ServiceBindingIO defines the type and provider entries of a secret compliant with the Service Binding for Kubernetes specification
+type ServiceBindingIO struct {
+	// Type of the service, defaults to the first tag of the service instance or to the service offering name if it has no tags
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]+$`
+	Type string `json:"type,omitempty"`
+
+	// Provider of the service, defaults to the service offering name
+	// +optional
+	Provider string `json:"provider,omitempty"`
+}
+
+const (
+	SecretFormatFlat       = "flat"
+	SecretFormatDotenv     = "dotenv"
+	SecretFormatProperties = "properties"
+	SecretFormatYAML       = "yaml"
+
+	KeyCaseUpper = "upper"
+	KeyCaseLower = "lower"
+
+	SecretDriftPolicyRestore = "Restore"
+	SecretDriftPolicyReport  = "Report"
+
+	SecretSinkKubernetes = "Kubernetes"
+	SecretSinkKV         = "KV"
+
+	// SecretSinkEnforceKey is the key of the secret sink secret that rejects a spec.secretSink of another type when "true"
+	SecretSinkEnforceKey = "enforce"
+)
+
+// SecretFormat defines the format of the credentials in the binding secret
+type SecretFormat struct {
+	// Type of the format:
+	// flat - every credential, including the nested ones, under its own key, e.g. uaa_clientid;
+	// dotenv, properties or yaml - all the credentials in a single .env, Java .properties or YAML file
+	// +required
+	// +kubebuilder:validation:Enum=flat;dotenv;properties;yaml
+	Type string `json:"type"`
+
+	// Key of the file in the secret for the dotenv, properties and yaml types,
+	// defaults to credentials.env, credentials.properties or credentials.yaml
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]+$`
+	Key string `json:"key,omitempty"`
+
+	// KeyCase converts the credentials keys to upper or lower case, by default the keys are not converted
+	// +optional
+	// +kubebuilder:validation:Enum=upper;lower
+	KeyCase string `json:"keyCase,omitempty"`
+
+	// Separator joins the keys of nested credentials, defaults to "." for the properties type and to "_" otherwise,
+	// it is not used by the yaml type
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]*$`
+	Separator *string `json:"separator,omitempty"`
+}
+
+// SecretTemplateReference refers to a shared secret template
+type SecretTemplateReference struct {
+	// Kind of the referenced template, a SecretTemplate in the namespace of the binding (default) or a ClusterSecretTemplate
+	// +optional
+	// +kubebuilder:validation:Enum=SecretTemplate;ClusterSecretTemplate
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the referenced template
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Parameters available to the template under .parameters, they override the default parameters of the template
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// IsClusterScoped returns true if the reference is to a ClusterSecretTemplate
+func (ref *SecretTemplateReference) IsClusterScoped() bool {
+	return ref.Kind == ClusterSecretTemplateKind
 }
 
 // ServiceBindingStatus defines the observed state of ServiceBinding
@@ -129,8 +244,36 @@ type ServiceBindingStatus struct {
 	// Indicates when binding secret was rotated
 	LastCredentialsRotationTime *metav1.Time `json:"lastCredentialsRotationTime,omitempty"`
 
+	// Indicates when the credentials are rotated next according to the rotation policy
+	// +optional
+	NextCredentialsRotationTime *metav1.Time `json:"nextCredentialsRotationTime,omitempty"`
+
 	// The subaccount id of the service binding
 	SubaccountID string `json:"subaccountID,omitempty"`
+
+	// Binding refers to the secret of the binding, it makes the binding a provisioned service of the
+	// Service Binding for Kubernetes specification (https://servicebinding.io)
+	// +optional
+	Binding *BindingSecretReference `json:"binding,omitempty"`
+
+	// ExternalSecretPath is the path of the credentials in the key-value store of the KV secret sink
+	// +optional
+	ExternalSecretPath string `json:"externalSecretPath,omitempty"`
+
+	// The Service Manager labels synced from the labels and annotations of the binding
+	// +optional
+	SyncedLabels map[string]string `json:"syncedLabels,omitempty"`
+
+	// The latest operations on the binding in Service Manager, the oldest operation is removed when there are more than 10
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	Operations []Operation `json:"operations,omitempty"`
+}
+
+// BindingSecretReference refers to the secret of a binding in its namespace
+type BindingSecretReference struct {
+	// Name of the secret
+	Name string `json:"name"`
 }
 
 // +kubebuilder:object:root=true
@@ -196,6 +339,18 @@ func (sb *ServiceBinding) SetAnnotations(annotations map[string]string) {
 	sb.ObjectMeta.Annotations = annotations
 }
 
+func (sb *ServiceBinding) GetOperations() []Operation {
+	return sb.Status.Operations
+}
+
+func (sb *ServiceBinding) SetOperations(operations []Operation) {
+	sb.Status.Operations = operations
+}
+
+func (sb *ServiceBinding) GetUserInfo() *v1.UserInfo {
+	return sb.Spec.UserInfo
+}
+
 // +kubebuilder:object:root=true
 
 // ServiceBindingList contains a list of ServiceBinding
@@ -211,6 +366,190 @@ type CredentialsRotationPolicy struct {
 	RotationFrequency string `json:"rotationFrequency,omitempty"`
 	// For how long to keep the rotated binding.
 	RotatedBindingTTL string `json:"rotatedBindingTTL,omitempty"`
+	// Schedule is a cron expression of the rotation times, e.g. "0 2 * * SAT", it is used instead of rotationFrequency
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+	// TimeZone of the schedule and the maintenance windows, an IANA time zone name such as Europe/Berlin, defaults to UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// MaintenanceWindows restrict the rotation to the given time windows, a rotation that is due outside of them is postponed to the next window
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// Workloads in the namespace of the binding to roll out after the credentials are rotated,
+	// the rotated binding is kept until they are rolled out
+	// +optional
+	RolloutWorkloads []WorkloadReference `json:"rolloutWorkloads,omitempty"`
+}
+
+// MaintenanceWindow is a recurring time window
+type MaintenanceWindow struct {
+	// Days of the week of the window, every day if empty
+	// +optional
+	Days []MaintenanceWindowDay `json:"days,omitempty"`
+
+	// Start time of the window in the 24-hour format HH:MM
+	// +required
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// Duration of the window, e.g. 4h
+	// +required
+	Duration string `json:"duration"`
+}
+
+// MaintenanceWindowDay is a day of the week
+// +kubebuilder:validation:Enum=Mon;Tue;Wed;Thu;Fri;Sat;Sun
+type MaintenanceWindowDay string
+
+var maintenanceWindowDays = map[MaintenanceWindowDay]time.Weekday{
+	"Sun": time.Sunday, "Mon": time.Monday, "Tue": time.Tuesday, "Wed": time.Wednesday, "Thu": time.Thursday, "Fri": time.Friday, "Sat": time.Saturday,
+}
+
+// NextRotationTime returns the first time after the last rotation at which the credentials should be rotated,
+// it is in the time zone of the policy
+func (p *CredentialsRotationPolicy) NextRotationTime(lastRotation time.Time) (time.Time, error) {
+	loc, err := p.location()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var due time.Time
+	if len(p.Schedule) > 0 {
+		schedule, err := commonutils.ParseCronSchedule(p.Schedule)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if due = schedule.Next(lastRotation.In(loc)); due.IsZero() {
+			return time.Time{}, fmt.Errorf("the schedule %q has no time in the next years", p.Schedule)
+		}
+	} else {
+		frequency, err := time.ParseDuration(p.RotationFrequency)
+		if err != nil {
+			return time.Time{}, err
+		}
+		due = lastRotation.Add(frequency).In(loc)
+	}
+
+	return p.NextMaintenanceWindowTime(due)
+}
+
+// NextMaintenanceWindowTime returns t if it is in one of the maintenance windows, otherwise the start of the next window,
+// it is in the time zone of the policy
+func (p *CredentialsRotationPolicy) NextMaintenanceWindowTime(t time.Time) (time.Time, error) {
+	loc, err := p.location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	t = t.In(loc)
+	if len(p.MaintenanceWindows) == 0 {
+		return t, nil
+	}
+
+	var next time.Time
+	for _, window := range p.MaintenanceWindows {
+		windowTime, err := window.next(t)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if next.IsZero() || windowTime.Before(next) {
+			next = windowTime
+		}
+	}
+	return next, nil
+}
+
+// Validate returns an error if the schedule, time zone or maintenance windows of the policy are invalid
+func (p *CredentialsRotationPolicy) Validate() error {
+	if _, err := p.location(); err != nil {
+		return err
+	}
+	if len(p.Schedule) > 0 {
+		if _, err := commonutils.ParseCronSchedule(p.Schedule); err != nil {
+			return err
+		}
+	} else if _, err := time.ParseDuration(p.RotationFrequency); err != nil {
+		return err
+	}
+	for _, window := range p.MaintenanceWindows {
+		if _, err := window.next(time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *CredentialsRotationPolicy) location() (*time.Location, error) {
+	if len(p.TimeZone) == 0 {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(p.TimeZone)
+}
+
+// next returns t if it is in the window, otherwise the start of the next window after t, in the location of t
+func (w MaintenanceWindow) next(t time.Time) (time.Time, error) {
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid maintenance window start %q", w.Start)
+	}
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil || duration <= 0 {
+		return time.Time{}, fmt.Errorf("invalid maintenance window duration %q", w.Duration)
+	}
+	for _, day := range w.Days {
+		if _, ok := maintenanceWindowDays[day]; !ok {
+			return time.Time{}, fmt.Errorf("invalid maintenance window day %q", day)
+		}
+	}
+
+	// a window that started on one of the previous days may still be open
+	days := int(duration/(24*time.Hour)) + 1
+	for i := -days; i <= 7; i++ {
+		windowStart := time.Date(t.Year(), t.Month(), t.Day()+i, start.Hour(), start.Minute(), 0, 0, t.Location())
+		if !w.includesDay(windowStart.Weekday()) {
+			continue
+		}
+		if !t.Before(windowStart) && t.Before(windowStart.Add(duration)) {
+			return t, nil
+		}
+		if windowStart.After(t) {
+			return windowStart, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("the maintenance window has no days")
+}
+
+func (w MaintenanceWindow) includesDay(weekday time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, day := range w.Days {
+		if maintenanceWindowDays[day] == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	WorkloadKindDeployment  = "Deployment"
+	WorkloadKindStatefulSet = "StatefulSet"
+	WorkloadKindDaemonSet   = "DaemonSet"
+)
+
+// WorkloadReference refers to workloads by name or by label selector
+type WorkloadReference struct {
+	// Kind of the workloads
+	// +required
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet
+	Kind string `json:"kind"`
+
+	// Name of the workload, either name or selector must be set
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Selector of the workloads, either name or selector must be set
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
 }
 
 func init() {
@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"time"
+
 	"github.com/SAP/sap-btp-service-operator/api/common"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -93,4 +95,52 @@ var _ = Describe("Service Binding Type Test", func() {
 		binding.SetAnnotations(annotation)
 		Expect(binding.GetAnnotations()).To(Equal(annotation))
 	})
+
+	Context("NextRotationTime", func() {
+		lastRotation := time.Date(2024, time.March, 6, 10, 17, 0, 0, time.UTC) // Wednesday
+
+		It("should add the rotation frequency to the last rotation", func() {
+			policy := &CredentialsRotationPolicy{RotationFrequency: "24h"}
+			Expect(policy.NextRotationTime(lastRotation)).To(BeTemporally("==", lastRotation.Add(24*time.Hour)))
+		})
+
+		It("should use the schedule in the time zone of the policy", func() {
+			policy := &CredentialsRotationPolicy{RotationFrequency: "1h", Schedule: "0 2 * * SAT", TimeZone: "Europe/Berlin"}
+			Expect(policy.NextRotationTime(lastRotation)).To(BeTemporally("==", time.Date(2024, time.March, 9, 1, 0, 0, 0, time.UTC)))
+		})
+
+		It("should keep a rotation time that is in a maintenance window", func() {
+			policy := &CredentialsRotationPolicy{
+				RotationFrequency:  "1h",
+				MaintenanceWindows: []MaintenanceWindow{{Days: []MaintenanceWindowDay{"Wed"}, Start: "09:00", Duration: "4h"}},
+			}
+			Expect(policy.NextRotationTime(lastRotation)).To(BeTemporally("==", lastRotation.Add(time.Hour)))
+		})
+
+		It("should postpone the rotation to the next maintenance window", func() {
+			policy := &CredentialsRotationPolicy{
+				RotationFrequency: "1h",
+				TimeZone:          "Europe/Berlin",
+				MaintenanceWindows: []MaintenanceWindow{
+					{Days: []MaintenanceWindowDay{"Sat", "Sun"}, Start: "01:00", Duration: "2h"},
+					{Days: []MaintenanceWindowDay{"Thu"}, Start: "22:00", Duration: "4h"},
+				},
+			}
+			Expect(policy.NextRotationTime(lastRotation)).To(BeTemporally("==", time.Date(2024, time.March, 7, 21, 0, 0, 0, time.UTC)))
+		})
+
+		It("should keep a rotation time in a window that started on the previous day", func() {
+			policy := &CredentialsRotationPolicy{
+				RotationFrequency:  "1h",
+				MaintenanceWindows: []MaintenanceWindow{{Days: []MaintenanceWindowDay{"Tue"}, Start: "22:00", Duration: "14h"}},
+			}
+			Expect(policy.NextRotationTime(lastRotation.Add(-2 * time.Hour))).To(BeTemporally("==", lastRotation.Add(-time.Hour)))
+		})
+
+		It("should return the start of the next maintenance window", func() {
+			policy := &CredentialsRotationPolicy{MaintenanceWindows: []MaintenanceWindow{{Days: []MaintenanceWindowDay{"Sat"}, Start: "01:00", Duration: "4h"}}}
+			Expect(policy.NextMaintenanceWindowTime(lastRotation)).To(BeTemporally("==", time.Date(2024, time.March, 9, 1, 0, 0, 0, time.UTC)))
+			Expect((&CredentialsRotationPolicy{}).NextMaintenanceWindowTime(lastRotation)).To(BeTemporally("==", lastRotation))
+		})
+	})
 })
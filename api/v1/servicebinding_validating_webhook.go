@@ -23,8 +23,11 @@ import (
 	"time"
 
 	"github.com/SAP/sap-btp-service-operator/api/common"
+	commonutils "github.com/SAP/sap-btp-service-operator/api/common/utils"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -33,8 +36,25 @@ import (
 // log is for logging in this package.
 var servicebindinglog = logf.Log.WithName("servicebinding-resource")
 
-func (sb *ServiceBinding) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).For(sb).WithValidator(sb).Complete()
+// SecretSinkSecretGetter returns the secret sink secret of the namespace or of the cluster, nil if there is none
+// +kubebuilder:object:generate=false
+type SecretSinkSecretGetter func(ctx context.Context, namespace string) (*corev1.Secret, error)
+
+// ServiceBindingWebhookOptions configures the validation of the service bindings
+// +kubebuilder:object:generate=false
+type ServiceBindingWebhookOptions struct {
+	// GetSecretSinkSecret returns the secret sink secret that may enforce the secret sink of the bindings
+	GetSecretSinkSecret SecretSinkSecretGetter
+	// EnforceServiceBindingGrants requires a ServiceBindingGrant for the bindings to service instances of other namespaces
+	EnforceServiceBindingGrants bool
+}
+
+func (sb *ServiceBinding) SetupWebhookWithManager(mgr ctrl.Manager, options ServiceBindingWebhookOptions) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(sb).WithValidator(&serviceBindingValidator{
+		client:              mgr.GetClient(),
+		getSecretSinkSecret: options.GetSecretSinkSecret,
+		enforceGrants:       options.EnforceServiceBindingGrants,
+	}).Complete()
 }
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -42,10 +62,18 @@ func (sb *ServiceBinding) SetupWebhookWithManager(mgr ctrl.Manager) error {
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 // +kubebuilder:webhook:verbs=create;update,path=/validate-services-cloud-sap-com-v1-servicebinding,mutating=false,failurePolicy=fail,groups=services.cloud.sap.com,resources=servicebindings,versions=v1,name=vservicebinding.kb.io,sideEffects=None,admissionReviewVersions=v1beta1;v1
 
-var _ webhook.CustomValidator = &ServiceBinding{}
+// serviceBindingValidator validates service bindings, client reads the service binding grants if enforceGrants is set
+// and getSecretSinkSecret the enforced secret sinks
+type serviceBindingValidator struct {
+	client              client.Reader
+	getSecretSinkSecret SecretSinkSecretGetter
+	enforceGrants       bool
+}
+
+var _ webhook.CustomValidator = &serviceBindingValidator{}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
-func (sb *ServiceBinding) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *serviceBindingValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	newBinding := obj.(*ServiceBinding)
 	servicebindinglog.Info("validate create", "name", newBinding.ObjectMeta.Name)
 	if newBinding.Spec.CredRotationPolicy != nil {
@@ -53,11 +81,25 @@ func (sb *ServiceBinding) ValidateCreate(_ context.Context, obj runtime.Object)
 			return nil, err
 		}
 	}
+	if err := newBinding.validateSecretTemplate(nil); err != nil {
+		return nil, err
+	}
+	if err := newBinding.validateSecretFormat(); err != nil {
+		return nil, err
+	}
+	if v.enforceGrants {
+		if err := newBinding.validateServiceBindingGrant(ctx, v.client, nil); err != nil {
+			return nil, err
+		}
+	}
+	if err := newBinding.validateSecretSink(ctx, v.getSecretSinkSecret, nil); err != nil {
+		return nil, err
+	}
 	return nil, nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (sb *ServiceBinding) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+func (v *serviceBindingValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	oldBinding := oldObj.(*ServiceBinding)
 	newBinding := newObj.(*ServiceBinding)
 	servicebindinglog.Info("validate update", "name", newBinding.ObjectMeta.Name)
@@ -66,6 +108,20 @@ func (sb *ServiceBinding) ValidateUpdate(_ context.Context, oldObj, newObj runti
 			return nil, err
 		}
 	}
+	if err := newBinding.validateSecretTemplate(oldBinding); err != nil {
+		return nil, err
+	}
+	if err := newBinding.validateSecretFormat(); err != nil {
+		return nil, err
+	}
+	if v.enforceGrants {
+		if err := newBinding.validateServiceBindingGrant(ctx, v.client, oldBinding); err != nil {
+			return nil, err
+		}
+	}
+	if err := newBinding.validateSecretSink(ctx, v.getSecretSinkSecret, oldBinding); err != nil {
+		return nil, err
+	}
 	isStale := false
 	if oldBinding.Labels != nil {
 		if _, ok := oldBinding.Labels[common.StaleBindingIDLabel]; ok {
@@ -93,6 +149,55 @@ func (sb *ServiceBinding) ValidateUpdate(_ context.Context, oldObj, newObj runti
 	return nil, nil
 }
 
+// validateSecretSink validates that spec.secretSink does not override an enforced secret sink, on update only if it changed
+func (sb *ServiceBinding) validateSecretSink(ctx context.Context, getSecretSinkSecret SecretSinkSecretGetter, oldBinding *ServiceBinding) error {
+	if len(sb.Spec.SecretSink) == 0 || !sb.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if oldBinding != nil && oldBinding.Spec.SecretSink == sb.Spec.SecretSink {
+		return nil
+	}
+	if getSecretSinkSecret == nil {
+		return fmt.Errorf("failed to validate the secret sink, the webhook secret sink lookup is not set")
+	}
+	sinkSecret, err := getSecretSinkSecret(ctx, sb.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get the secret sink secret: %w", err)
+	}
+	return ValidateSecretSink(sb, sinkSecret)
+}
+
+// ValidateSecretSink returns an error if the secret sink secret enforces its type and spec.secretSink of the binding is another one
+func ValidateSecretSink(binding *ServiceBinding, sinkSecret *corev1.Secret) error {
+	if sinkSecret == nil || len(binding.Spec.SecretSink) == 0 || string(sinkSecret.Data[SecretSinkEnforceKey]) != "true" {
+		return nil
+	}
+	sinkType := string(sinkSecret.Data["type"])
+	if len(sinkType) == 0 {
+		sinkType = SecretSinkKubernetes
+	}
+	if binding.Spec.SecretSink != sinkType {
+		return fmt.Errorf("spec.secretSink %s is not allowed, the %s secret sink is enforced for the namespace %s", binding.Spec.SecretSink, sinkType, binding.Namespace)
+	}
+	return nil
+}
+
+// validateServiceBindingGrant validates that a binding to a service instance of another namespace is granted,
+// on update only if the service instance changed
+func (sb *ServiceBinding) validateServiceBindingGrant(ctx context.Context, reader client.Reader, oldBinding *ServiceBinding) error {
+	if reader == nil {
+		return fmt.Errorf("failed to validate the service binding grants, the webhook client is not set")
+	}
+	if !sb.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if oldBinding != nil && oldBinding.Spec.ServiceInstanceName == sb.Spec.ServiceInstanceName &&
+		oldBinding.Spec.ServiceInstanceNamespace == sb.Spec.ServiceInstanceNamespace {
+		return nil
+	}
+	return ValidateServiceBindingGrant(ctx, reader, sb)
+}
+
 func (sb *ServiceBinding) validateRotationLabels(old *ServiceBinding) bool {
 	if sb.ObjectMeta.Labels[common.StaleBindingIDLabel] != old.ObjectMeta.Labels[common.StaleBindingIDLabel] {
 		return false
@@ -111,13 +216,27 @@ func (sb *ServiceBinding) specChanged(oldBinding *ServiceBinding) bool {
 	//allow changing SecretTemplate
 	oldSpec.SecretTemplate = ""
 	newSpec.SecretTemplate = ""
+	oldSpec.SecretTemplateRef = nil
+	newSpec.SecretTemplateRef = nil
+
+	//allow changing SecretFormat
+	oldSpec.SecretFormat = nil
+	newSpec.SecretFormat = nil
+
+	//allow changing ServiceBindingIO
+	oldSpec.ServiceBindingIO = nil
+	newSpec.ServiceBindingIO = nil
+
+	//allow changing SecretDriftPolicy
+	oldSpec.SecretDriftPolicy = ""
+	newSpec.SecretDriftPolicy = ""
 
 	return !reflect.DeepEqual(oldSpec, newSpec)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
-func (sb *ServiceBinding) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
-	servicebindinglog.Info("validate delete", "name", sb.ObjectMeta.Name)
+func (v *serviceBindingValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	servicebindinglog.Info("validate delete", "name", obj.(*ServiceBinding).ObjectMeta.Name)
 
 	// TODO(user): fill in your validation logic upon object deletion.
 	return nil, nil
@@ -128,10 +247,43 @@ func (sb *ServiceBinding) validateCredRotatingConfig() error {
 	if err != nil {
 		return err
 	}
-	_, err = time.ParseDuration(sb.Spec.CredRotationPolicy.RotationFrequency)
-	if err != nil {
+	if err = sb.Spec.CredRotationPolicy.Validate(); err != nil {
 		return err
 	}
 
+	for i, workload := range sb.Spec.CredRotationPolicy.RolloutWorkloads {
+		if (len(workload.Name) > 0) == (workload.Selector != nil) {
+			return fmt.Errorf("spec.credentialsRotationPolicy.rolloutWorkloads[%d] must have either a name or a selector", i)
+		}
+	}
+
+	return nil
+}
+
+// validateSecretTemplate dry runs spec.secretTemplate on sample data, on update only if it was changed
+func (sb *ServiceBinding) validateSecretTemplate(oldBinding *ServiceBinding) error {
+	if len(sb.Spec.SecretTemplate) > 0 && sb.Spec.SecretTemplateRef != nil {
+		return fmt.Errorf("spec.secretTemplate and spec.secretTemplateRef are mutually exclusive")
+	}
+	if len(sb.Spec.SecretTemplate) == 0 || (oldBinding != nil && oldBinding.Spec.SecretTemplate == sb.Spec.SecretTemplate) {
+		return nil
+	}
+	templateName := fmt.Sprintf("%s/%s", sb.Namespace, sb.Name)
+	if err := commonutils.ValidateSecretTemplate(templateName, sb.Spec.SecretTemplate, nil); err != nil {
+		return fmt.Errorf("spec.secretTemplate is invalid: %w", err)
+	}
+	return nil
+}
+
+func (sb *ServiceBinding) validateSecretFormat() error {
+	if sb.Spec.SecretFormat == nil {
+		return nil
+	}
+	if sb.Spec.SecretKey != nil || sb.Spec.SecretRootKey != nil {
+		return fmt.Errorf("spec.secretFormat cannot be used with spec.secretKey or spec.secretRootKey")
+	}
+	if len(sb.Spec.SecretFormat.Key) > 0 && sb.Spec.SecretFormat.Type == SecretFormatFlat {
+		return fmt.Errorf("spec.secretFormat.key cannot be used with the %s type", SecretFormatFlat)
+	}
 	return nil
 }
@@ -1,24 +1,38 @@
 package v1
 
 import (
+	"context"
+
 	"github.com/SAP/sap-btp-service-operator/api/common"
 	"github.com/lithammer/dedent"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = Describe("Service Binding Webhook Test", func() {
 	var binding *ServiceBinding
+	var validator *serviceBindingValidator
 	BeforeEach(func() {
 		binding = getBinding()
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).To(Succeed())
+		validator = &serviceBindingValidator{
+			client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+			getSecretSinkSecret: func(ctx context.Context, namespace string) (*corev1.Secret, error) {
+				return nil, nil
+			},
+		}
 	})
 
 	Context("Validator", func() {
 		Context("Validate create", func() {
 			It("should succeed", func() {
-				_, err := binding.ValidateCreate(nil, binding)
+				_, err := validator.ValidateCreate(nil, binding)
 				Expect(err).ToNot(HaveOccurred())
 			})
 			It("should succeed if using allowed sprig function", func() {
@@ -27,10 +41,83 @@ var _ = Describe("Service Binding Webhook Test", func() {
 				                                       apiVersion: v1
 				                                       kind: Secret
 				                                       stringData:
-				                                         secretKey: {{ .secretValue | quote }}`)
-				_, err := binding.ValidateCreate(nil, binding)
+				                                         secretKey: {{ .credentials.secretValue | quote }}`)
+				_, err := validator.ValidateCreate(nil, binding)
+				Expect(err).ToNot(HaveOccurred())
+			})
+			It("should succeed with a secretTemplate that refers to nested credentials", func() {
+				binding.Spec.SecretTemplate = dedent.Dedent(`
+				                                       stringData:
+				                                         plan: {{ .instance.plan }}
+				                                         {{- with .credentials.uaa }}
+				                                         clientid: {{ .clientid }}
+				                                         {{- end }}
+				                                         {{- range $key, $value := .credentials.urls }}
+				                                         {{ $key }}: {{ $value }}
+				                                         {{- end }}
+				                                         url: {{ $.credentials.uaa.url | b64enc }}`)
+				_, err := validator.ValidateCreate(nil, binding)
 				Expect(err).ToNot(HaveOccurred())
 			})
+			It("should fail if secretTemplate cannot be parsed", func() {
+				binding.Spec.SecretTemplate = "stringData:\n  key: {{ .credentials.key | notAFunction }}"
+				_, err := validator.ValidateCreate(nil, binding)
+				Expect(err).To(MatchError(ContainSubstring(`line 2, column 30: function "notAFunction" not defined`)))
+			})
+			It("should fail if secretTemplate refers to an unknown key", func() {
+				binding.Spec.SecretTemplate = "stringData:\n  key: {{ .instance.plan }}-{{ .secretValue }}"
+				_, err := validator.ValidateCreate(nil, binding)
+				Expect(err).To(MatchError(ContainSubstring(`line 2, column 32: map has no entry for key "secretValue"`)))
+			})
+			It("should fail if secretTemplate refers to an unknown instance key", func() {
+				binding.Spec.SecretTemplate = "stringData:\n  key: {{ .instance.unknown }}"
+				_, err := validator.ValidateCreate(nil, binding)
+				Expect(err).To(MatchError(ContainSubstring(`line 2, column 20: map has no entry for key "unknown"`)))
+			})
+			It("should fail if secretTemplate edits a forbidden metadata field", func() {
+				binding.Spec.SecretTemplate = "metadata:\n  name: {{ .instance.instance_name }}"
+				_, err := validator.ValidateCreate(nil, binding)
+				Expect(err).To(MatchError(ContainSubstring("Secret's metadata field 'name' cannot be edited")))
+			})
+			It("should fail if secretTemplate is of another kind", func() {
+				binding.Spec.SecretTemplate = "apiVersion: v1\nkind: Pod"
+				_, err := validator.ValidateCreate(nil, binding)
+				Expect(err).To(MatchError(ContainSubstring("needs to be of kind 'Secret'")))
+			})
+			It("should fail if secretTemplate generates a too large secret", func() {
+				binding.Spec.SecretTemplate = `stringData:
+  key: {{ repeat 2000000 "a" }}`
+				_, err := validator.ValidateCreate(nil, binding)
+				Expect(err).To(MatchError(ContainSubstring("exceeds the limit")))
+			})
+			It("should succeed with secretTemplateRef", func() {
+				binding.Spec.SecretTemplateRef = &SecretTemplateReference{Kind: ClusterSecretTemplateKind, Name: "template"}
+				_, err := validator.ValidateCreate(nil, binding)
+				Expect(err).ToNot(HaveOccurred())
+			})
+			It("should fail if both secretTemplate and secretTemplateRef are set", func() {
+				binding.Spec.SecretTemplate = "template"
+				binding.Spec.SecretTemplateRef = &SecretTemplateReference{Name: "template"}
+				_, err := validator.ValidateCreate(nil, binding)
+				Expect(err).To(MatchError(ContainSubstring("mutually exclusive")))
+			})
+			It("should succeed with secretFormat", func() {
+				binding.Spec.SecretFormat = &SecretFormat{Type: SecretFormatDotenv, Key: ".env", KeyCase: KeyCaseUpper}
+				_, err := validator.ValidateCreate(nil, binding)
+				Expect(err).ToNot(HaveOccurred())
+			})
+			It("should fail if secretFormat is used with secretKey", func() {
+				secretKey := "secret-key"
+				binding.Spec.SecretKey = &secretKey
+				binding.Spec.SecretFormat = &SecretFormat{Type: SecretFormatYAML}
+				_, err := validator.ValidateCreate(nil, binding)
+				Expect(err).To(MatchError("spec.secretFormat cannot be used with spec.secretKey or spec.secretRootKey"))
+			})
+			It("should fail if secretFormat of the flat type has a key", func() {
+				binding.Spec.SecretFormat = &SecretFormat{Type: SecretFormatFlat, Key: "credentials"}
+				_, err := validator.ValidateCreate(nil, binding)
+				Expect(err).To(MatchError("spec.secretFormat.key cannot be used with the flat type"))
+			})
 		})
 
 		Context("Validate update of spec before binding is created (failure recovery)", func() {
@@ -43,7 +130,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 				When("Service instance name changed", func() {
 					It("should succeed", func() {
 						newBinding.Spec.ServiceInstanceName = "new-service-instance"
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).ToNot(HaveOccurred())
 					})
 				})
@@ -51,7 +138,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 				When("External name changed", func() {
 					It("should succeed", func() {
 						newBinding.Spec.ExternalName = "new-external-instance"
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).ToNot(HaveOccurred())
 					})
 				})
@@ -61,7 +148,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 						newBinding.Spec.Parameters = &runtime.RawExtension{
 							Raw: []byte("params"),
 						}
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).ToNot(HaveOccurred())
 					})
 				})
@@ -69,7 +156,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 				When("ParametersFrom were changed", func() {
 					It("should succeed", func() {
 						newBinding.Spec.ParametersFrom[0].SecretKeyRef.Name = "newName"
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).ToNot(HaveOccurred())
 					})
 				})
@@ -83,7 +170,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 						  key2: "value2"
 					`)
 						newBinding.Spec.SecretTemplate = modifiedSecretTemplate
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).ToNot(HaveOccurred())
 					})
 				})
@@ -93,17 +180,17 @@ var _ = Describe("Service Binding Webhook Test", func() {
 						newBinding.Spec.UserInfo = &v1.UserInfo{
 							Username: "username",
 						}
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).To(HaveOccurred())
 						Expect(err.Error()).To(ContainSubstring("modifying spec.userInfo is not allowed"))
 					})
 					It("should succeed if new binding user info is empty", func() {
 						newBinding.Spec.UserInfo = nil
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).ToNot(HaveOccurred())
 					})
 					It("should succeed if user info not changed", func() {
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).ToNot(HaveOccurred())
 					})
 				})
@@ -112,7 +199,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 			When("Metadata changed", func() {
 				It("should succeed", func() {
 					newBinding.Finalizers = append(newBinding.Finalizers, "newFinalizer")
-					_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
 					Expect(err).ToNot(HaveOccurred())
 				})
 			})
@@ -124,7 +211,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 						RotatedBindingTTL: "1s",
 						RotationFrequency: "1s",
 					}
-					_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
 					Expect(err).ToNot(HaveOccurred())
 				})
 
@@ -134,15 +221,84 @@ var _ = Describe("Service Binding Webhook Test", func() {
 						RotatedBindingTTL: "1x",
 						RotationFrequency: "1y",
 					}
-					_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
 					Expect(err).To(HaveOccurred())
 				})
 
+				It("should succeed with rollout workloads", func() {
+					newBinding.Spec.CredRotationPolicy = &CredentialsRotationPolicy{
+						Enabled:           true,
+						RotatedBindingTTL: "1h",
+						RotationFrequency: "24h",
+						RolloutWorkloads: []WorkloadReference{
+							{Kind: WorkloadKindDeployment, Name: "app"},
+							{Kind: WorkloadKindStatefulSet, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+						},
+					}
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("should fail when a rollout workload has both a name and a selector", func() {
+					newBinding.Spec.CredRotationPolicy = &CredentialsRotationPolicy{
+						Enabled:           true,
+						RotatedBindingTTL: "1h",
+						RotationFrequency: "24h",
+						RolloutWorkloads:  []WorkloadReference{{Kind: WorkloadKindDeployment, Name: "app", Selector: &metav1.LabelSelector{}}},
+					}
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
+					Expect(err).To(MatchError("spec.credentialsRotationPolicy.rolloutWorkloads[0] must have either a name or a selector"))
+				})
+
+				It("should succeed with a schedule and maintenance windows", func() {
+					newBinding.Spec.CredRotationPolicy = &CredentialsRotationPolicy{
+						Enabled:            true,
+						RotatedBindingTTL:  "1h",
+						Schedule:           "0 2 * * SAT",
+						TimeZone:           "Europe/Berlin",
+						MaintenanceWindows: []MaintenanceWindow{{Days: []MaintenanceWindowDay{"Sat", "Sun"}, Start: "01:00", Duration: "4h"}},
+					}
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("should fail when the schedule is not valid", func() {
+					newBinding.Spec.CredRotationPolicy = &CredentialsRotationPolicy{
+						Enabled:           true,
+						RotatedBindingTTL: "1h",
+						Schedule:          "0 2 * *",
+					}
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
+					Expect(err).To(MatchError(`invalid cron expression "0 2 * *": expected 5 fields, found 4`))
+				})
+
+				It("should fail when the time zone is not valid", func() {
+					newBinding.Spec.CredRotationPolicy = &CredentialsRotationPolicy{
+						Enabled:           true,
+						RotatedBindingTTL: "1h",
+						RotationFrequency: "24h",
+						TimeZone:          "Europe/Nowhere",
+					}
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
+					Expect(err).To(HaveOccurred())
+				})
+
+				It("should fail when the maintenance window duration is not valid", func() {
+					newBinding.Spec.CredRotationPolicy = &CredentialsRotationPolicy{
+						Enabled:            true,
+						RotatedBindingTTL:  "1h",
+						RotationFrequency:  "24h",
+						MaintenanceWindows: []MaintenanceWindow{{Start: "01:00", Duration: "0s"}},
+					}
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
+					Expect(err).To(MatchError(`invalid maintenance window duration "0s"`))
+				})
+
 				It("should fail on update with stale label", func() {
 					binding.Labels = map[string]string{common.StaleBindingIDLabel: "true"}
 					newBinding.Spec.ParametersFrom[0].SecretKeyRef.Name = "newName"
 					newBinding.Labels = map[string]string{common.StaleBindingIDLabel: "true"}
-					_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
 					Expect(err).To(HaveOccurred())
 				})
 
@@ -151,7 +307,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 			When("Status changed", func() {
 				It("should succeed", func() {
 					newBinding.Status.BindingID = "12345"
-					_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
 					Expect(err).ToNot(HaveOccurred())
 				})
 			})
@@ -167,7 +323,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 				When("Service instance name changed", func() {
 					It("should fail", func() {
 						newBinding.Spec.ServiceInstanceName = "new-service-instance"
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).To(HaveOccurred())
 					})
 				})
@@ -175,7 +331,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 				When("External name changed", func() {
 					It("should fail", func() {
 						newBinding.Spec.ExternalName = "new-external-instance"
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).To(HaveOccurred())
 					})
 				})
@@ -183,7 +339,15 @@ var _ = Describe("Service Binding Webhook Test", func() {
 				When("secret name changed", func() {
 					It("should fail", func() {
 						newBinding.Spec.SecretName = "newsecret"
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
+						Expect(err).To(HaveOccurred())
+					})
+				})
+
+				When("secret sink changed", func() {
+					It("should fail", func() {
+						newBinding.Spec.SecretSink = SecretSinkKV
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).To(HaveOccurred())
 					})
 				})
@@ -192,7 +356,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 					It("should fail", func() {
 						secretKey := "secret-key"
 						newBinding.Spec.SecretKey = &secretKey
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).To(HaveOccurred())
 					})
 				})
@@ -201,7 +365,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 					It("should fail", func() {
 						secretRootKey := "root"
 						newBinding.Spec.SecretRootKey = &secretRootKey
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).To(HaveOccurred())
 					})
 				})
@@ -211,7 +375,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 						newBinding.Spec.Parameters = &runtime.RawExtension{
 							Raw: []byte("params"),
 						}
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).To(HaveOccurred())
 					})
 				})
@@ -219,26 +383,26 @@ var _ = Describe("Service Binding Webhook Test", func() {
 				When("ParametersFrom were changed", func() {
 					It("should fail on changed name", func() {
 						newBinding.Spec.ParametersFrom[0].SecretKeyRef.Name = "newName"
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).To(HaveOccurred())
 					})
 
 					It("should fail on changed key", func() {
 						newBinding.Spec.ParametersFrom[0].SecretKeyRef.Key = "newName"
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).To(HaveOccurred())
 					})
 
 					It("should fail on nil array", func() {
 						newBinding.Spec.ParametersFrom = nil
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).To(HaveOccurred())
 					})
 
 					It("should fail on changed array", func() {
 						p := ParametersFromSource{}
 						newBinding.Spec.ParametersFrom[0] = p
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).To(HaveOccurred())
 					})
 
@@ -246,8 +410,51 @@ var _ = Describe("Service Binding Webhook Test", func() {
 
 				When("secretTemplate changed", func() {
 					It("should succeed", func() {
-						newBinding.Spec.SecretTemplate = "new-template"
-						_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+						newBinding.Spec.SecretTemplate = "stringData:\n  key: new-value"
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
+						Expect(err).ToNot(HaveOccurred())
+					})
+					It("should fail if the new secretTemplate is invalid", func() {
+						newBinding.Spec.SecretTemplate = "stringData:\n  key: {{ .unknown }}"
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
+						Expect(err).To(MatchError(ContainSubstring(`map has no entry for key "unknown"`)))
+					})
+					It("should not validate an unchanged secretTemplate", func() {
+						binding.Spec.SecretTemplate = "stringData:\n  key: {{ .unknown }}"
+						newBinding.Spec.SecretTemplate = binding.Spec.SecretTemplate
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
+						Expect(err).ToNot(HaveOccurred())
+					})
+				})
+
+				When("secretTemplateRef changed", func() {
+					It("should succeed", func() {
+						newBinding.Spec.SecretTemplateRef = &SecretTemplateReference{Name: "new-template", Parameters: map[string]string{"key": "value"}}
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
+						Expect(err).ToNot(HaveOccurred())
+					})
+				})
+
+				When("secretFormat changed", func() {
+					It("should succeed", func() {
+						newBinding.Spec.SecretFormat = &SecretFormat{Type: SecretFormatProperties}
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
+						Expect(err).ToNot(HaveOccurred())
+					})
+				})
+
+				When("serviceBindingIO changed", func() {
+					It("should succeed", func() {
+						newBinding.Spec.ServiceBindingIO = &ServiceBindingIO{Type: "postgresql"}
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
+						Expect(err).ToNot(HaveOccurred())
+					})
+				})
+
+				When("secretDriftPolicy changed", func() {
+					It("should succeed", func() {
+						newBinding.Spec.SecretDriftPolicy = SecretDriftPolicyReport
+						_, err := validator.ValidateUpdate(nil, binding, newBinding)
 						Expect(err).ToNot(HaveOccurred())
 					})
 				})
@@ -256,7 +463,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 			When("Metadata changed", func() {
 				It("should succeed", func() {
 					newBinding.Finalizers = append(newBinding.Finalizers, "newFinalizer")
-					_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
 					Expect(err).ToNot(HaveOccurred())
 				})
 			})
@@ -268,7 +475,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 						RotatedBindingTTL: "1s",
 						RotationFrequency: "1s",
 					}
-					_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
 					Expect(err).ToNot(HaveOccurred())
 				})
 
@@ -278,7 +485,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 						RotatedBindingTTL: "1x",
 						RotationFrequency: "1y",
 					}
-					_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
 					Expect(err).To(HaveOccurred())
 				})
 			})
@@ -286,7 +493,7 @@ var _ = Describe("Service Binding Webhook Test", func() {
 			When("Status changed", func() {
 				It("should succeed", func() {
 					newBinding.Status.BindingID = "12345"
-					_, err := newBinding.ValidateUpdate(nil, binding, newBinding)
+					_, err := validator.ValidateUpdate(nil, binding, newBinding)
 					Expect(err).ToNot(HaveOccurred())
 				})
 			})
@@ -294,9 +501,137 @@ var _ = Describe("Service Binding Webhook Test", func() {
 
 		Context("Validate delete", func() {
 			It("should succeed", func() {
-				_, err := binding.ValidateDelete(nil, binding)
+				_, err := validator.ValidateDelete(nil, binding)
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
+
+		Context("Validate secret sink", func() {
+			var sinkSecret *corev1.Secret
+
+			BeforeEach(func() {
+				binding.Spec.SecretSink = SecretSinkKubernetes
+				sinkSecret = &corev1.Secret{Data: map[string][]byte{"type": []byte(SecretSinkKV), SecretSinkEnforceKey: []byte("true")}}
+				validator.getSecretSinkSecret = func(ctx context.Context, namespace string) (*corev1.Secret, error) {
+					return sinkSecret, nil
+				}
+			})
+
+			It("should fail if the secret sink lookup is not set", func() {
+				validator.getSecretSinkSecret = nil
+				_, err := validator.ValidateCreate(context.Background(), binding)
+				Expect(err).To(MatchError("failed to validate the secret sink, the webhook secret sink lookup is not set"))
+			})
+
+			It("should fail if the binding overrides an enforced secret sink", func() {
+				_, err := validator.ValidateCreate(context.Background(), binding)
+				Expect(err).To(MatchError("spec.secretSink Kubernetes is not allowed, the KV secret sink is enforced for the namespace namespace-1"))
+			})
+
+			It("should succeed if the binding sets the enforced secret sink", func() {
+				binding.Spec.SecretSink = SecretSinkKV
+				_, err := validator.ValidateCreate(context.Background(), binding)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should succeed if the secret sink is not enforced", func() {
+				delete(sinkSecret.Data, SecretSinkEnforceKey)
+				_, err := validator.ValidateCreate(context.Background(), binding)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should validate on update only if the secret sink changed", func() {
+				newBinding := binding.DeepCopy()
+				newBinding.Spec.ExternalName = "new-name"
+				_, err := validator.ValidateUpdate(context.Background(), binding, newBinding)
+				Expect(err).ToNot(HaveOccurred())
+
+				oldBinding := binding.DeepCopy()
+				oldBinding.Spec.SecretSink = ""
+				_, err = validator.ValidateUpdate(context.Background(), oldBinding, newBinding)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("Validate service binding grant", func() {
+			var grant *ServiceBindingGrant
+
+			BeforeEach(func() {
+				binding.Spec.ServiceInstanceNamespace = "namespace-2"
+				grant = &ServiceBindingGrant{
+					ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: "namespace-2"},
+					Spec: ServiceBindingGrantSpec{
+						From:                 []ServiceBindingGrantFrom{{Namespace: "namespace-3"}, {Namespace: "namespace-1"}},
+						ServiceInstanceNames: []string{"service-instance-1"},
+					},
+				}
+			})
+
+			JustBeforeEach(func() {
+				scheme := runtime.NewScheme()
+				Expect(AddToScheme(scheme)).To(Succeed())
+				validator.client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(grant).Build()
+				validator.enforceGrants = true
+			})
+
+			It("should fail if the webhook client is not set", func() {
+				validator.client = nil
+				_, err := validator.ValidateCreate(context.Background(), binding)
+				Expect(err).To(MatchError("failed to validate the service binding grants, the webhook client is not set"))
+			})
+
+			It("should succeed if the binding is granted", func() {
+				_, err := validator.ValidateCreate(context.Background(), binding)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should succeed if the instance is in the namespace of the binding", func() {
+				binding.Spec.ServiceInstanceNamespace = binding.Namespace
+				grant.Spec.From = []ServiceBindingGrantFrom{{Namespace: "namespace-3"}}
+				_, err := validator.ValidateCreate(context.Background(), binding)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			When("the namespace of the binding is not granted", func() {
+				BeforeEach(func() {
+					grant.Spec.From = []ServiceBindingGrantFrom{{Namespace: "namespace-3"}}
+				})
+
+				It("should fail on create", func() {
+					_, err := validator.ValidateCreate(context.Background(), binding)
+					Expect(err).To(MatchError("binding to service instance service-instance-1 in namespace namespace-2 is not granted to namespace namespace-1, " +
+						"a ServiceBindingGrant in namespace namespace-2 must allow it"))
+				})
+
+				It("should succeed if the service binding grants are not enforced", func() {
+					validator.enforceGrants = false
+					_, err := validator.ValidateCreate(context.Background(), binding)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("should fail on update only if the instance changed", func() {
+					newBinding := binding.DeepCopy()
+					newBinding.Spec.ExternalName = "new-name"
+					_, err := validator.ValidateUpdate(context.Background(), binding, newBinding)
+					Expect(err).ToNot(HaveOccurred())
+
+					oldBinding := binding.DeepCopy()
+					oldBinding.Spec.ServiceInstanceNamespace = ""
+					_, err = validator.ValidateUpdate(context.Background(), oldBinding, newBinding)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+
+			When("the instance is not granted", func() {
+				BeforeEach(func() {
+					grant.Spec.ServiceInstanceNames = []string{"service-instance-2"}
+				})
+
+				It("should fail", func() {
+					_, err := validator.ValidateCreate(context.Background(), binding)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
 	})
 })
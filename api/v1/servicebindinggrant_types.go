@@ -0,0 +1,108 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ServiceBindingGrantFrom identifies the service bindings that are granted
+type ServiceBindingGrantFrom struct {
+	// Namespace is the namespace of the service bindings
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+}
+
+// ServiceBindingGrantSpec defines the service bindings of other namespaces that can bind to the service instances of the namespace of the grant
+type ServiceBindingGrantSpec struct {
+	// From lists the namespaces whose service bindings can bind to the service instances
+	// +required
+	// +kubebuilder:validation:MinItems=1
+	From []ServiceBindingGrantFrom `json:"from"`
+
+	// ServiceInstanceNames lists the service instances that can be bound, all the service instances of the namespace if it is empty
+	// +optional
+	ServiceInstanceNames []string `json:"serviceInstanceNames,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name="Age",type=date
+
+// ServiceBindingGrant is the Schema for the servicebindinggrants API, it allows service bindings of other namespaces
+// to bind to the service instances of its namespace
+type ServiceBindingGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ServiceBindingGrantSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ServiceBindingGrantList contains a list of ServiceBindingGrant
+type ServiceBindingGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceBindingGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ServiceBindingGrant{}, &ServiceBindingGrantList{})
+}
+
+// Grants returns whether the grant allows the service binding to bind to its service instance
+func (g *ServiceBindingGrant) Grants(binding *ServiceBinding) bool {
+	if g.Namespace != binding.Spec.ServiceInstanceNamespace {
+		return false
+	}
+	if len(g.Spec.ServiceInstanceNames) > 0 && !slices.Contains(g.Spec.ServiceInstanceNames, binding.Spec.ServiceInstanceName) {
+		return false
+	}
+	return slices.ContainsFunc(g.Spec.From, func(from ServiceBindingGrantFrom) bool {
+		return from.Namespace == binding.Namespace
+	})
+}
+
+// IsCrossNamespace returns whether the service binding refers to a service instance of another namespace
+func (sb *ServiceBinding) IsCrossNamespace() bool {
+	return len(sb.Spec.ServiceInstanceNamespace) > 0 && sb.Spec.ServiceInstanceNamespace != sb.Namespace
+}
+
+// ValidateServiceBindingGrant returns an error if the service binding refers to a service instance of another namespace
+// and no ServiceBindingGrant of that namespace allows it
+func ValidateServiceBindingGrant(ctx context.Context, reader client.Reader, binding *ServiceBinding) error {
+	if !binding.IsCrossNamespace() {
+		return nil
+	}
+	grants := &ServiceBindingGrantList{}
+	if err := reader.List(ctx, grants, client.InNamespace(binding.Spec.ServiceInstanceNamespace)); err != nil {
+		return fmt.Errorf("failed to list the service binding grants: %w", err)
+	}
+	for _, grant := range grants.Items {
+		if grant.Grants(binding) {
+			return nil
+		}
+	}
+	return fmt.Errorf("binding to service instance %s in namespace %s is not granted to namespace %s, a ServiceBindingGrant in namespace %s must allow it",
+		binding.Spec.ServiceInstanceName, binding.Spec.ServiceInstanceNamespace, binding.Namespace, binding.Spec.ServiceInstanceNamespace)
+}
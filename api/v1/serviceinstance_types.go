@@ -93,8 +93,52 @@ type ServiceInstanceSpec struct {
 
 	// The name of the btp access credentials secret
 	BTPAccessCredentialsSecret string `json:"btpAccessCredentialsSecret,omitempty"`
+
+	// MaintenanceInfo is the maintenance info version of the service plan to upgrade the instance to
+	// +optional
+	MaintenanceInfo *MaintenanceInfo `json:"maintenanceInfo,omitempty"`
+
+	// UpgradePolicy defines how the instance is upgraded when its service plan publishes a new maintenance info version:
+	// Manual (default) - the instance is upgraded only to the version of spec.maintenanceInfo;
+	// Automatic - the instance is upgraded to the current version of its service plan and spec.maintenanceInfo is ignored
+	// +optional
+	// +kubebuilder:validation:Enum=Manual;Automatic
+	UpgradePolicy string `json:"upgradePolicy,omitempty"`
 }
 
+// MaintenanceInfo identifies a version of a service instance
+type MaintenanceInfo struct {
+	// Version of the maintenance info
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+
+	// Description of the maintenance info
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// LastOperation describes the last operation on a resource in Service Manager
+type LastOperation struct {
+	// The type of the operation, create, update or delete
+	Type types.OperationCategory `json:"type,omitempty"`
+
+	// The state of the operation, in progress, succeeded or failed
+	State types.OperationState `json:"state,omitempty"`
+
+	// The description of the operation
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// The time the operation was last updated
+	// +optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+}
+
+const (
+	UpgradePolicyManual    = "Manual"
+	UpgradePolicyAutomatic = "Automatic"
+)
+
 // ServiceInstanceStatus defines the observed state of ServiceInstance
 type ServiceInstanceStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -125,8 +169,73 @@ type ServiceInstanceStatus struct {
 	// The subaccount id of the service instance
 	SubaccountID string `json:"subaccountID,omitempty"`
 
+	// The ID of the service plan of the instance in Service Manager
+	// +optional
+	ServicePlanID string `json:"servicePlanID,omitempty"`
+
+	// The ID of the service offering of the instance in Service Manager
+	// +optional
+	ServiceOfferingID string `json:"serviceOfferingID,omitempty"`
+
+	// The URL of the web-based management UI of the instance, if the service provides one
+	// +optional
+	DashboardURL string `json:"dashboardURL,omitempty"`
+
+	// Indicates whether Service Manager considers the instance usable
+	// +optional
+	Usable *bool `json:"usable,omitempty"`
+
+	// The time the instance was created in Service Manager
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+
+	// The time the instance was last updated in Service Manager
+	// +optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+
+	// The last operation on the instance in Service Manager
+	// +optional
+	LastOperation *LastOperation `json:"lastOperation,omitempty"`
+
 	// if true need to update instance
 	ForceReconcile bool `json:"forceReconcile,omitempty"`
+
+	// The name of the service offering of the instance in Service Manager, resolved with planUpdatable
+	// +optional
+	ServiceOfferingName string `json:"serviceOfferingName,omitempty"`
+
+	// The name of the service plan of the instance in Service Manager, resolved with planUpdatable
+	// +optional
+	ServicePlanName string `json:"servicePlanName,omitempty"`
+
+	// Indicates whether the service plan of the instance can be changed, as defined by the service catalog
+	// +optional
+	PlanUpdatable *bool `json:"planUpdatable,omitempty"`
+
+	// The service plans of the service offering the instance can be changed to
+	// +optional
+	AllowedServicePlans []string `json:"allowedServicePlans,omitempty"`
+
+	// The maintenance info of the instance in Service Manager
+	// +optional
+	MaintenanceInfo *MaintenanceInfo `json:"maintenanceInfo,omitempty"`
+
+	// The current maintenance info of the service plan of the instance, the instance can be upgraded to it when it differs from maintenanceInfo
+	// +optional
+	PlanMaintenanceInfo *MaintenanceInfo `json:"planMaintenanceInfo,omitempty"`
+
+	// The last time the service plan and the maintenance info of the instance were checked in Service Manager
+	// +optional
+	LastPlanCheckTime *metav1.Time `json:"lastPlanCheckTime,omitempty"`
+
+	// The Service Manager labels synced from the labels and annotations of the instance
+	// +optional
+	SyncedLabels map[string]string `json:"syncedLabels,omitempty"`
+
+	// The latest operations on the instance in Service Manager, the oldest operation is removed when there are more than 10
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	Operations []Operation `json:"operations,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -141,6 +250,12 @@ type ServiceInstanceStatus struct {
 // +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name="Age",type=date
 // +kubebuilder:printcolumn:JSONPath=".status.instanceID",name="ID",type=string,priority=1
 // +kubebuilder:printcolumn:JSONPath=".status.conditions[0].message",name="Message",type=string,priority=1
+// +kubebuilder:printcolumn:JSONPath=".status.usable",name="Usable",type=boolean,priority=1
+// +kubebuilder:printcolumn:JSONPath=".status.servicePlanID",name="Plan ID",type=string,priority=1
+// +kubebuilder:printcolumn:JSONPath=".status.serviceOfferingID",name="Offering ID",type=string,priority=1
+// +kubebuilder:printcolumn:JSONPath=".status.lastOperation.type",name="Last Operation",type=string,priority=1
+// +kubebuilder:printcolumn:JSONPath=".status.lastOperation.state",name="Last Operation State",type=string,priority=1
+// +kubebuilder:printcolumn:JSONPath=".status.dashboardURL",name="Dashboard",type=string,priority=1
 
 // ServiceInstance is the Schema for the serviceinstances API
 type ServiceInstance struct {
@@ -193,6 +308,18 @@ func (si *ServiceInstance) SetAnnotations(annotations map[string]string) {
 	si.ObjectMeta.Annotations = annotations
 }
 
+func (si *ServiceInstance) GetOperations() []Operation {
+	return si.Status.Operations
+}
+
+func (si *ServiceInstance) SetOperations(operations []Operation) {
+	si.Status.Operations = operations
+}
+
+func (si *ServiceInstance) GetUserInfo() *v1.UserInfo {
+	return si.Spec.UserInfo
+}
+
 // +kubebuilder:object:root=true
 
 // ServiceInstanceList contains a list of ServiceInstance
@@ -19,33 +19,42 @@ package v1
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/SAP/sap-btp-service-operator/api/common"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 func (si *ServiceInstance) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).For(si).WithValidator(si).Complete()
+	return ctrl.NewWebhookManagedBy(mgr).For(si).WithValidator(&serviceInstanceValidator{client: mgr.GetClient()}).Complete()
 }
 
 // +kubebuilder:webhook:verbs=delete;update;create,path=/validate-services-cloud-sap-com-v1-serviceinstance,mutating=false,failurePolicy=fail,groups=services.cloud.sap.com,resources=serviceinstances,versions=v1,name=vserviceinstance.kb.io,sideEffects=None,admissionReviewVersions=v1beta1;v1
 
-var _ webhook.CustomValidator = &ServiceInstance{}
+// serviceInstanceValidator validates service instances, client reads the service policies
+type serviceInstanceValidator struct {
+	client client.Reader
+}
+
+var _ webhook.CustomValidator = &serviceInstanceValidator{}
 
 // log is for logging in this package.
 var serviceinstancelog = logf.Log.WithName("serviceinstance-resource")
 
-func (si *ServiceInstance) ValidateCreate(_ context.Context, _ runtime.Object) (warnings admission.Warnings, err error) {
-	return nil, nil
+func (v *serviceInstanceValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (warnings admission.Warnings, err error) {
+	newInstance := obj.(*ServiceInstance)
+	serviceinstancelog.Info("validate create", "name", newInstance.ObjectMeta.Name)
+	return nil, newInstance.validateServicePolicies(ctx, v.client, nil)
 }
 
-func (si *ServiceInstance) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (warnings admission.Warnings, err error) {
+func (v *serviceInstanceValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (warnings admission.Warnings, err error) {
 	oldInstance := oldObj.(*ServiceInstance)
 	newInstance := newObj.(*ServiceInstance)
 	serviceinstancelog.Info("validate update", "name", newInstance.ObjectMeta.Name)
@@ -53,10 +62,36 @@ func (si *ServiceInstance) ValidateUpdate(_ context.Context, oldObj, newObj runt
 	if oldInstance.Spec.BTPAccessCredentialsSecret != newInstance.Spec.BTPAccessCredentialsSecret {
 		return nil, fmt.Errorf("changing the btpAccessCredentialsSecret for an existing instance is not allowed")
 	}
-	return nil, nil
+	if err := newInstance.validateServicePlanChange(oldInstance); err != nil {
+		return nil, err
+	}
+	return nil, newInstance.validateServicePolicies(ctx, v.client, oldInstance)
+}
+
+// validateServicePlanChange rejects changing the service offering of an instance that was created,
+// and changing its service plan when the service catalog does not allow it
+func (si *ServiceInstance) validateServicePlanChange(oldInstance *ServiceInstance) error {
+	if len(oldInstance.Status.InstanceID) == 0 {
+		return nil
+	}
+	if oldInstance.Spec.ServiceOfferingName != si.Spec.ServiceOfferingName {
+		return fmt.Errorf("changing the serviceOfferingName for an existing instance is not allowed")
+	}
+
+	if oldInstance.Spec.ServicePlanName == si.Spec.ServicePlanName || oldInstance.Status.PlanUpdatable == nil {
+		return nil
+	}
+	if !*oldInstance.Status.PlanUpdatable {
+		return fmt.Errorf("the service plan %s of the service offering %s is not updatable", oldInstance.Spec.ServicePlanName, oldInstance.Spec.ServiceOfferingName)
+	}
+	if !slices.Contains(oldInstance.Status.AllowedServicePlans, si.Spec.ServicePlanName) {
+		return fmt.Errorf("changing the service plan from %s to %s is not allowed, the allowed service plans are %v",
+			oldInstance.Spec.ServicePlanName, si.Spec.ServicePlanName, oldInstance.Status.AllowedServicePlans)
+	}
+	return nil
 }
 
-func (si *ServiceInstance) ValidateDelete(_ context.Context, newObj runtime.Object) (warnings admission.Warnings, err error) {
+func (v *serviceInstanceValidator) ValidateDelete(_ context.Context, newObj runtime.Object) (warnings admission.Warnings, err error) {
 	newInstance := newObj.(*ServiceInstance)
 	serviceinstancelog.Info("validate delete", "name", newInstance.ObjectMeta.Name)
 	if newInstance.ObjectMeta.Annotations != nil {
@@ -1,15 +1,25 @@
 package v1
 
 import (
+	"context"
+
 	"github.com/SAP/sap-btp-service-operator/api/common"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = Describe("Service Instance Webhook Test", func() {
 	var instance *ServiceInstance
+	var validator *serviceInstanceValidator
 	BeforeEach(func() {
 		instance = getInstance()
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).To(Succeed())
+		validator = &serviceInstanceValidator{client: fake.NewClientBuilder().WithScheme(scheme).Build()}
 	})
 
 	Context("Validate Update", func() {
@@ -19,27 +29,198 @@ var _ = Describe("Service Instance Webhook Test", func() {
 				instance.Spec.BTPAccessCredentialsSecret = ""
 				newInstance := getInstance()
 				newInstance.Spec.BTPAccessCredentialsSecret = "new-secret"
-				_, err := newInstance.ValidateUpdate(nil, instance, newInstance)
+				_, err := validator.ValidateUpdate(nil, instance, newInstance)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("changing the btpAccessCredentialsSecret for an existing instance is not allowed"))
 			})
 		})
 	})
 
+	Context("Validate service plan change", func() {
+		var newInstance *ServiceInstance
+
+		BeforeEach(func() {
+			instance.Status.InstanceID = "instance-id"
+			instance.Status.PlanUpdatable = &[]bool{true}[0]
+			instance.Status.AllowedServicePlans = []string{"service-plan-name-2"}
+			newInstance = getInstance()
+		})
+
+		It("should allow changing to an allowed plan", func() {
+			newInstance.Spec.ServicePlanName = "service-plan-name-2"
+			_, err := validator.ValidateUpdate(nil, instance, newInstance)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject changing to a plan that is not allowed", func() {
+			newInstance.Spec.ServicePlanName = "service-plan-name-3"
+			_, err := validator.ValidateUpdate(nil, instance, newInstance)
+			Expect(err).To(MatchError("changing the service plan from service-plan-name-1 to service-plan-name-3 is not allowed, the allowed service plans are [service-plan-name-2]"))
+		})
+
+		It("should reject changing a plan that is not updatable", func() {
+			instance.Status.PlanUpdatable = &[]bool{false}[0]
+			newInstance.Spec.ServicePlanName = "service-plan-name-2"
+			_, err := validator.ValidateUpdate(nil, instance, newInstance)
+			Expect(err).To(MatchError("the service plan service-plan-name-1 of the service offering service-offering-1 is not updatable"))
+		})
+
+		It("should allow changing the plan if the allowed plans are unknown", func() {
+			instance.Status.PlanUpdatable = nil
+			newInstance.Spec.ServicePlanName = "service-plan-name-3"
+			_, err := validator.ValidateUpdate(nil, instance, newInstance)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject changing the offering", func() {
+			newInstance.Spec.ServiceOfferingName = "service-offering-2"
+			_, err := validator.ValidateUpdate(nil, instance, newInstance)
+			Expect(err).To(MatchError("changing the serviceOfferingName for an existing instance is not allowed"))
+		})
+
+		It("should allow changing the offering of an instance that was not created", func() {
+			instance.Status.InstanceID = ""
+			newInstance.Spec.ServiceOfferingName = "service-offering-2"
+			_, err := validator.ValidateUpdate(nil, instance, newInstance)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("Validate service policies", func() {
+		var policies []client.Object
+
+		newPolicy := func(spec ServicePolicySpec) *ServicePolicy {
+			return &ServicePolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: instance.Namespace}, Spec: spec}
+		}
+
+		newClusterPolicy := func(spec ServicePolicySpec) *ClusterServicePolicy {
+			return &ClusterServicePolicy{ObjectMeta: metav1.ObjectMeta{Name: "cluster-policy"}, Spec: spec}
+		}
+
+		JustBeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(AddToScheme(scheme)).To(Succeed())
+			validator.client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(policies...).Build()
+		})
+
+		AfterEach(func() {
+			policies = nil
+		})
+
+		When("the webhook client is not set", func() {
+			It("should reject the instance", func() {
+				validator.client = nil
+				_, err := validator.ValidateCreate(context.Background(), instance)
+				Expect(err).To(MatchError("failed to validate the service policies, the webhook client is not set"))
+			})
+		})
+
+		When("there are no policies", func() {
+			It("should allow the instance", func() {
+				_, err := validator.ValidateCreate(context.Background(), instance)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		When("the offering and the plan are allowed", func() {
+			BeforeEach(func() {
+				policies = []client.Object{newPolicy(ServicePolicySpec{
+					ServiceOfferingNames: PolicyRule{Allow: []string{"service-offering-1"}},
+					ServicePlanNames:     PolicyRule{Allow: []string{"service-offering-1/service-plan-name-1"}},
+					DataCenters:          PolicyRule{Deny: []string{"eu10"}},
+				})}
+			})
+
+			It("should allow the instance", func() {
+				_, err := validator.ValidateCreate(context.Background(), instance)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		When("the instance violates the policies", func() {
+			BeforeEach(func() {
+				policies = []client.Object{
+					newPolicy(ServicePolicySpec{ServicePlanNames: PolicyRule{Deny: []string{"service-plan-name-1"}}}),
+					newClusterPolicy(ServicePolicySpec{
+						ServiceOfferingNames: PolicyRule{Allow: []string{"other-offering"}},
+						DataCenters:          PolicyRule{Allow: []string{"eu10", "us10"}},
+					}),
+				}
+			})
+
+			It("should explain all the violations on create", func() {
+				_, err := validator.ValidateCreate(context.Background(), instance)
+				Expect(err).To(MatchError("service instance service-instance-1 is not allowed by the service policies: " +
+					"ServicePolicy namespace-1/policy denies service plan service-plan-name-1; " +
+					"ClusterServicePolicy cluster-policy does not allow service offering service-offering-1, the allowed values are [other-offering]; " +
+					"ClusterServicePolicy cluster-policy does not allow data center tel-aviv, the allowed values are [eu10 us10]"))
+			})
+
+			It("should require an allowed data center", func() {
+				instance.Spec.DataCenter = ""
+				_, err := validator.ValidateCreate(context.Background(), instance)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("ClusterServicePolicy cluster-policy requires spec.dataCenter to be one of the allowed data centers [eu10 us10]"))
+			})
+
+			It("should validate only the changed fields on update", func() {
+				newInstance := getInstance()
+				newInstance.Spec.ExternalName = "new-name"
+				_, err := validator.ValidateUpdate(context.Background(), instance, newInstance)
+				Expect(err).ToNot(HaveOccurred())
+
+				newInstance.Spec.DataCenter = "eu10"
+				_, err = validator.ValidateUpdate(context.Background(), instance, newInstance)
+				Expect(err).ToNot(HaveOccurred())
+
+				newInstance.Spec.ServicePlanName = "service-plan-name-2"
+				_, err = validator.ValidateUpdate(context.Background(), instance, newInstance)
+				Expect(err).ToNot(HaveOccurred())
+
+				newInstance.Spec.ServicePlanName = "service-plan-name-1"
+				oldInstance := newInstance.DeepCopy()
+				oldInstance.Spec.ServicePlanName = "service-plan-name-2"
+				_, err = validator.ValidateUpdate(context.Background(), oldInstance, newInstance)
+				Expect(err).To(MatchError("service instance service-instance-1 is not allowed by the service policies: " +
+					"ServicePolicy namespace-1/policy denies service plan service-plan-name-1"))
+			})
+		})
+
+		When("the namespace has the maximum number of instances", func() {
+			BeforeEach(func() {
+				other := getInstance()
+				other.Name = "service-instance-2"
+				policies = []client.Object{other, newClusterPolicy(ServicePolicySpec{MaxInstancesPerNamespace: &[]int32{1}[0]})}
+			})
+
+			It("should fail on create", func() {
+				_, err := validator.ValidateCreate(context.Background(), instance)
+				Expect(err).To(MatchError("service instance service-instance-1 is not allowed by the service policies: " +
+					"ClusterServicePolicy cluster-policy allows at most 1 service instances in namespace namespace-1, which already has 1"))
+			})
+
+			It("should allow an instance in another namespace", func() {
+				instance.Namespace = "namespace-2"
+				_, err := validator.ValidateCreate(context.Background(), instance)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+	})
+
 	Context("Validate Delete", func() {
 		When("service instance is marked as prevent deletion", func() {
 			It("should return error from webhook", func() {
 				instance.Annotations = map[string]string{
 					common.PreventDeletion: "true",
 				}
-				_, err := instance.ValidateDelete(nil, instance)
+				_, err := validator.ValidateDelete(nil, instance)
 				Expect(err).To(HaveOccurred())
 			})
 		})
 
 		When("service instance is not marked as prevent deletion", func() {
 			It("should not return error from webhook", func() {
-				_, err := instance.ValidateDelete(nil, instance)
+				_, err := validator.ValidateDelete(nil, instance)
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
@@ -49,7 +230,7 @@ var _ = Describe("Service Instance Webhook Test", func() {
 				instance.Annotations = map[string]string{
 					common.PreventDeletion: "not-true",
 				}
-				_, err := instance.ValidateDelete(nil, instance)
+				_, err := validator.ValidateDelete(nil, instance)
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
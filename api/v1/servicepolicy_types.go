@@ -0,0 +1,105 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ServicePolicyKind        = "ServicePolicy"
+	ClusterServicePolicyKind = "ClusterServicePolicy"
+)
+
+// PolicyRule allows or denies values of a service instance field
+type PolicyRule struct {
+	// Allow lists the allowed values, all values are allowed if it is empty
+	// +optional
+	Allow []string `json:"allow,omitempty"`
+
+	// Deny lists the denied values, it takes precedence over allow
+	// +optional
+	Deny []string `json:"deny,omitempty"`
+}
+
+// ServicePolicySpec defines the service instances that can be created
+type ServicePolicySpec struct {
+	// ServiceOfferingNames restricts spec.serviceOfferingName of the service instances
+	// +optional
+	ServiceOfferingNames PolicyRule `json:"serviceOfferingNames,omitempty"`
+
+	// ServicePlanNames restricts spec.servicePlanName of the service instances,
+	// a value can be a plan name or <offering name>/<plan name> for the plan of a specific offering
+	// +optional
+	ServicePlanNames PolicyRule `json:"servicePlanNames,omitempty"`
+
+	// DataCenters restricts spec.dataCenter of the service instances
+	// +optional
+	DataCenters PolicyRule `json:"dataCenters,omitempty"`
+
+	// MaxInstancesPerNamespace is the maximum number of service instances in a namespace
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxInstancesPerNamespace *int32 `json:"maxInstancesPerNamespace,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:JSONPath=".spec.maxInstancesPerNamespace",name="Max Instances",type=integer
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name="Age",type=date
+
+// ServicePolicy is the Schema for the servicepolicies API, it restricts the service instances of its namespace
+type ServicePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ServicePolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ServicePolicyList contains a list of ServicePolicy
+type ServicePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServicePolicy `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:JSONPath=".spec.maxInstancesPerNamespace",name="Max Instances",type=integer
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name="Age",type=date
+
+// ClusterServicePolicy is the Schema for the clusterservicepolicies API, it restricts the service instances of all namespaces
+type ClusterServicePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ServicePolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterServicePolicyList contains a list of ClusterServicePolicy
+type ClusterServicePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterServicePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ServicePolicy{}, &ServicePolicyList{}, &ClusterServicePolicy{}, &ClusterServicePolicyList{})
+}
@@ -0,0 +1,146 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// validateServicePolicies returns an error that explains all the violations of the service policies of the namespace and the cluster,
+// on update only the changed fields are validated and the maximum number of instances is not
+func (si *ServiceInstance) validateServicePolicies(ctx context.Context, reader client.Reader, oldInstance *ServiceInstance) error {
+	if reader == nil {
+		return fmt.Errorf("failed to validate the service policies, the webhook client is not set")
+	}
+	if !si.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if oldInstance != nil && oldInstance.Spec.ServiceOfferingName == si.Spec.ServiceOfferingName &&
+		oldInstance.Spec.ServicePlanName == si.Spec.ServicePlanName && oldInstance.Spec.DataCenter == si.Spec.DataCenter {
+		return nil
+	}
+
+	policies := &ServicePolicyList{}
+	if err := reader.List(ctx, policies, client.InNamespace(si.Namespace)); err != nil {
+		return fmt.Errorf("failed to list the service policies: %w", err)
+	}
+	clusterPolicies := &ClusterServicePolicyList{}
+	if err := reader.List(ctx, clusterPolicies); err != nil {
+		return fmt.Errorf("failed to list the cluster service policies: %w", err)
+	}
+
+	checker := &policyChecker{reader: reader, instance: si, oldInstance: oldInstance}
+	for _, policy := range policies.Items {
+		if err := checker.check(ctx, fmt.Sprintf("%s %s/%s", ServicePolicyKind, policy.Namespace, policy.Name), policy.Spec); err != nil {
+			return err
+		}
+	}
+	for _, policy := range clusterPolicies.Items {
+		if err := checker.check(ctx, fmt.Sprintf("%s %s", ClusterServicePolicyKind, policy.Name), policy.Spec); err != nil {
+			return err
+		}
+	}
+
+	if len(checker.violations) > 0 {
+		return fmt.Errorf("service instance %s is not allowed by the service policies: %s", si.Name, strings.Join(checker.violations, "; "))
+	}
+	return nil
+}
+
+type policyChecker struct {
+	reader      client.Reader
+	instance    *ServiceInstance
+	oldInstance *ServiceInstance
+	violations  []string
+	// instanceCount is the number of the other service instances of the namespace, nil until they are counted
+	instanceCount *int
+}
+
+func (c *policyChecker) check(ctx context.Context, policyName string, spec ServicePolicySpec) error {
+	instanceSpec := c.instance.Spec
+	isCreate := c.oldInstance == nil
+	offeringChanged := isCreate || c.oldInstance.Spec.ServiceOfferingName != instanceSpec.ServiceOfferingName
+	planChanged := offeringChanged || c.oldInstance.Spec.ServicePlanName != instanceSpec.ServicePlanName
+	dataCenterChanged := isCreate || c.oldInstance.Spec.DataCenter != instanceSpec.DataCenter
+
+	if offeringChanged {
+		c.checkRule(policyName, "service offering", instanceSpec.ServiceOfferingName, spec.ServiceOfferingNames, func(value string) bool {
+			return value == instanceSpec.ServiceOfferingName
+		})
+	}
+	if planChanged {
+		c.checkRule(policyName, "service plan", instanceSpec.ServicePlanName, spec.ServicePlanNames, func(value string) bool {
+			return value == instanceSpec.ServicePlanName || value == instanceSpec.ServiceOfferingName+"/"+instanceSpec.ServicePlanName
+		})
+	}
+	if dataCenterChanged {
+		if len(instanceSpec.DataCenter) == 0 {
+			if len(spec.DataCenters.Allow) > 0 {
+				c.violations = append(c.violations, fmt.Sprintf("%s requires spec.dataCenter to be one of the allowed data centers %v", policyName, spec.DataCenters.Allow))
+			}
+		} else {
+			c.checkRule(policyName, "data center", instanceSpec.DataCenter, spec.DataCenters, func(value string) bool {
+				return value == instanceSpec.DataCenter
+			})
+		}
+	}
+
+	if isCreate && spec.MaxInstancesPerNamespace != nil {
+		count, err := c.countInstances(ctx)
+		if err != nil {
+			return err
+		}
+		if count >= int(*spec.MaxInstancesPerNamespace) {
+			c.violations = append(c.violations, fmt.Sprintf("%s allows at most %d service instances in namespace %s, which already has %d",
+				policyName, *spec.MaxInstancesPerNamespace, c.instance.Namespace, count))
+		}
+	}
+	return nil
+}
+
+// checkRule adds a violation if the rule denies the value of a field or does not allow it
+func (c *policyChecker) checkRule(policyName, field, value string, rule PolicyRule, matches func(string) bool) {
+	if slices.ContainsFunc(rule.Deny, matches) {
+		c.violations = append(c.violations, fmt.Sprintf("%s denies %s %s", policyName, field, value))
+	} else if len(rule.Allow) > 0 && !slices.ContainsFunc(rule.Allow, matches) {
+		c.violations = append(c.violations, fmt.Sprintf("%s does not allow %s %s, the allowed values are %v", policyName, field, value, rule.Allow))
+	}
+}
+
+// countInstances returns the number of the other service instances of the namespace that are not being deleted
+func (c *policyChecker) countInstances(ctx context.Context) (int, error) {
+	if c.instanceCount != nil {
+		return *c.instanceCount, nil
+	}
+	instances := &ServiceInstanceList{}
+	if err := c.reader.List(ctx, instances, client.InNamespace(c.instance.Namespace)); err != nil {
+		return 0, fmt.Errorf("failed to list the service instances: %w", err)
+	}
+	count := 0
+	for _, instance := range instances.Items {
+		if instance.Name != c.instance.Name && instance.DeletionTimestamp.IsZero() {
+			count++
+		}
+	}
+	c.instanceCount = &count
+	return count, nil
+}
@@ -1,5 +1,13 @@
 package v1
 
+import (
+	"github.com/SAP/sap-btp-service-operator/client/sm/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaxOperations is the number of operations kept in the operations history of a resource
+const MaxOperations = 10
+
 // ParametersFromSource represents the source of a set of Parameters
 type ParametersFromSource struct {
 	// The Secret key to select from.
@@ -14,3 +22,31 @@ type SecretKeyReference struct {
 	// The key of the secret to select from.  Must be a valid secret key.
 	Key string `json:"key"`
 }
+
+// Operation is an operation of Service Manager on a resource
+type Operation struct {
+	// The type of the operation (CREATE/UPDATE/DELETE)
+	Type types.OperationCategory `json:"type"`
+
+	// The ID of the operation in Service Manager, synchronous operations have no ID
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// The state of the operation
+	State types.OperationState `json:"state"`
+
+	// The time the operation was started
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// The time the operation reached its final state
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// The error description of a failed operation
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// The user that originated the operation
+	// +optional
+	User string `json:"user,omitempty"`
+}
@@ -0,0 +1,78 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// alphaFields holds the v1alpha1 fields that have no v1 counterpart, it is kept on v1 objects
+// so that v1alpha1 clients get them back unchanged
+type alphaFields struct {
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// storeFields keeps fields in the annotation, the annotation is removed when fields hold only zero values
+func storeFields(objectMeta *metav1.ObjectMeta, annotation string, fields interface{}, empty bool) error {
+	if empty {
+		delete(objectMeta.Annotations, annotation)
+		if len(objectMeta.Annotations) == 0 {
+			objectMeta.Annotations = nil
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	if objectMeta.Annotations == nil {
+		objectMeta.Annotations = make(map[string]string)
+	}
+	objectMeta.Annotations[annotation] = string(data)
+	return nil
+}
+
+// restoreFields reads fields from the annotation and removes it
+func restoreFields(objectMeta *metav1.ObjectMeta, annotation string, fields interface{}) error {
+	data, ok := objectMeta.Annotations[annotation]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(data), fields); err != nil {
+		return fmt.Errorf("failed to parse annotation %s: %w", annotation, err)
+	}
+	delete(objectMeta.Annotations, annotation)
+	if len(objectMeta.Annotations) == 0 {
+		objectMeta.Annotations = nil
+	}
+	return nil
+}
+
+func convertParametersFromToV1(src []ParametersFromSource) []v1.ParametersFromSource {
+	if src == nil {
+		return nil
+	}
+	dst := make([]v1.ParametersFromSource, len(src))
+	for i, param := range src {
+		if param.SecretKeyRef != nil {
+			dst[i].SecretKeyRef = &v1.SecretKeyReference{Name: param.SecretKeyRef.Name, Key: param.SecretKeyRef.Key}
+		}
+	}
+	return dst
+}
+
+func convertParametersFromFromV1(src []v1.ParametersFromSource) []ParametersFromSource {
+	if src == nil {
+		return nil
+	}
+	dst := make([]ParametersFromSource, len(src))
+	for i, param := range src {
+		if param.SecretKeyRef != nil {
+			dst[i].SecretKeyRef = &SecretKeyReference{Name: param.SecretKeyRef.Name, Key: param.SecretKeyRef.Key}
+		}
+	}
+	return dst
+}
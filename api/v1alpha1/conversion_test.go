@@ -0,0 +1,143 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/randfill"
+)
+
+const fuzzIterations = 500
+
+var _ = Describe("Conversion", func() {
+	var filler *randfill.Filler
+
+	BeforeEach(func() {
+		filler = randfill.New().NilChance(0.2).NumElements(0, 3).Funcs(
+			func(typeMeta *metav1.TypeMeta, c randfill.Continue) {
+				*typeMeta = metav1.TypeMeta{}
+			},
+			func(raw *runtime.RawExtension, c randfill.Continue) {
+				*raw = runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"key":%q}`, c.String(10)))}
+			},
+		)
+	})
+
+	expectRoundTrip := func(original, back runtime.Object) {
+		ExpectWithOffset(1, apiequality.Semantic.DeepEqual(original, back)).To(BeTrue(), diff.ObjectReflectDiff(original, back))
+	}
+
+	spokeRoundTrip := func(newSpoke func() conversion.Convertible, newHub func() conversion.Hub) {
+		for i := 0; i < fuzzIterations; i++ {
+			spoke, hub, back := newSpoke(), newHub(), newSpoke()
+			filler.Fill(spoke)
+			Expect(spoke.ConvertTo(hub)).To(Succeed())
+			Expect(back.ConvertFrom(hub)).To(Succeed())
+			expectRoundTrip(spoke, back)
+		}
+	}
+
+	hubRoundTrip := func(newSpoke func() conversion.Convertible, newHub func() conversion.Hub) {
+		for i := 0; i < fuzzIterations; i++ {
+			hub, spoke, back := newHub(), newSpoke(), newHub()
+			filler.Fill(hub)
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			Expect(spoke.ConvertTo(back)).To(Succeed())
+			expectRoundTrip(hub, back)
+		}
+	}
+
+	Context("ServiceInstance", func() {
+		newSpoke := func() conversion.Convertible { return &ServiceInstance{} }
+		newHub := func() conversion.Hub { return &v1.ServiceInstance{} }
+
+		It("should round trip v1alpha1 -> v1 -> v1alpha1", func() {
+			spokeRoundTrip(newSpoke, newHub)
+		})
+
+		It("should round trip v1 -> v1alpha1 -> v1", func() {
+			hubRoundTrip(newSpoke, newHub)
+		})
+
+		It("should keep the v1 only fields in an annotation", func() {
+			hub := &v1.ServiceInstance{Spec: v1.ServiceInstanceSpec{
+				ServiceOfferingName:        "offering",
+				ServicePlanName:            "plan",
+				DataCenter:                 "eu10",
+				BTPAccessCredentialsSecret: "my-secret",
+			}}
+			spoke := &ServiceInstance{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			Expect(spoke.Spec.ServiceOfferingName).To(Equal("offering"))
+			Expect(spoke.Annotations[common.V1FieldsAnnotation]).To(MatchJSON(`{"dataCenter":"eu10","btpAccessCredentialsSecret":"my-secret"}`))
+
+			converted := &v1.ServiceInstance{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+			Expect(converted.Spec.DataCenter).To(Equal("eu10"))
+			Expect(converted.Spec.BTPAccessCredentialsSecret).To(Equal("my-secret"))
+			Expect(converted.Annotations).To(BeEmpty())
+		})
+
+		It("should not add annotations when there are no version specific fields", func() {
+			spoke := getInstance()
+			hub := &v1.ServiceInstance{}
+			Expect(spoke.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Annotations).To(BeEmpty())
+			Expect(hub.Spec.ServicePlanName).To(Equal(spoke.Spec.ServicePlanName))
+		})
+
+		It("should fail on a malformed annotation", func() {
+			spoke := getInstance()
+			spoke.Annotations = map[string]string{common.V1FieldsAnnotation: "{"}
+			Expect(spoke.ConvertTo(&v1.ServiceInstance{})).To(MatchError(ContainSubstring(common.V1FieldsAnnotation)))
+		})
+	})
+
+	Context("ServiceBinding", func() {
+		newSpoke := func() conversion.Convertible { return &ServiceBinding{} }
+		newHub := func() conversion.Hub { return &v1.ServiceBinding{} }
+
+		It("should round trip v1alpha1 -> v1 -> v1alpha1", func() {
+			spokeRoundTrip(newSpoke, newHub)
+		})
+
+		It("should round trip v1 -> v1alpha1 -> v1", func() {
+			hubRoundTrip(newSpoke, newHub)
+		})
+
+		It("should keep the v1 only fields in an annotation", func() {
+			hub := &v1.ServiceBinding{Spec: v1.ServiceBindingSpec{
+				ServiceInstanceName:      "instance",
+				ServiceInstanceNamespace: "other-namespace",
+				SecretTemplate:           "{{ .credentials }}",
+			}}
+			spoke := &ServiceBinding{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			Expect(spoke.Spec.ServiceInstanceName).To(Equal("instance"))
+			Expect(spoke.Annotations[common.V1FieldsAnnotation]).To(MatchJSON(`{"serviceInstanceNamespace":"other-namespace","secretTemplate":"{{ .credentials }}"}`))
+
+			converted := &v1.ServiceBinding{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+			Expect(converted.Spec.ServiceInstanceNamespace).To(Equal("other-namespace"))
+			Expect(converted.Spec.SecretTemplate).To(Equal("{{ .credentials }}"))
+			Expect(converted.Annotations).To(BeEmpty())
+		})
+
+		It("should keep the v1alpha1 observed generation on the v1 object", func() {
+			spoke := getBinding()
+			spoke.Status.ObservedGeneration = 3
+			hub := &v1.ServiceBinding{}
+			Expect(spoke.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Annotations[common.V1Alpha1FieldsAnnotation]).To(MatchJSON(`{"observedGeneration":3}`))
+			Expect(hub.Spec.CredRotationPolicy.RotationFrequency).To(Equal("1s"))
+		})
+	})
+})
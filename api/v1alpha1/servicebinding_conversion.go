@@ -0,0 +1,156 @@
+package v1alpha1
+
+import (
+	"reflect"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// bindingV1Fields holds the ServiceBinding v1 fields that have no v1alpha1 counterpart
+type bindingV1Fields struct {
+	ServiceInstanceNamespace string                      `json:"serviceInstanceNamespace,omitempty"`
+	SecretTemplate           string                      `json:"secretTemplate,omitempty"`
+	SecretTemplateRef        *v1.SecretTemplateReference `json:"secretTemplateRef,omitempty"`
+	SecretFormat             *v1.SecretFormat            `json:"secretFormat,omitempty"`
+	ServiceBindingIO         *v1.ServiceBindingIO        `json:"serviceBindingIO,omitempty"`
+	SecretDriftPolicy        string                      `json:"secretDriftPolicy,omitempty"`
+	SecretSink               string                      `json:"secretSink,omitempty"`
+	ExternalSecretPath       string                      `json:"externalSecretPath,omitempty"`
+	SubaccountID             string                      `json:"subaccountID,omitempty"`
+	Binding                  *v1.BindingSecretReference  `json:"binding,omitempty"`
+	RolloutWorkloads         []v1.WorkloadReference      `json:"rolloutWorkloads,omitempty"`
+	RotationSchedule         string                      `json:"rotationSchedule,omitempty"`
+	RotationTimeZone         string                      `json:"rotationTimeZone,omitempty"`
+	MaintenanceWindows       []v1.MaintenanceWindow      `json:"maintenanceWindows,omitempty"`
+	NextRotationTime         *metav1.Time                `json:"nextCredentialsRotationTime,omitempty"`
+	SyncedLabels             map[string]string           `json:"syncedLabels,omitempty"`
+	Operations               []v1.Operation              `json:"operations,omitempty"`
+}
+
+var _ conversion.Convertible = &ServiceBinding{}
+
+// ConvertTo converts this ServiceBinding to the Hub version (v1)
+func (in *ServiceBinding) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*v1.ServiceBinding)
+	src := in.DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1.ServiceBindingSpec{
+		ServiceInstanceName: src.Spec.ServiceInstanceName,
+		ExternalName:        src.Spec.ExternalName,
+		SecretName:          src.Spec.SecretName,
+		SecretKey:           src.Spec.SecretKey,
+		SecretRootKey:       src.Spec.SecretRootKey,
+		Parameters:          src.Spec.Parameters,
+		ParametersFrom:      convertParametersFromToV1(src.Spec.ParametersFrom),
+		UserInfo:            src.Spec.UserInfo,
+	}
+	if src.Spec.CredRotationPolicy != nil {
+		dst.Spec.CredRotationPolicy = &v1.CredentialsRotationPolicy{
+			Enabled:           src.Spec.CredRotationPolicy.Enabled,
+			RotationFrequency: src.Spec.CredRotationPolicy.RotationFrequency,
+			RotatedBindingTTL: src.Spec.CredRotationPolicy.RotatedBindingTTL,
+		}
+	}
+	dst.Status = v1.ServiceBindingStatus{
+		InstanceID:                  src.Status.InstanceID,
+		BindingID:                   src.Status.BindingID,
+		OperationURL:                src.Status.OperationURL,
+		OperationType:               src.Status.OperationType,
+		Conditions:                  src.Status.Conditions,
+		Ready:                       src.Status.Ready,
+		LastCredentialsRotationTime: src.Status.LastCredentialsRotationTime,
+	}
+
+	fields := bindingV1Fields{}
+	if err := restoreFields(&dst.ObjectMeta, common.V1FieldsAnnotation, &fields); err != nil {
+		return err
+	}
+	dst.Spec.ServiceInstanceNamespace = fields.ServiceInstanceNamespace
+	dst.Spec.SecretTemplate = fields.SecretTemplate
+	dst.Spec.SecretTemplateRef = fields.SecretTemplateRef
+	dst.Spec.SecretFormat = fields.SecretFormat
+	dst.Spec.ServiceBindingIO = fields.ServiceBindingIO
+	dst.Spec.SecretDriftPolicy = fields.SecretDriftPolicy
+	dst.Spec.SecretSink = fields.SecretSink
+	dst.Status.ExternalSecretPath = fields.ExternalSecretPath
+	dst.Status.SubaccountID = fields.SubaccountID
+	dst.Status.Binding = fields.Binding
+	dst.Status.NextCredentialsRotationTime = fields.NextRotationTime
+	dst.Status.SyncedLabels = fields.SyncedLabels
+	dst.Status.Operations = fields.Operations
+	if dst.Spec.CredRotationPolicy != nil {
+		dst.Spec.CredRotationPolicy.RolloutWorkloads = fields.RolloutWorkloads
+		dst.Spec.CredRotationPolicy.Schedule = fields.RotationSchedule
+		dst.Spec.CredRotationPolicy.TimeZone = fields.RotationTimeZone
+		dst.Spec.CredRotationPolicy.MaintenanceWindows = fields.MaintenanceWindows
+	}
+
+	alpha := alphaFields{ObservedGeneration: src.Status.ObservedGeneration}
+	return storeFields(&dst.ObjectMeta, common.V1Alpha1FieldsAnnotation, alpha, alpha == alphaFields{})
+}
+
+// ConvertFrom converts from the Hub version (v1) to this version
+func (in *ServiceBinding) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*v1.ServiceBinding).DeepCopy()
+
+	in.ObjectMeta = src.ObjectMeta
+	in.Spec = ServiceBindingSpec{
+		ServiceInstanceName: src.Spec.ServiceInstanceName,
+		ExternalName:        src.Spec.ExternalName,
+		SecretName:          src.Spec.SecretName,
+		SecretKey:           src.Spec.SecretKey,
+		SecretRootKey:       src.Spec.SecretRootKey,
+		Parameters:          src.Spec.Parameters,
+		ParametersFrom:      convertParametersFromFromV1(src.Spec.ParametersFrom),
+		UserInfo:            src.Spec.UserInfo,
+	}
+	if src.Spec.CredRotationPolicy != nil {
+		in.Spec.CredRotationPolicy = &CredentialsRotationPolicy{
+			Enabled:           src.Spec.CredRotationPolicy.Enabled,
+			RotationFrequency: src.Spec.CredRotationPolicy.RotationFrequency,
+			RotatedBindingTTL: src.Spec.CredRotationPolicy.RotatedBindingTTL,
+		}
+	}
+	in.Status = ServiceBindingStatus{
+		InstanceID:                  src.Status.InstanceID,
+		BindingID:                   src.Status.BindingID,
+		OperationURL:                src.Status.OperationURL,
+		OperationType:               src.Status.OperationType,
+		Conditions:                  src.Status.Conditions,
+		Ready:                       src.Status.Ready,
+		LastCredentialsRotationTime: src.Status.LastCredentialsRotationTime,
+	}
+
+	alpha := alphaFields{}
+	if err := restoreFields(&in.ObjectMeta, common.V1Alpha1FieldsAnnotation, &alpha); err != nil {
+		return err
+	}
+	in.Status.ObservedGeneration = alpha.ObservedGeneration
+
+	fields := bindingV1Fields{
+		ServiceInstanceNamespace: src.Spec.ServiceInstanceNamespace,
+		SecretTemplate:           src.Spec.SecretTemplate,
+		SecretTemplateRef:        src.Spec.SecretTemplateRef,
+		SecretFormat:             src.Spec.SecretFormat,
+		ServiceBindingIO:         src.Spec.ServiceBindingIO,
+		SecretDriftPolicy:        src.Spec.SecretDriftPolicy,
+		SecretSink:               src.Spec.SecretSink,
+		ExternalSecretPath:       src.Status.ExternalSecretPath,
+		SubaccountID:             src.Status.SubaccountID,
+		Binding:                  src.Status.Binding,
+		NextRotationTime:         src.Status.NextCredentialsRotationTime,
+		SyncedLabels:             src.Status.SyncedLabels,
+		Operations:               src.Status.Operations,
+	}
+	if src.Spec.CredRotationPolicy != nil {
+		fields.RolloutWorkloads = src.Spec.CredRotationPolicy.RolloutWorkloads
+		fields.RotationSchedule = src.Spec.CredRotationPolicy.Schedule
+		fields.RotationTimeZone = src.Spec.CredRotationPolicy.TimeZone
+		fields.MaintenanceWindows = src.Spec.CredRotationPolicy.MaintenanceWindows
+	}
+	return storeFields(&in.ObjectMeta, common.V1FieldsAnnotation, fields, reflect.DeepEqual(fields, bindingV1Fields{}))
+}
@@ -0,0 +1,159 @@
+package v1alpha1
+
+import (
+	"reflect"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// instanceV1Fields holds the ServiceInstance v1 fields that have no v1alpha1 counterpart
+type instanceV1Fields struct {
+	DataCenter                 string              `json:"dataCenter,omitempty"`
+	WatchParametersFromChanges *bool               `json:"watchParametersFromChanges,omitempty"`
+	BTPAccessCredentialsSecret string              `json:"btpAccessCredentialsSecret,omitempty"`
+	MaintenanceInfo            *v1.MaintenanceInfo `json:"maintenanceInfo,omitempty"`
+	UpgradePolicy              string              `json:"upgradePolicy,omitempty"`
+	HashedSpec                 string              `json:"hashedSpec,omitempty"`
+	SubaccountID               string              `json:"subaccountID,omitempty"`
+	StatusServicePlanID        string              `json:"statusServicePlanID,omitempty"`
+	ServiceOfferingID          string              `json:"serviceOfferingID,omitempty"`
+	DashboardURL               string              `json:"dashboardURL,omitempty"`
+	Usable                     *bool               `json:"usable,omitempty"`
+	CreatedAt                  *metav1.Time        `json:"createdAt,omitempty"`
+	UpdatedAt                  *metav1.Time        `json:"updatedAt,omitempty"`
+	LastOperation              *v1.LastOperation   `json:"lastOperation,omitempty"`
+	ForceReconcile             bool                `json:"forceReconcile,omitempty"`
+	StatusServiceOfferingName  string              `json:"statusServiceOfferingName,omitempty"`
+	StatusServicePlanName      string              `json:"statusServicePlanName,omitempty"`
+	PlanUpdatable              *bool               `json:"planUpdatable,omitempty"`
+	AllowedServicePlans        []string            `json:"allowedServicePlans,omitempty"`
+	StatusMaintenanceInfo      *v1.MaintenanceInfo `json:"statusMaintenanceInfo,omitempty"`
+	PlanMaintenanceInfo        *v1.MaintenanceInfo `json:"planMaintenanceInfo,omitempty"`
+	LastPlanCheckTime          *metav1.Time        `json:"lastPlanCheckTime,omitempty"`
+	SyncedLabels               map[string]string   `json:"syncedLabels,omitempty"`
+	Operations                 []v1.Operation      `json:"operations,omitempty"`
+}
+
+var _ conversion.Convertible = &ServiceInstance{}
+
+// ConvertTo converts this ServiceInstance to the Hub version (v1)
+func (in *ServiceInstance) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*v1.ServiceInstance)
+	src := in.DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1.ServiceInstanceSpec{
+		ServiceOfferingName: src.Spec.ServiceOfferingName,
+		ServicePlanName:     src.Spec.ServicePlanName,
+		ServicePlanID:       src.Spec.ServicePlanID,
+		ExternalName:        src.Spec.ExternalName,
+		Shared:              src.Spec.Shared,
+		Parameters:          src.Spec.Parameters,
+		ParametersFrom:      convertParametersFromToV1(src.Spec.ParametersFrom),
+		CustomTags:          src.Spec.CustomTags,
+		UserInfo:            src.Spec.UserInfo,
+	}
+	dst.Status = v1.ServiceInstanceStatus{
+		InstanceID:    src.Status.InstanceID,
+		Tags:          src.Status.Tags,
+		OperationURL:  src.Status.OperationURL,
+		OperationType: src.Status.OperationType,
+		Conditions:    src.Status.Conditions,
+		Ready:         src.Status.Ready,
+	}
+
+	fields := instanceV1Fields{}
+	if err := restoreFields(&dst.ObjectMeta, common.V1FieldsAnnotation, &fields); err != nil {
+		return err
+	}
+	dst.Spec.DataCenter = fields.DataCenter
+	dst.Spec.WatchParametersFromChanges = fields.WatchParametersFromChanges
+	dst.Spec.BTPAccessCredentialsSecret = fields.BTPAccessCredentialsSecret
+	dst.Spec.MaintenanceInfo = fields.MaintenanceInfo
+	dst.Spec.UpgradePolicy = fields.UpgradePolicy
+	dst.Status.HashedSpec = fields.HashedSpec
+	dst.Status.SubaccountID = fields.SubaccountID
+	dst.Status.ServicePlanID = fields.StatusServicePlanID
+	dst.Status.ServiceOfferingID = fields.ServiceOfferingID
+	dst.Status.DashboardURL = fields.DashboardURL
+	dst.Status.Usable = fields.Usable
+	dst.Status.CreatedAt = fields.CreatedAt
+	dst.Status.UpdatedAt = fields.UpdatedAt
+	dst.Status.LastOperation = fields.LastOperation
+	dst.Status.ForceReconcile = fields.ForceReconcile
+	dst.Status.ServiceOfferingName = fields.StatusServiceOfferingName
+	dst.Status.ServicePlanName = fields.StatusServicePlanName
+	dst.Status.PlanUpdatable = fields.PlanUpdatable
+	dst.Status.AllowedServicePlans = fields.AllowedServicePlans
+	dst.Status.MaintenanceInfo = fields.StatusMaintenanceInfo
+	dst.Status.PlanMaintenanceInfo = fields.PlanMaintenanceInfo
+	dst.Status.LastPlanCheckTime = fields.LastPlanCheckTime
+	dst.Status.SyncedLabels = fields.SyncedLabels
+	dst.Status.Operations = fields.Operations
+
+	alpha := alphaFields{ObservedGeneration: src.Status.ObservedGeneration}
+	return storeFields(&dst.ObjectMeta, common.V1Alpha1FieldsAnnotation, alpha, alpha == alphaFields{})
+}
+
+// ConvertFrom converts from the Hub version (v1) to this version
+func (in *ServiceInstance) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*v1.ServiceInstance).DeepCopy()
+
+	in.ObjectMeta = src.ObjectMeta
+	in.Spec = ServiceInstanceSpec{
+		ServiceOfferingName: src.Spec.ServiceOfferingName,
+		ServicePlanName:     src.Spec.ServicePlanName,
+		ServicePlanID:       src.Spec.ServicePlanID,
+		ExternalName:        src.Spec.ExternalName,
+		Shared:              src.Spec.Shared,
+		Parameters:          src.Spec.Parameters,
+		ParametersFrom:      convertParametersFromFromV1(src.Spec.ParametersFrom),
+		CustomTags:          src.Spec.CustomTags,
+		UserInfo:            src.Spec.UserInfo,
+	}
+	in.Status = ServiceInstanceStatus{
+		InstanceID:    src.Status.InstanceID,
+		Tags:          src.Status.Tags,
+		OperationURL:  src.Status.OperationURL,
+		OperationType: src.Status.OperationType,
+		Conditions:    src.Status.Conditions,
+		Ready:         src.Status.Ready,
+	}
+
+	alpha := alphaFields{}
+	if err := restoreFields(&in.ObjectMeta, common.V1Alpha1FieldsAnnotation, &alpha); err != nil {
+		return err
+	}
+	in.Status.ObservedGeneration = alpha.ObservedGeneration
+
+	fields := instanceV1Fields{
+		DataCenter:                 src.Spec.DataCenter,
+		WatchParametersFromChanges: src.Spec.WatchParametersFromChanges,
+		BTPAccessCredentialsSecret: src.Spec.BTPAccessCredentialsSecret,
+		MaintenanceInfo:            src.Spec.MaintenanceInfo,
+		UpgradePolicy:              src.Spec.UpgradePolicy,
+		HashedSpec:                 src.Status.HashedSpec,
+		SubaccountID:               src.Status.SubaccountID,
+		StatusServicePlanID:        src.Status.ServicePlanID,
+		ServiceOfferingID:          src.Status.ServiceOfferingID,
+		DashboardURL:               src.Status.DashboardURL,
+		Usable:                     src.Status.Usable,
+		CreatedAt:                  src.Status.CreatedAt,
+		UpdatedAt:                  src.Status.UpdatedAt,
+		LastOperation:              src.Status.LastOperation,
+		ForceReconcile:             src.Status.ForceReconcile,
+		StatusServiceOfferingName:  src.Status.ServiceOfferingName,
+		StatusServicePlanName:      src.Status.ServicePlanName,
+		PlanUpdatable:              src.Status.PlanUpdatable,
+		AllowedServicePlans:        src.Status.AllowedServicePlans,
+		StatusMaintenanceInfo:      src.Status.MaintenanceInfo,
+		PlanMaintenanceInfo:        src.Status.PlanMaintenanceInfo,
+		LastPlanCheckTime:          src.Status.LastPlanCheckTime,
+		SyncedLabels:               src.Status.SyncedLabels,
+		Operations:                 src.Status.Operations,
+	}
+	return storeFields(&in.ObjectMeta, common.V1FieldsAnnotation, fields, reflect.DeepEqual(fields, instanceV1Fields{}))
+}
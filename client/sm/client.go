@@ -56,6 +56,8 @@ type Client interface {
 	Bind(binding *types.ServiceBinding, q *Parameters, user string) (*types.ServiceBinding, string, error)
 	Unbind(id string, q *Parameters, user string) (string, error)
 	RenameBinding(id, newName, newK8SName string) (*types.ServiceBinding, error)
+	UpdateInstanceLabels(id string, labelChanges []*types.LabelChange) (*types.ServiceInstance, error)
+	UpdateBindingLabels(id string, labelChanges []*types.LabelChange) (*types.ServiceBinding, error)
 	ShareInstance(id string, user string) error
 	UnShareInstance(id string, user string) error
 
@@ -107,9 +109,12 @@ type planInfo struct {
 type ProvisionResponse struct {
 	InstanceID   string
 	PlanID       string
+	OfferingID   string
 	Location     string
 	SubaccountID string
 	Tags         json.RawMessage
+	// Instance is the provisioned instance, it is nil if the provisioning is async
+	Instance *types.ServiceInstance
 }
 
 // NewClient NewClientWithAuth returns new SM Client configured with the provided configuration
@@ -174,8 +179,12 @@ func (client *serviceManagerClient) Provision(instance *types.ServiceInstance, s
 		PlanID:       planInfo.planID,
 		SubaccountID: subaccountID,
 	}
+	if len(location) == 0 {
+		res.Instance = newInstance
+	}
 
 	if planInfo.serviceOffering != nil {
+		res.OfferingID = planInfo.serviceOffering.ID
 		res.Tags = planInfo.serviceOffering.Tags
 	}
 
@@ -290,6 +299,33 @@ func (client *serviceManagerClient) RenameBinding(id, newName, newK8SName string
 	return result, err
 }
 
+// UpdateInstanceLabels applies the provided label changes to the service instance.
+// Note that SM does not support removing and adding the same label key in a single request
+func (client *serviceManagerClient) UpdateInstanceLabels(id string, labelChanges []*types.LabelChange) (*types.ServiceInstance, error) {
+	var result *types.ServiceInstance
+	if _, err := client.updateLabels(types.ServiceInstancesURL, id, labelChanges, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateBindingLabels applies the provided label changes to the service binding.
+// Note that SM does not support removing and adding the same label key in a single request
+func (client *serviceManagerClient) UpdateBindingLabels(id string, labelChanges []*types.LabelChange) (*types.ServiceBinding, error) {
+	var result *types.ServiceBinding
+	if _, err := client.updateLabels(types.ServiceBindingsURL, id, labelChanges, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (client *serviceManagerClient) updateLabels(url string, id string, labelChanges []*types.LabelChange, result interface{}) (string, error) {
+	labelsRequest := map[string]interface{}{
+		"labels": labelChanges,
+	}
+	return client.update(labelsRequest, url, id, nil, "", result)
+}
+
 func (client *serviceManagerClient) list(items interface{}, url string, q *Parameters) error {
 	itemsType := reflect.TypeOf(items)
 	if itemsType.Kind() != reflect.Ptr || itemsType.Elem().Kind() != reflect.Slice {
@@ -579,6 +615,15 @@ func ExtractBindingID(operationURL string) string {
 	return ""
 }
 
+func ExtractOperationID(operationURL string) string {
+	r := regexp.MustCompile(`^/v1/service_(?:instances|bindings)/.*/operations/([^/]+)$`)
+	matches := r.FindStringSubmatch(operationURL)
+	if len(matches) == 2 {
+		return matches[1]
+	}
+	return ""
+}
+
 func BuildOperationURL(operationID, resourceID, resourceURL string) string {
 	return fmt.Sprintf("%s/%s%s/%s", resourceURL, resourceID, types.ResourceOperationsURL, operationID)
 }
@@ -180,6 +180,8 @@ var _ = Describe("Client test", func() {
 					Expect(err).ShouldNot(HaveOccurred())
 					Expect(res.Location).Should(HaveLen(0))
 					Expect(res.InstanceID).To(Equal(instance.ID))
+					Expect(res.OfferingID).To(Equal("service_id"))
+					Expect(res.Instance.ID).To(Equal(instance.ID))
 				})
 
 				Context("When multiple matching plan names returned from SM", func() {
@@ -275,6 +277,7 @@ var _ = Describe("Client test", func() {
 					Expect(err).ShouldNot(HaveOccurred())
 					Expect(res.Location).Should(Equal(locationHeader))
 					Expect(res.InstanceID).Should(Equal("12345"))
+					Expect(res.Instance).Should(BeNil())
 				})
 			})
 
@@ -567,6 +570,34 @@ var _ = Describe("Client test", func() {
 				})
 			})
 		})
+
+		Describe("Update instance labels", func() {
+			BeforeEach(func() {
+				responseBody, _ := json.Marshal(instance)
+				handlerDetails = []HandlerDetails{
+					{Method: http.MethodPatch, Path: types.ServiceInstancesURL + "/" + instance.ID, ResponseBody: responseBody, ResponseStatusCode: http.StatusOK},
+				}
+			})
+
+			It("should update labels", func() {
+				res, err := client.UpdateInstanceLabels(instance.ID, []*types.LabelChange{{Key: "key", Operation: types.AddLabelOperation, Values: []string{"value"}}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.ID).To(Equal(instance.ID))
+			})
+
+			When("SM returns an error", func() {
+				BeforeEach(func() {
+					handlerDetails = []HandlerDetails{
+						{Method: http.MethodPatch, Path: types.ServiceInstancesURL + "/" + instance.ID, ResponseBody: []byte(`{ "description": "description"}`), ResponseStatusCode: http.StatusBadRequest},
+					}
+				})
+				It("should return error", func() {
+					res, err := client.UpdateInstanceLabels(instance.ID, []*types.LabelChange{{Key: "key", Operation: types.RemoveLabelOperation}})
+					expectErrorToContainSubstringAndStatusCode(err, "description", http.StatusBadRequest)
+					Expect(res).To(BeNil())
+				})
+			})
+		})
 	})
 
 	Describe("Bindings", func() {
@@ -586,6 +617,12 @@ var _ = Describe("Client test", func() {
 			Expect(bid).To(Equal("1234"))
 		})
 
+		It("validate operation id extraction from operation url", func() {
+			Expect(ExtractOperationID("/v1/service_bindings/1234/operations/5678")).To(Equal("5678"))
+			Expect(ExtractOperationID("/v1/service_instances/1234/operations/5678")).To(Equal("5678"))
+			Expect(ExtractOperationID("/v1/service_instances/1234")).To(BeEmpty())
+		})
+
 		Describe("List service bindings", func() {
 			Context("when there are service bindings registered", func() {
 				BeforeEach(func() {
@@ -979,6 +1016,21 @@ TSTAhYWEQVZqRKYQMYGHpNlU
 				Expect(res.ID).To(Equal("bindingID"))
 			})
 		})
+
+		Describe("Update binding labels", func() {
+			BeforeEach(func() {
+				responseBody, _ := json.Marshal(binding)
+				handlerDetails = []HandlerDetails{
+					{Method: http.MethodPatch, Path: types.ServiceBindingsURL + "/" + binding.ID, ResponseBody: responseBody, ResponseStatusCode: http.StatusOK},
+				}
+			})
+
+			It("should update labels", func() {
+				res, err := client.UpdateBindingLabels(binding.ID, []*types.LabelChange{{Key: "key", Operation: types.AddLabelOperation, Values: []string{"value"}}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.ID).To(Equal("bindingID"))
+			})
+		})
 	})
 
 	It("build operation url", func() {
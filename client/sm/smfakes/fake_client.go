@@ -225,6 +225,20 @@ type FakeClient struct {
 		result1 string
 		result2 error
 	}
+	UpdateBindingLabelsStub        func(string, []*types.LabelChange) (*types.ServiceBinding, error)
+	updateBindingLabelsMutex       sync.RWMutex
+	updateBindingLabelsArgsForCall []struct {
+		arg1 string
+		arg2 []*types.LabelChange
+	}
+	updateBindingLabelsReturns struct {
+		result1 *types.ServiceBinding
+		result2 error
+	}
+	updateBindingLabelsReturnsOnCall map[int]struct {
+		result1 *types.ServiceBinding
+		result2 error
+	}
 	UpdateInstanceStub        func(string, *types.ServiceInstance, string, string, *sm.Parameters, string, string) (*types.ServiceInstance, string, error)
 	updateInstanceMutex       sync.RWMutex
 	updateInstanceArgsForCall []struct {
@@ -246,6 +260,20 @@ type FakeClient struct {
 		result2 string
 		result3 error
 	}
+	UpdateInstanceLabelsStub        func(string, []*types.LabelChange) (*types.ServiceInstance, error)
+	updateInstanceLabelsMutex       sync.RWMutex
+	updateInstanceLabelsArgsForCall []struct {
+		arg1 string
+		arg2 []*types.LabelChange
+	}
+	updateInstanceLabelsReturns struct {
+		result1 *types.ServiceInstance
+		result2 error
+	}
+	updateInstanceLabelsReturnsOnCall map[int]struct {
+		result1 *types.ServiceInstance
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -1228,6 +1256,76 @@ func (fake *FakeClient) UnbindReturnsOnCall(i int, result1 string, result2 error
 	}{result1, result2}
 }
 
+func (fake *FakeClient) UpdateBindingLabels(arg1 string, arg2 []*types.LabelChange) (*types.ServiceBinding, error) {
+	var arg2Copy []*types.LabelChange
+	if arg2 != nil {
+		arg2Copy = make([]*types.LabelChange, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.updateBindingLabelsMutex.Lock()
+	ret, specificReturn := fake.updateBindingLabelsReturnsOnCall[len(fake.updateBindingLabelsArgsForCall)]
+	fake.updateBindingLabelsArgsForCall = append(fake.updateBindingLabelsArgsForCall, struct {
+		arg1 string
+		arg2 []*types.LabelChange
+	}{arg1, arg2Copy})
+	stub := fake.UpdateBindingLabelsStub
+	fakeReturns := fake.updateBindingLabelsReturns
+	fake.recordInvocation("UpdateBindingLabels", []interface{}{arg1, arg2Copy})
+	fake.updateBindingLabelsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) UpdateBindingLabelsCallCount() int {
+	fake.updateBindingLabelsMutex.RLock()
+	defer fake.updateBindingLabelsMutex.RUnlock()
+	return len(fake.updateBindingLabelsArgsForCall)
+}
+
+func (fake *FakeClient) UpdateBindingLabelsCalls(stub func(string, []*types.LabelChange) (*types.ServiceBinding, error)) {
+	fake.updateBindingLabelsMutex.Lock()
+	defer fake.updateBindingLabelsMutex.Unlock()
+	fake.UpdateBindingLabelsStub = stub
+}
+
+func (fake *FakeClient) UpdateBindingLabelsArgsForCall(i int) (string, []*types.LabelChange) {
+	fake.updateBindingLabelsMutex.RLock()
+	defer fake.updateBindingLabelsMutex.RUnlock()
+	argsForCall := fake.updateBindingLabelsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) UpdateBindingLabelsReturns(result1 *types.ServiceBinding, result2 error) {
+	fake.updateBindingLabelsMutex.Lock()
+	defer fake.updateBindingLabelsMutex.Unlock()
+	fake.UpdateBindingLabelsStub = nil
+	fake.updateBindingLabelsReturns = struct {
+		result1 *types.ServiceBinding
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) UpdateBindingLabelsReturnsOnCall(i int, result1 *types.ServiceBinding, result2 error) {
+	fake.updateBindingLabelsMutex.Lock()
+	defer fake.updateBindingLabelsMutex.Unlock()
+	fake.UpdateBindingLabelsStub = nil
+	if fake.updateBindingLabelsReturnsOnCall == nil {
+		fake.updateBindingLabelsReturnsOnCall = make(map[int]struct {
+			result1 *types.ServiceBinding
+			result2 error
+		})
+	}
+	fake.updateBindingLabelsReturnsOnCall[i] = struct {
+		result1 *types.ServiceBinding
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) UpdateInstance(arg1 string, arg2 *types.ServiceInstance, arg3 string, arg4 string, arg5 *sm.Parameters, arg6 string, arg7 string) (*types.ServiceInstance, string, error) {
 	fake.updateInstanceMutex.Lock()
 	ret, specificReturn := fake.updateInstanceReturnsOnCall[len(fake.updateInstanceArgsForCall)]
@@ -1301,6 +1399,76 @@ func (fake *FakeClient) UpdateInstanceReturnsOnCall(i int, result1 *types.Servic
 	}{result1, result2, result3}
 }
 
+func (fake *FakeClient) UpdateInstanceLabels(arg1 string, arg2 []*types.LabelChange) (*types.ServiceInstance, error) {
+	var arg2Copy []*types.LabelChange
+	if arg2 != nil {
+		arg2Copy = make([]*types.LabelChange, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.updateInstanceLabelsMutex.Lock()
+	ret, specificReturn := fake.updateInstanceLabelsReturnsOnCall[len(fake.updateInstanceLabelsArgsForCall)]
+	fake.updateInstanceLabelsArgsForCall = append(fake.updateInstanceLabelsArgsForCall, struct {
+		arg1 string
+		arg2 []*types.LabelChange
+	}{arg1, arg2Copy})
+	stub := fake.UpdateInstanceLabelsStub
+	fakeReturns := fake.updateInstanceLabelsReturns
+	fake.recordInvocation("UpdateInstanceLabels", []interface{}{arg1, arg2Copy})
+	fake.updateInstanceLabelsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) UpdateInstanceLabelsCallCount() int {
+	fake.updateInstanceLabelsMutex.RLock()
+	defer fake.updateInstanceLabelsMutex.RUnlock()
+	return len(fake.updateInstanceLabelsArgsForCall)
+}
+
+func (fake *FakeClient) UpdateInstanceLabelsCalls(stub func(string, []*types.LabelChange) (*types.ServiceInstance, error)) {
+	fake.updateInstanceLabelsMutex.Lock()
+	defer fake.updateInstanceLabelsMutex.Unlock()
+	fake.UpdateInstanceLabelsStub = stub
+}
+
+func (fake *FakeClient) UpdateInstanceLabelsArgsForCall(i int) (string, []*types.LabelChange) {
+	fake.updateInstanceLabelsMutex.RLock()
+	defer fake.updateInstanceLabelsMutex.RUnlock()
+	argsForCall := fake.updateInstanceLabelsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) UpdateInstanceLabelsReturns(result1 *types.ServiceInstance, result2 error) {
+	fake.updateInstanceLabelsMutex.Lock()
+	defer fake.updateInstanceLabelsMutex.Unlock()
+	fake.UpdateInstanceLabelsStub = nil
+	fake.updateInstanceLabelsReturns = struct {
+		result1 *types.ServiceInstance
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) UpdateInstanceLabelsReturnsOnCall(i int, result1 *types.ServiceInstance, result2 error) {
+	fake.updateInstanceLabelsMutex.Lock()
+	defer fake.updateInstanceLabelsMutex.Unlock()
+	fake.UpdateInstanceLabelsStub = nil
+	if fake.updateInstanceLabelsReturnsOnCall == nil {
+		fake.updateInstanceLabelsReturnsOnCall = make(map[int]struct {
+			result1 *types.ServiceInstance
+			result2 error
+		})
+	}
+	fake.updateInstanceLabelsReturnsOnCall[i] = struct {
+		result1 *types.ServiceInstance
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -1334,8 +1502,12 @@ func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	defer fake.unShareInstanceMutex.RUnlock()
 	fake.unbindMutex.RLock()
 	defer fake.unbindMutex.RUnlock()
+	fake.updateBindingLabelsMutex.RLock()
+	defer fake.updateBindingLabelsMutex.RUnlock()
 	fake.updateInstanceMutex.RLock()
 	defer fake.updateInstanceMutex.RUnlock()
+	fake.updateInstanceLabelsMutex.RLock()
+	defer fake.updateInstanceLabelsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -32,13 +32,14 @@ type ServiceInstance struct {
 	ServiceID     string `json:"service_id,omitempty" yaml:"service_id,omitempty"`
 	ServicePlanID string `json:"service_plan_id,omitempty" yaml:"service_plan_id,omitempty"`
 	PlatformID    string `json:"platform_id,omitempty" yaml:"platform_id,omitempty"`
+	DashboardURL  string `json:"dashboard_url,omitempty" yaml:"dashboard_url,omitempty"`
 
 	Parameters json.RawMessage `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 	Labels     Labels          `json:"labels,omitempty" yaml:"labels,omitempty"`
 
-	MaintenanceInfo json.RawMessage `json:"maintenance_info,omitempty" yaml:"-"`
-	Context         json.RawMessage `json:"context,omitempty" yaml:"context,omitempty"`
-	PreviousValues  json.RawMessage `json:"-" yaml:"-"`
+	MaintenanceInfo *MaintenanceInfo `json:"maintenance_info,omitempty" yaml:"-"`
+	Context         json.RawMessage  `json:"context,omitempty" yaml:"context,omitempty"`
+	PreviousValues  json.RawMessage  `json:"-" yaml:"-"`
 
 	Ready  bool `json:"ready" yaml:"ready"`
 	Usable bool `json:"usable" yaml:"usable"`
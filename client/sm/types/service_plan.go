@@ -30,11 +30,14 @@ type ServicePlan struct {
 	CreatedAt   string `json:"created_at,omitempty" yaml:"created_at,omitempty"`
 	UpdatedAt   string `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
 
-	CatalogID     string `json:"catalog_id,omitempty" yaml:"catalog_id,omitempty"`
-	CatalogName   string `json:"catalog_name,omitempty" yaml:"catalog_name,omitempty"`
-	Free          bool   `json:"free,omitempty" yaml:"free,omitempty"`
-	Bindable      bool   `json:"bindable,omitempty" yaml:"bindable,omitempty"`
-	PlanUpdatable bool   `json:"plan_updateable,omitempty" yaml:"plan_updateable,omitempty"`
+	CatalogID   string `json:"catalog_id,omitempty" yaml:"catalog_id,omitempty"`
+	CatalogName string `json:"catalog_name,omitempty" yaml:"catalog_name,omitempty"`
+	Free        bool   `json:"free,omitempty" yaml:"free,omitempty"`
+	Bindable    bool   `json:"bindable,omitempty" yaml:"bindable,omitempty"`
+	// PlanUpdatable overrides plan_updateable of the service offering when it is set
+	PlanUpdatable *bool `json:"plan_updateable,omitempty" yaml:"plan_updateable,omitempty"`
+	// MaintenanceInfo is the current maintenance info of the plan, instances with an older version can be upgraded to it
+	MaintenanceInfo *MaintenanceInfo `json:"maintenance_info,omitempty" yaml:"maintenance_info,omitempty"`
 
 	Metadata json.RawMessage `json:"metadata,omitempty" yaml:"-"`
 	Schemas  json.RawMessage `json:"schemas,omitempty" yaml:"-"`
@@ -44,6 +47,12 @@ type ServicePlan struct {
 	Ready             bool   `json:"ready,omitempty" yaml:"ready,omitempty"`
 }
 
+// MaintenanceInfo defines the version of a service plan or of a service instance
+type MaintenanceInfo struct {
+	Version     string `json:"version,omitempty" yaml:"version,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
 // ServicePlans wraps an array of service plans
 type ServicePlans struct {
 	ServicePlans []ServicePlan `json:"items" yaml:"items"`
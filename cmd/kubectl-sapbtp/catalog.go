@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/SAP/sap-btp-service-operator/client/sm"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+)
+
+type offeringSummary struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	DataCenter  string   `json:"dataCenter,omitempty"`
+	Plans       []string `json:"plans"`
+}
+
+type planSummary struct {
+	Name        string `json:"name"`
+	ID          string `json:"id"`
+	Offering    string `json:"offering"`
+	Free        bool   `json:"free"`
+	Bindable    bool   `json:"bindable"`
+	Description string `json:"description,omitempty"`
+}
+
+func runMarketplace(ctx context.Context, args []string) error {
+	opts := &options{}
+	fs := flag.NewFlagSet("marketplace", flag.ExitOnError)
+	opts.addFlags(fs)
+	opts.addOutputFlag(fs)
+	fs.Usage = commandUsage(fs, "List the service offerings and plans available with the credentials of the namespace.",
+		"kubectl sapbtp marketplace [-n <namespace>] [-o table|json|yaml]")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := opts.init(true); err != nil {
+		return err
+	}
+
+	smClient, err := opts.smClient(ctx, opts.namespace)
+	if err != nil {
+		return err
+	}
+	offerings, err := smClient.ListOfferings(nil)
+	if err != nil {
+		return err
+	}
+	plans, err := smClient.ListPlans(nil)
+	if err != nil {
+		return err
+	}
+
+	plansByOffering := make(map[string][]string)
+	for _, plan := range plans.ServicePlans {
+		plansByOffering[plan.ServiceOfferingID] = append(plansByOffering[plan.ServiceOfferingID], plan.CatalogName)
+	}
+	summaries := make([]offeringSummary, 0, len(offerings.ServiceOfferings))
+	for _, offering := range offerings.ServiceOfferings {
+		offeringPlans := plansByOffering[offering.ID]
+		sort.Strings(offeringPlans)
+		summaries = append(summaries, offeringSummary{
+			Name:        offering.CatalogName,
+			Description: offering.Description,
+			DataCenter:  offering.DataCenter,
+			Plans:       offeringPlans,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+
+	return printOutput(os.Stdout, opts.output, summaries, func(tw *tabwriter.Writer) {
+		row(tw, "OFFERING", "PLANS", "DESCRIPTION")
+		for _, summary := range summaries {
+			row(tw, summary.Name, strings.Join(summary.Plans, ","), summary.Description)
+		}
+	})
+}
+
+func runPlans(ctx context.Context, args []string) error {
+	opts := &options{}
+	var offeringName string
+	fs := flag.NewFlagSet("plans", flag.ExitOnError)
+	opts.addFlags(fs)
+	opts.addOutputFlag(fs)
+	fs.StringVar(&offeringName, "offering", "", "List only the plans of the service offering with this name")
+	fs.Usage = commandUsage(fs, "List the service plans available with the credentials of the namespace.",
+		"kubectl sapbtp plans [--offering <name>] [-n <namespace>] [-o table|json|yaml]")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := opts.init(true); err != nil {
+		return err
+	}
+
+	smClient, err := opts.smClient(ctx, opts.namespace)
+	if err != nil {
+		return err
+	}
+
+	summaries, err := listPlans(smClient, offeringName)
+	if err != nil {
+		return err
+	}
+	return printOutput(os.Stdout, opts.output, summaries, func(tw *tabwriter.Writer) {
+		row(tw, "PLAN", "ID", "OFFERING", "FREE", "BINDABLE", "DESCRIPTION")
+		for _, summary := range summaries {
+			row(tw, summary.Name, summary.ID, summary.Offering, strconv.FormatBool(summary.Free), strconv.FormatBool(summary.Bindable), summary.Description)
+		}
+	})
+}
+
+// listPlans returns the plans of the service offering with the given name, or all the plans if offeringName is empty
+func listPlans(smClient sm.Client, offeringName string) ([]planSummary, error) {
+	var offeringsQuery *sm.Parameters
+	if len(offeringName) > 0 {
+		offeringsQuery = &sm.Parameters{FieldQuery: []string{fmt.Sprintf("catalog_name eq '%s'", offeringName)}}
+	}
+	offerings, err := smClient.ListOfferings(offeringsQuery)
+	if err != nil {
+		return nil, err
+	}
+	if len(offeringName) > 0 && len(offerings.ServiceOfferings) == 0 {
+		return nil, fmt.Errorf("couldn't find the service offering '%s'", offeringName)
+	}
+
+	offeringNames := make(map[string]string)
+	offeringIDs := make([]string, 0, len(offerings.ServiceOfferings))
+	for _, offering := range offerings.ServiceOfferings {
+		offeringNames[offering.ID] = offering.CatalogName
+		offeringIDs = append(offeringIDs, offering.ID)
+	}
+
+	var plansQuery *sm.Parameters
+	if len(offeringName) > 0 {
+		plansQuery = &sm.Parameters{FieldQuery: []string{fmt.Sprintf("service_offering_id in ('%s')", strings.Join(offeringIDs, "', '"))}}
+	}
+	plans, err := smClient.ListPlans(plansQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPlanSummaries(plans.ServicePlans, offeringNames), nil
+}
+
+func toPlanSummaries(plans []smClientTypes.ServicePlan, offeringNames map[string]string) []planSummary {
+	summaries := make([]planSummary, 0, len(plans))
+	for _, plan := range plans {
+		summaries = append(summaries, planSummary{
+			Name:        plan.CatalogName,
+			ID:          plan.ID,
+			Offering:    offeringNames[plan.ServiceOfferingID],
+			Free:        plan.Free,
+			Bindable:    plan.Bindable,
+			Description: plan.Description,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Offering != summaries[j].Offering {
+			return summaries[i].Offering < summaries[j].Offering
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries
+}
+
+func runServices(ctx context.Context, args []string) error {
+	opts := &options{}
+	fs := flag.NewFlagSet("services", flag.ExitOnError)
+	opts.addFlags(fs)
+	opts.addOutputFlag(fs)
+	fs.Usage = commandUsage(fs, "List the service offerings available with the credentials of the namespace.",
+		"kubectl sapbtp services [-n <namespace>] [-o table|json|yaml]")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := opts.init(true); err != nil {
+		return err
+	}
+
+	smClient, err := opts.smClient(ctx, opts.namespace)
+	if err != nil {
+		return err
+	}
+	offerings, err := smClient.ListOfferings(nil)
+	if err != nil {
+		return err
+	}
+
+	services := offerings.ServiceOfferings
+	sort.Slice(services, func(i, j int) bool { return services[i].CatalogName < services[j].CatalogName })
+	return printOutput(os.Stdout, opts.output, services, func(tw *tabwriter.Writer) {
+		row(tw, "OFFERING", "ID", "BINDABLE", "DESCRIPTION")
+		for _, service := range services {
+			row(tw, service.CatalogName, service.ID, strconv.FormatBool(service.Bindable), service.Description)
+		}
+	})
+}
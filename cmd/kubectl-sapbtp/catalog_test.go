@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/SAP/sap-btp-service-operator/client/sm/smfakes"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Catalog", func() {
+	var smClient *smfakes.FakeClient
+
+	BeforeEach(func() {
+		smClient = &smfakes.FakeClient{}
+		smClient.ListOfferingsReturns(&smClientTypes.ServiceOfferings{ServiceOfferings: []smClientTypes.ServiceOffering{
+			{ID: "offering-a-id", CatalogName: "offering-a"},
+			{ID: "offering-b-id", CatalogName: "offering-b"},
+		}}, nil)
+		smClient.ListPlansReturns(&smClientTypes.ServicePlans{ServicePlans: []smClientTypes.ServicePlan{
+			{ID: "plan-b2-id", CatalogName: "plan-b2", ServiceOfferingID: "offering-b-id", Bindable: true},
+			{ID: "plan-a-id", CatalogName: "plan-a", ServiceOfferingID: "offering-a-id", Free: true},
+			{ID: "plan-b1-id", CatalogName: "plan-b1", ServiceOfferingID: "offering-b-id"},
+		}}, nil)
+	})
+
+	Describe("listPlans", func() {
+		It("should list all the plans sorted by offering and name", func() {
+			summaries, err := listPlans(smClient, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summaries).To(Equal([]planSummary{
+				{Name: "plan-a", ID: "plan-a-id", Offering: "offering-a", Free: true},
+				{Name: "plan-b1", ID: "plan-b1-id", Offering: "offering-b"},
+				{Name: "plan-b2", ID: "plan-b2-id", Offering: "offering-b", Bindable: true},
+			}))
+			Expect(smClient.ListOfferingsArgsForCall(0)).To(BeNil())
+			Expect(smClient.ListPlansArgsForCall(0)).To(BeNil())
+		})
+
+		It("should filter the plans by the offering name", func() {
+			smClient.ListOfferingsReturns(&smClientTypes.ServiceOfferings{ServiceOfferings: []smClientTypes.ServiceOffering{
+				{ID: "offering-b-id", CatalogName: "offering-b"},
+			}}, nil)
+			smClient.ListPlansReturns(&smClientTypes.ServicePlans{ServicePlans: []smClientTypes.ServicePlan{
+				{ID: "plan-b1-id", CatalogName: "plan-b1", ServiceOfferingID: "offering-b-id"},
+			}}, nil)
+
+			summaries, err := listPlans(smClient, "offering-b")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summaries).To(Equal([]planSummary{{Name: "plan-b1", ID: "plan-b1-id", Offering: "offering-b"}}))
+			Expect(smClient.ListOfferingsArgsForCall(0).FieldQuery).To(ConsistOf("catalog_name eq 'offering-b'"))
+			Expect(smClient.ListPlansArgsForCall(0).FieldQuery).To(ConsistOf("service_offering_id in ('offering-b-id')"))
+		})
+
+		DescribeTable("errors",
+			func(setup func(), offeringName, expectedErr string) {
+				setup()
+				_, err := listPlans(smClient, offeringName)
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+			},
+			Entry("unknown offering", func() {
+				smClient.ListOfferingsReturns(&smClientTypes.ServiceOfferings{}, nil)
+			}, "missing", "couldn't find the service offering 'missing'"),
+			Entry("listing the offerings fails", func() {
+				smClient.ListOfferingsReturns(nil, fmt.Errorf("offerings unavailable"))
+			}, "", "offerings unavailable"),
+			Entry("listing the plans fails", func() {
+				smClient.ListPlansReturns(nil, fmt.Errorf("plans unavailable"))
+			}, "offering-a", "plans unavailable"),
+		)
+
+		It("should return no plans when the catalog is empty", func() {
+			smClient.ListOfferingsReturns(&smClientTypes.ServiceOfferings{}, nil)
+			smClient.ListPlansReturns(&smClientTypes.ServicePlans{}, nil)
+			summaries, err := listPlans(smClient, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summaries).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	"github.com/SAP/sap-btp-service-operator/client/sm"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type instanceDescription struct {
+	Instance       *v1.ServiceInstance            `json:"instance"`
+	ServiceManager *smClientTypes.ServiceInstance `json:"serviceManager,omitempty"`
+	// ServiceManagerError is set when the instance could not be read from Service Manager
+	ServiceManagerError string `json:"serviceManagerError,omitempty"`
+}
+
+func runDescribe(ctx context.Context, args []string) error {
+	opts := &options{}
+	fs := flag.NewFlagSet("describe", flag.ExitOnError)
+	opts.addFlags(fs)
+	opts.addOutputFlag(fs)
+	fs.Usage = commandUsage(fs, "Show the Service Manager state and the conditions of a service instance.",
+		"kubectl sapbtp describe instance <name> [-n <namespace>] [-o table|json|yaml]")
+	if len(args) < 2 || args[0] != "instance" {
+		fs.Usage()
+		return fmt.Errorf("expected: describe instance <name>")
+	}
+	name := args[1]
+	if err := fs.Parse(args[2:]); err != nil {
+		return err
+	}
+	if err := opts.init(true); err != nil {
+		return err
+	}
+
+	instance := &v1.ServiceInstance{}
+	if err := opts.k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: opts.namespace}, instance); err != nil {
+		return err
+	}
+	instance.SetGroupVersionKind(v1.GroupVersion.WithKind("ServiceInstance"))
+	instance.ManagedFields = nil
+
+	description := &instanceDescription{Instance: instance}
+	if len(instance.Status.InstanceID) > 0 {
+		smInstance, err := getSMInstance(ctx, opts, instance)
+		if err != nil {
+			description.ServiceManagerError = err.Error()
+		} else {
+			description.ServiceManager = smInstance
+		}
+	}
+
+	return printOutput(os.Stdout, opts.output, description, func(tw *tabwriter.Writer) {
+		printInstanceDescription(tw, description)
+	})
+}
+
+func getSMInstance(ctx context.Context, opts *options, instance *v1.ServiceInstance) (*smClientTypes.ServiceInstance, error) {
+	smClient, err := opts.smClientForInstance(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+	return smClient.GetInstanceByID(instance.Status.InstanceID, &sm.Parameters{
+		GeneralParams: []string{"attach_last_operations=true"},
+	})
+}
+
+func printInstanceDescription(tw *tabwriter.Writer, description *instanceDescription) {
+	instance := description.Instance
+	row(tw, "Name:", instance.Name)
+	row(tw, "Namespace:", instance.Namespace)
+	row(tw, "Offering:", instance.Spec.ServiceOfferingName)
+	row(tw, "Plan:", instance.Spec.ServicePlanName)
+	row(tw, "External Name:", instance.Spec.ExternalName)
+	row(tw, "Instance ID:", instance.Status.InstanceID)
+	row(tw, "Subaccount ID:", instance.Status.SubaccountID)
+	row(tw, "Ready:", string(instance.Status.Ready))
+	if instance.DeletionTimestamp != nil {
+		row(tw, "Deletion Requested:", instance.DeletionTimestamp.String())
+	}
+
+	row(tw, "")
+	row(tw, "Service Manager:")
+	switch {
+	case description.ServiceManager != nil:
+		smInstance := description.ServiceManager
+		row(tw, "  Ready:", strconv.FormatBool(smInstance.Ready))
+		row(tw, "  Usable:", strconv.FormatBool(smInstance.Usable))
+		row(tw, "  Shared:", strconv.FormatBool(smInstance.Shared))
+		if op := smInstance.LastOperation; op != nil {
+			row(tw, "  Last Operation:", fmt.Sprintf("%s %s", op.Type, op.State))
+			if len(op.Description) > 0 {
+				row(tw, "  Description:", op.Description)
+			}
+		}
+	case len(description.ServiceManagerError) > 0:
+		row(tw, "  Error:", description.ServiceManagerError)
+	default:
+		row(tw, "  Not created yet")
+	}
+
+	row(tw, "")
+	row(tw, "Conditions:")
+	row(tw, "  TYPE", "STATUS", "REASON", "LAST TRANSITION", "MESSAGE")
+	for _, condition := range instance.Status.Conditions {
+		row(tw, "  "+condition.Type, string(condition.Status), condition.Reason, condition.LastTransitionTime.String(), condition.Message)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/SAP/sap-btp-service-operator/internal/importer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func runImport(ctx context.Context, args []string) error {
+	opts := &options{}
+	var clusterID string
+	var dryRun bool
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	opts.addFlags(fs)
+	fs.StringVar(&clusterID, "cluster-id", "", "The cluster ID of the resources to import, defaults to the cluster ID of the installed operator")
+	fs.BoolVar(&dryRun, "dry-run", false, "Print the custom resources as YAML instead of creating them")
+	fs.Usage = commandUsage(fs, "Import the SAP BTP service instances and bindings of a cluster as custom resources.\n"+
+		"Resources of all namespaces are imported unless -n is set, existing custom resources are skipped.",
+		"kubectl sapbtp import [-n <namespace>] [--cluster-id <id>] [--dry-run]")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := opts.init(false); err != nil {
+		return err
+	}
+	if len(clusterID) == 0 {
+		var err error
+		if clusterID, err = opts.clusterID(ctx); err != nil {
+			return err
+		}
+	}
+
+	smClient, err := opts.smClient(ctx, opts.namespace)
+	if err != nil {
+		return err
+	}
+
+	imp := &importer.Importer{
+		SMClient:  smClient,
+		ClusterID: clusterID,
+		Namespace: opts.namespace,
+	}
+	objects, err := imp.Build(ctx)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		return printManifests(os.Stdout, objects)
+	}
+
+	result, err := importer.Apply(ctx, opts.k8sClient, objects)
+	if result != nil {
+		for _, obj := range result.Created {
+			fmt.Printf("%s created\n", objectRef(obj))
+		}
+		for _, obj := range result.Skipped {
+			fmt.Printf("%s already exists, skipped\n", objectRef(obj))
+		}
+	}
+	return err
+}
+
+func objectRef(obj client.Object) string {
+	return fmt.Sprintf("%s/%s -n %s", strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind), obj.GetName(), obj.GetNamespace())
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+type command struct {
+	description string
+	run         func(ctx context.Context, args []string) error
+}
+
+var commands = map[string]command{
+	"marketplace": {description: "List the available service offerings and their plans", run: runMarketplace},
+	"services":    {description: "List the available service offerings", run: runServices},
+	"plans":       {description: "List the available service plans", run: runPlans},
+	"describe":    {description: "Show the Service Manager state and the conditions of a service instance", run: runDescribe},
+	"orphans":     {description: "List the Service Manager resources of the cluster without a custom resource", run: runOrphans},
+	"rotate":      {description: "Trigger the credentials rotation of a service binding", run: runRotate},
+	"unstick":     {description: "Remove the finalizer of a resource stuck in deletion", run: runUnstick},
+	"import":      {description: "Import the SAP BTP service instances and bindings of a cluster as custom resources", run: runImport},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(1)
+	}
+
+	if err := cmd.run(context.Background(), os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "kubectl extension to access SAP BTP services\n\nUsage:\n  kubectl sapbtp <command> [flags]\n\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].description)
+	}
+	fmt.Fprintln(os.Stderr, "\nUse \"kubectl sapbtp <command> -h\" for more information about a command.")
+}
+
+// commandUsage returns a usage function printing the description and synopsis of a command followed by its flags
+func commandUsage(fs *flag.FlagSet, description, synopsis string) func() {
+	return func() {
+		fmt.Fprintf(fs.Output(), "%s\n\nUsage:\n  %s\n\nFlags:\n", description, synopsis)
+		fs.PrintDefaults()
+	}
+}
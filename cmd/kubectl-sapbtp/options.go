@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	"github.com/SAP/sap-btp-service-operator/client/sm"
+	"github.com/SAP/sap-btp-service-operator/internal/config"
+	"github.com/SAP/sap-btp-service-operator/internal/utils"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const operatorConfigMapName = "sap-btp-operator-config"
+
+// options are the flags shared by all the commands
+type options struct {
+	kubeconfig        string
+	kubeContext       string
+	namespace         string
+	operatorNamespace string
+	output            string
+
+	k8sClient client.Client
+	// newSMClient returns the Service Manager client for the resources of the instance, set by init
+	newSMClient func(ctx context.Context, instance *v1.ServiceInstance) (sm.Client, error)
+}
+
+func (o *options) addFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file")
+	fs.StringVar(&o.kubeContext, "context", "", "The kubeconfig context to use")
+	fs.StringVar(&o.namespace, "n", "", "The namespace of the resources, defaults to the namespace of the current context")
+	fs.StringVar(&o.operatorNamespace, "operator-namespace", "sap-btp-operator", "The namespace the SAP BTP service operator is installed in")
+}
+
+func (o *options) addOutputFlag(fs *flag.FlagSet) {
+	fs.StringVar(&o.output, "o", outputTable, "Output format, one of: table, json, yaml")
+}
+
+// init loads the kubeconfig and resolves the namespace, defaultNamespace=false keeps an empty namespace empty
+func (o *options) init(defaultNamespace bool) error {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: o.kubeContext})
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	if len(o.namespace) == 0 && defaultNamespace {
+		if o.namespace, _, err = clientConfig.Namespace(); err != nil {
+			return err
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return err
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		return err
+	}
+	o.k8sClient, err = client.New(restConfig, client.Options{Scheme: scheme})
+	o.newSMClient = o.operatorSMClient
+	return err
+}
+
+// operatorConfig returns the configuration of the installed operator
+func (o *options) operatorConfig(ctx context.Context) (map[string]string, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := o.k8sClient.Get(ctx, types.NamespacedName{Name: operatorConfigMapName, Namespace: o.operatorNamespace}, configMap); err != nil {
+		return nil, fmt.Errorf("failed to read the operator configuration from namespace %s: %w", o.operatorNamespace, err)
+	}
+	return configMap.Data, nil
+}
+
+// smClient returns a Service Manager client with the credentials the operator uses for resources of the namespace,
+// or with the cluster default credentials if namespace is empty
+func (o *options) smClient(ctx context.Context, namespace string) (sm.Client, error) {
+	if len(namespace) == 0 {
+		namespace = o.operatorNamespace
+	}
+	instance := &v1.ServiceInstance{}
+	instance.Namespace = namespace
+	return o.smClientForInstance(ctx, instance)
+}
+
+// smClientForInstance returns a Service Manager client with the credentials the operator uses for the instance
+func (o *options) smClientForInstance(ctx context.Context, instance *v1.ServiceInstance) (sm.Client, error) {
+	return o.newSMClient(ctx, instance)
+}
+
+// operatorSMClient resolves the Service Manager credentials of the instance the same way the operator does
+func (o *options) operatorSMClient(ctx context.Context, instance *v1.ServiceInstance) (sm.Client, error) {
+	if err := o.initSecretsClient(ctx); err != nil {
+		return nil, err
+	}
+	return utils.GetSMClient(withLogger(ctx), instance)
+}
+
+// initSecretsClient sets up the operator secret resolution with the configuration of the installed operator
+func (o *options) initSecretsClient(ctx context.Context) error {
+	operatorConfig, err := o.operatorConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	managementNamespace := operatorConfig["MANAGEMENT_NAMESPACE"]
+	if len(managementNamespace) == 0 {
+		managementNamespace = o.operatorNamespace
+	}
+	utils.InitializeSecretsClient(o.k8sClient, nil, config.Config{
+		ManagementNamespace:    managementNamespace,
+		ReleaseNamespace:       o.operatorNamespace,
+		EnableNamespaceSecrets: true,
+	})
+	return nil
+}
+
+// clusterID returns the cluster ID of the installed operator
+func (o *options) clusterID(ctx context.Context) (string, error) {
+	operatorConfig, err := o.operatorConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(operatorConfig["CLUSTER_ID"]) == 0 {
+		return "", fmt.Errorf("cluster ID not found in the operator configuration")
+	}
+	return operatorConfig["CLUSTER_ID"], nil
+}
+
+func withLogger(ctx context.Context) context.Context {
+	return context.WithValue(ctx, utils.LogKey{}, logr.Discard())
+}
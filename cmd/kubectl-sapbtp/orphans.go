@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"text/tabwriter"
+
+	"github.com/SAP/sap-btp-service-operator/internal/orphans"
+	"github.com/SAP/sap-btp-service-operator/internal/utils"
+)
+
+func runOrphans(ctx context.Context, args []string) error {
+	opts := &options{}
+	fs := flag.NewFlagSet("orphans", flag.ExitOnError)
+	opts.addFlags(fs)
+	opts.addOutputFlag(fs)
+	fs.Usage = commandUsage(fs, "List the Service Manager instances and bindings of the cluster without a matching custom resource.\n"+
+		"Resources of all namespaces are listed unless -n is set.",
+		"kubectl sapbtp orphans [-n <namespace>] [-o table|json|yaml]")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := opts.init(false); err != nil {
+		return err
+	}
+
+	clusterID, err := opts.clusterID(ctx)
+	if err != nil {
+		return err
+	}
+	if err := opts.initSecretsClient(ctx); err != nil {
+		return err
+	}
+	smClients, err := utils.GetSMClientsForCluster(withLogger(ctx), opts.k8sClient)
+	if err != nil {
+		return err
+	}
+
+	var namespaces []string
+	if len(opts.namespace) > 0 {
+		namespaces = []string{opts.namespace}
+	}
+	result := make([]orphans.Orphan, 0)
+	for _, smClient := range smClients {
+		found, err := orphans.Find(ctx, opts.k8sClient, smClient, clusterID, namespaces)
+		if err != nil {
+			return err
+		}
+		result = append(result, found...)
+	}
+
+	return printOutput(os.Stdout, opts.output, result, func(tw *tabwriter.Writer) {
+		row(tw, "KIND", "ID", "NAME", "NAMESPACE", "K8S NAME")
+		for _, orphan := range result {
+			row(tw, orphan.Kind, orphan.ID, orphan.Name, orphan.Namespace, orphan.K8sName)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// printOutput prints v as JSON or YAML, or calls printTable for the table format
+func printOutput(w io.Writer, format string, v interface{}, printTable func(tw *tabwriter.Writer)) error {
+	switch format {
+	case outputJSON:
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(out))
+		return err
+	case outputYAML:
+		out, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(w, string(out))
+		return err
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		printTable(tw)
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q, use one of: table, json, yaml", format)
+	}
+}
+
+// row writes the columns as a single tab separated table row
+func row(tw *tabwriter.Writer, columns ...string) {
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+}
+
+// printManifests prints the objects as a multi document YAML, without their status and server populated metadata
+func printManifests(w io.Writer, objects []client.Object) error {
+	for _, obj := range objects {
+		manifest, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		delete(manifest, "status")
+		if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "creationTimestamp")
+		}
+
+		out, err := yaml.Marshal(manifest)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
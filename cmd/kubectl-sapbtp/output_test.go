@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"text/tabwriter"
+
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Output", func() {
+	summaries := []planSummary{
+		{Name: "plan-a", ID: "plan-a-id", Offering: "offering-a", Free: true},
+		{Name: "long-plan-name", ID: "plan-b-id", Offering: "offering-a", Description: "a plan"},
+	}
+	printTable := func(tw *tabwriter.Writer) {
+		row(tw, "PLAN", "ID", "DESCRIPTION")
+		for _, summary := range summaries {
+			row(tw, summary.Name, summary.ID, summary.Description)
+		}
+	}
+
+	DescribeTable("printOutput",
+		func(format, expected string) {
+			out := &bytes.Buffer{}
+			Expect(printOutput(out, format, summaries, printTable)).To(Succeed())
+			Expect(out.String()).To(Equal(expected))
+		},
+		Entry("table", outputTable,
+			"PLAN            ID         DESCRIPTION\n"+
+				"plan-a          plan-a-id  \n"+
+				"long-plan-name  plan-b-id  a plan\n"),
+		Entry("json", outputJSON, `[
+  {
+    "name": "plan-a",
+    "id": "plan-a-id",
+    "offering": "offering-a",
+    "free": true,
+    "bindable": false
+  },
+  {
+    "name": "long-plan-name",
+    "id": "plan-b-id",
+    "offering": "offering-a",
+    "free": false,
+    "bindable": false,
+    "description": "a plan"
+  }
+]
+`),
+		Entry("yaml", outputYAML, `- bindable: false
+  free: true
+  id: plan-a-id
+  name: plan-a
+  offering: offering-a
+- bindable: false
+  description: a plan
+  free: false
+  id: plan-b-id
+  name: long-plan-name
+  offering: offering-a
+`),
+	)
+
+	It("should fail on an unknown format", func() {
+		Expect(printOutput(&bytes.Buffer{}, "xml", summaries, printTable)).To(MatchError(ContainSubstring(`unknown output format "xml"`)))
+	})
+
+	It("should print the manifests without status and server populated metadata", func() {
+		instance := &v1.ServiceInstance{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "ServiceInstance"},
+			ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "ns1"},
+			Spec:       v1.ServiceInstanceSpec{ServiceOfferingName: "offering-a", ServicePlanName: "plan-a"},
+		}
+		instance.Status.InstanceID = "instance-id"
+		binding := &v1.ServiceBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "ServiceBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: "binding", Namespace: "ns1"},
+			Spec:       v1.ServiceBindingSpec{ServiceInstanceName: "instance"},
+		}
+
+		out := &bytes.Buffer{}
+		Expect(printManifests(out, []client.Object{instance, binding})).To(Succeed())
+		Expect(out.String()).To(HavePrefix("---\napiVersion: services.cloud.sap.com/v1\nkind: ServiceInstance\n"))
+		Expect(out.String()).To(ContainSubstring("---\napiVersion: services.cloud.sap.com/v1\nkind: ServiceBinding\n"))
+		Expect(out.String()).To(ContainSubstring("servicePlanName: plan-a"))
+		Expect(out.String()).ToNot(ContainSubstring("status"))
+		Expect(out.String()).ToNot(ContainSubstring("instance-id"))
+		Expect(out.String()).ToNot(ContainSubstring("creationTimestamp"))
+	})
+})
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func runRotate(ctx context.Context, args []string) error {
+	opts := &options{}
+	fs := flag.NewFlagSet("rotate", flag.ExitOnError)
+	opts.addFlags(fs)
+	fs.Usage = commandUsage(fs, "Trigger the credentials rotation of a service binding.\n"+
+		"The binding must have credentialsRotationPolicy enabled.",
+		"kubectl sapbtp rotate binding <name> [-n <namespace>]")
+	if len(args) < 2 || args[0] != "binding" {
+		fs.Usage()
+		return fmt.Errorf("expected: rotate binding <name>")
+	}
+	name := args[1]
+	if err := fs.Parse(args[2:]); err != nil {
+		return err
+	}
+	if err := opts.init(true); err != nil {
+		return err
+	}
+	return rotateBinding(ctx, opts, name)
+}
+
+// rotateBinding sets the force rotate annotation of the binding, the operator rotates the credentials on the next reconcile
+func rotateBinding(ctx context.Context, opts *options, name string) error {
+	binding := &v1.ServiceBinding{}
+	if err := opts.k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: opts.namespace}, binding); err != nil {
+		return err
+	}
+	if binding.Spec.CredRotationPolicy == nil || !binding.Spec.CredRotationPolicy.Enabled {
+		return fmt.Errorf("credentials rotation is not enabled for binding %s, set spec.credentialsRotationPolicy.enabled to true first", name)
+	}
+
+	patch := client.MergeFrom(binding.DeepCopy())
+	if binding.Annotations == nil {
+		binding.Annotations = make(map[string]string)
+	}
+	binding.Annotations[common.ForceRotateAnnotation] = "true"
+	if err := opts.k8sClient.Patch(ctx, binding, patch); err != nil {
+		return err
+	}
+	fmt.Printf("servicebinding/%s -n %s credentials rotation requested\n", binding.Name, binding.Namespace)
+	return nil
+}
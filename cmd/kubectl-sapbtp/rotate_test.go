@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Rotate", func() {
+	const namespace = "ns1"
+
+	DescribeTable("rotateBinding",
+		func(annotations map[string]string, policy *v1.CredentialsRotationPolicy, expectedErr string) {
+			ctx := context.Background()
+			binding := &v1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "binding", Namespace: namespace, Annotations: annotations}}
+			binding.Spec.CredRotationPolicy = policy
+			opts := &options{namespace: namespace, k8sClient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(binding).Build()}
+
+			err := rotateBinding(ctx, opts, "binding")
+
+			updated := &v1.ServiceBinding{}
+			Expect(opts.k8sClient.Get(ctx, types.NamespacedName{Name: "binding", Namespace: namespace}, updated)).To(Succeed())
+			if len(expectedErr) > 0 {
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+				Expect(updated.Annotations).ToNot(HaveKey(common.ForceRotateAnnotation))
+				return
+			}
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated.Annotations).To(HaveKeyWithValue(common.ForceRotateAnnotation, "true"))
+			for key, value := range annotations {
+				Expect(updated.Annotations).To(HaveKeyWithValue(key, value))
+			}
+		},
+		Entry("sets the annotation on a binding without annotations", nil, &v1.CredentialsRotationPolicy{Enabled: true}, ""),
+		Entry("keeps the other annotations", map[string]string{"owner": "team-a"}, &v1.CredentialsRotationPolicy{Enabled: true}, ""),
+		Entry("fails without a rotation policy", nil, nil, "credentials rotation is not enabled"),
+		Entry("fails when the rotation policy is disabled", nil, &v1.CredentialsRotationPolicy{Enabled: false}, "credentials rotation is not enabled"),
+	)
+
+	It("should fail when the binding doesn't exist", func() {
+		opts := &options{namespace: namespace, k8sClient: fake.NewClientBuilder().WithScheme(scheme).Build()}
+		Expect(rotateBinding(context.Background(), opts, "binding")).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	"github.com/SAP/sap-btp-service-operator/client/sm"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func runUnstick(ctx context.Context, args []string) error {
+	opts := &options{}
+	var force bool
+	fs := flag.NewFlagSet("unstick", flag.ExitOnError)
+	opts.addFlags(fs)
+	fs.BoolVar(&force, "force", false, "Remove the finalizer even if the resource still exists in Service Manager or can't be checked")
+	fs.Usage = commandUsage(fs, "Remove the operator finalizer of a service instance or binding that is stuck in deletion.\n"+
+		"The finalizer is removed only if the resource no longer exists in Service Manager, unless --force is set.",
+		"kubectl sapbtp unstick instance|binding <name> [-n <namespace>] [--force]")
+	if len(args) < 2 || (args[0] != "instance" && args[0] != "binding") {
+		fs.Usage()
+		return fmt.Errorf("expected: unstick instance|binding <name>")
+	}
+	kind, name := args[0], args[1]
+	if err := fs.Parse(args[2:]); err != nil {
+		return err
+	}
+	if err := opts.init(true); err != nil {
+		return err
+	}
+	return unstick(ctx, opts, kind, name, force)
+}
+
+// unstick removes the operator finalizer of the instance or binding if it no longer exists in Service Manager, or if force is set
+func unstick(ctx context.Context, opts *options, kind, name string, force bool) error {
+	key := types.NamespacedName{Name: name, Namespace: opts.namespace}
+	var obj client.Object
+	var existsInSM func() (bool, error)
+	if kind == "instance" {
+		instance := &v1.ServiceInstance{}
+		if err := opts.k8sClient.Get(ctx, key, instance); err != nil {
+			return err
+		}
+		obj = instance
+		existsInSM = func() (bool, error) {
+			if len(instance.Status.InstanceID) == 0 {
+				return false, nil
+			}
+			smClient, err := opts.smClientForInstance(ctx, instance)
+			if err != nil {
+				return false, err
+			}
+			_, err = smClient.GetInstanceByID(instance.Status.InstanceID, nil)
+			return smResourceExists(err)
+		}
+	} else {
+		binding := &v1.ServiceBinding{}
+		if err := opts.k8sClient.Get(ctx, key, binding); err != nil {
+			return err
+		}
+		obj = binding
+		existsInSM = func() (bool, error) {
+			if len(binding.Status.BindingID) == 0 {
+				return false, nil
+			}
+			smClient, err := opts.smClientForInstance(ctx, bindingInstance(ctx, opts, binding))
+			if err != nil {
+				return false, err
+			}
+			_, err = smClient.GetBindingByID(binding.Status.BindingID, nil)
+			return smResourceExists(err)
+		}
+	}
+
+	if obj.GetDeletionTimestamp() == nil {
+		return fmt.Errorf("%s %s is not being deleted, delete it first", kind, name)
+	}
+	if !controllerutil.ContainsFinalizer(obj, common.FinalizerName) {
+		return fmt.Errorf("%s %s has no %s finalizer", kind, name, common.FinalizerName)
+	}
+
+	if !force {
+		exists, err := existsInSM()
+		if err != nil {
+			return fmt.Errorf("failed to check the %s in Service Manager, use --force to remove the finalizer anyway: %w", kind, err)
+		}
+		if exists {
+			return fmt.Errorf("the %s still exists in Service Manager, removing the finalizer would leave it orphaned, use --force to remove it anyway", kind)
+		}
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	controllerutil.RemoveFinalizer(obj, common.FinalizerName)
+	if err := opts.k8sClient.Patch(ctx, obj, patch); err != nil {
+		return err
+	}
+	fmt.Printf("service%s/%s -n %s finalizer removed\n", kind, name, opts.namespace)
+	return nil
+}
+
+// bindingInstance returns the instance of the binding, or a placeholder in the instance namespace
+// that resolves the namespace credentials when the instance was already deleted
+func bindingInstance(ctx context.Context, opts *options, binding *v1.ServiceBinding) *v1.ServiceInstance {
+	namespace := binding.Namespace
+	if len(binding.Spec.ServiceInstanceNamespace) > 0 {
+		namespace = binding.Spec.ServiceInstanceNamespace
+	}
+	instance := &v1.ServiceInstance{}
+	if err := opts.k8sClient.Get(ctx, types.NamespacedName{Name: binding.Spec.ServiceInstanceName, Namespace: namespace}, instance); err != nil {
+		instance = &v1.ServiceInstance{}
+		instance.Namespace = namespace
+	}
+	return instance
+}
+
+// smResourceExists interprets the error of a Service Manager get request
+func smResourceExists(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	var smError *sm.ServiceManagerError
+	if errors.As(err, &smError) && (smError.StatusCode == http.StatusNotFound || smError.StatusCode == http.StatusGone) {
+		return false, nil
+	}
+	return false, err
+}
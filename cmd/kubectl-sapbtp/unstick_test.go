@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	"github.com/SAP/sap-btp-service-operator/client/sm"
+	"github.com/SAP/sap-btp-service-operator/client/sm/smfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Unstick", func() {
+	const namespace = "ns1"
+
+	var (
+		ctx      context.Context
+		smClient *smfakes.FakeClient
+	)
+
+	deletingMeta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			Finalizers:        []string{common.FinalizerName},
+			DeletionTimestamp: &metav1.Time{Time: time.Now()},
+		}
+	}
+
+	newOptions := func(objects ...client.Object) *options {
+		return &options{
+			namespace: namespace,
+			k8sClient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+			newSMClient: func(ctx context.Context, instance *v1.ServiceInstance) (sm.Client, error) {
+				return smClient, nil
+			},
+		}
+	}
+
+	expectRemoved := func(opts *options, obj client.Object) {
+		err := opts.k8sClient.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: namespace}, obj)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	}
+
+	expectStuck := func(opts *options, obj client.Object) {
+		Expect(opts.k8sClient.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: namespace}, obj)).To(Succeed())
+		Expect(obj.GetFinalizers()).To(ConsistOf(common.FinalizerName))
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		smClient = &smfakes.FakeClient{}
+	})
+
+	DescribeTable("smResourceExists",
+		func(err error, expectedExists bool, expectedErr bool) {
+			exists, checkErr := smResourceExists(err)
+			Expect(exists).To(Equal(expectedExists))
+			if expectedErr {
+				Expect(checkErr).To(MatchError(err))
+			} else {
+				Expect(checkErr).ToNot(HaveOccurred())
+			}
+		},
+		Entry("found", nil, true, false),
+		Entry("not found", &sm.ServiceManagerError{StatusCode: http.StatusNotFound}, false, false),
+		Entry("gone", &sm.ServiceManagerError{StatusCode: http.StatusGone}, false, false),
+		Entry("wrapped not found", fmt.Errorf("get failed: %w", &sm.ServiceManagerError{StatusCode: http.StatusNotFound}), false, false),
+		Entry("server error", &sm.ServiceManagerError{StatusCode: http.StatusInternalServerError}, false, true),
+		Entry("transport error", fmt.Errorf("connection refused"), false, true),
+	)
+
+	Context("instance", func() {
+		var instance *v1.ServiceInstance
+
+		BeforeEach(func() {
+			instance = &v1.ServiceInstance{ObjectMeta: deletingMeta("instance")}
+			instance.Status.InstanceID = "instance-id"
+		})
+
+		DescribeTable("removing the finalizer",
+			func(getErr error, force bool, expectedErr string) {
+				smClient.GetInstanceByIDReturns(nil, getErr)
+				opts := newOptions(instance)
+
+				err := unstick(ctx, opts, "instance", "instance", force)
+				if len(expectedErr) > 0 {
+					Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+					expectStuck(opts, &v1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Name: "instance"}})
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+					expectRemoved(opts, &v1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Name: "instance"}})
+				}
+			},
+			Entry("removes it when the instance no longer exists in SM", &sm.ServiceManagerError{StatusCode: http.StatusNotFound}, false, ""),
+			Entry("keeps it when the instance still exists in SM", nil, false, "still exists in Service Manager"),
+			Entry("keeps it when SM can't be checked", &sm.ServiceManagerError{StatusCode: http.StatusBadGateway}, false, "failed to check the instance in Service Manager"),
+			Entry("removes it with force when the instance still exists in SM", nil, true, ""),
+		)
+
+		It("should not call SM when the instance was never created", func() {
+			instance.Status.InstanceID = ""
+			opts := newOptions(instance)
+			Expect(unstick(ctx, opts, "instance", "instance", false)).To(Succeed())
+			Expect(smClient.GetInstanceByIDCallCount()).To(BeZero())
+			expectRemoved(opts, &v1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Name: "instance"}})
+		})
+
+		It("should fail when the instance is not being deleted", func() {
+			instance.DeletionTimestamp = nil
+			opts := newOptions(instance)
+			Expect(unstick(ctx, opts, "instance", "instance", true)).To(MatchError(ContainSubstring("is not being deleted")))
+		})
+	})
+
+	Context("binding", func() {
+		var binding *v1.ServiceBinding
+
+		BeforeEach(func() {
+			binding = &v1.ServiceBinding{ObjectMeta: deletingMeta("binding")}
+			binding.Spec.ServiceInstanceName = "instance"
+			binding.Status.BindingID = "binding-id"
+		})
+
+		It("should remove the finalizer when the binding no longer exists in SM", func() {
+			smClient.GetBindingByIDReturns(nil, &sm.ServiceManagerError{StatusCode: http.StatusNotFound})
+			opts := newOptions(binding)
+			Expect(unstick(ctx, opts, "binding", "binding", false)).To(Succeed())
+			Expect(smClient.GetBindingByIDArgsForCall(0)).To(Equal("binding-id"))
+			expectRemoved(opts, &v1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "binding"}})
+		})
+
+		It("should keep the finalizer when the binding still exists in SM", func() {
+			opts := newOptions(binding)
+			Expect(unstick(ctx, opts, "binding", "binding", false)).To(MatchError(ContainSubstring("still exists in Service Manager")))
+			expectStuck(opts, &v1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "binding"}})
+		})
+	})
+})
@@ -18,7 +18,11 @@ package controllers
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"encoding/json"
+	"maps"
+	"reflect"
 	"strings"
 	"time"
 
@@ -55,8 +59,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 )
 
 const (
@@ -67,17 +73,22 @@ const (
 // ServiceBindingReconciler reconciles a ServiceBinding object
 type ServiceBindingReconciler struct {
 	client.Client
-	Log         logr.Logger
-	Scheme      *runtime.Scheme
-	GetSMClient func(ctx context.Context, instance *v1.ServiceInstance) (sm.Client, error)
-	Config      config.Config
-	Recorder    record.EventRecorder
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	GetSMClient   func(ctx context.Context, instance *v1.ServiceInstance) (sm.Client, error)
+	GetSecretSink func(ctx context.Context, binding *v1.ServiceBinding) (utils.SecretSink, error)
+	Config        config.Config
+	Recorder      record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=services.cloud.sap.com,resources=servicebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=services.cloud.sap.com,resources=servicebindings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=services.cloud.sap.com,resources=secrettemplates;clustersecrettemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=services.cloud.sap.com,resources=servicebindinggrants,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;patch
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update
 
 func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -94,6 +105,10 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	serviceBinding = serviceBinding.DeepCopy()
 	log.Info(fmt.Sprintf("Current generation is %v and observed is %v", serviceBinding.Generation, common.GetObservedGeneration(serviceBinding)))
 
+	if paused, err := utils.HandlePaused(ctx, r.Client, serviceBinding, r.Config.AllowDeleteWhenPaused); err != nil || paused {
+		return ctrl.Result{}, err
+	}
+
 	if len(serviceBinding.GetConditions()) == 0 {
 		if err := utils.InitConditions(ctx, r.Client, serviceBinding); err != nil {
 			return ctrl.Result{}, err
@@ -148,12 +163,30 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{Requeue: true}, utils.UpdateStatus(ctx, r.Client, serviceBinding)
 	}
 
+	// a created binding whose grant was revoked is not deleted in SM, it is blocked until the grant is restored,
+	// stale bindings are still deleted
+	if r.Config.EnforceServiceBindingGrants && !isStaleServiceBinding(serviceBinding) {
+		if err := v1.ValidateServiceBindingGrant(ctx, r.Client, serviceBinding); err != nil {
+			log.Error(err, "service binding grant validation failed")
+			utils.SetBlockedCondition(ctx, err.Error(), serviceBinding)
+			return ctrl.Result{}, utils.UpdateStatus(ctx, r.Client, serviceBinding)
+		}
+		if len(serviceBinding.Status.BindingID) > 0 && isBlocked(serviceBinding) {
+			log.Info("service binding grant was restored, unblocking binding")
+			utils.SetSuccessConditions(smClientTypes.CREATE, serviceBinding, false)
+			return ctrl.Result{}, utils.UpdateStatus(ctx, r.Client, serviceBinding)
+		}
+	}
+
 	// should rotate creds
 	if meta.IsStatusConditionTrue(serviceBinding.Status.Conditions, common.ConditionCredRotationInProgress) {
 		log.Info("rotating credentials")
 		if err := r.rotateCredentials(ctx, serviceBinding, serviceInstance); err != nil {
 			return ctrl.Result{}, err
 		}
+		if isRollingOutWorkloads(serviceBinding) {
+			return ctrl.Result{RequeueAfter: r.Config.PollInterval}, nil
+		}
 	}
 
 	// is binding ready
@@ -215,6 +248,11 @@ func (r *ServiceBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1.ServiceBinding{}).
+		Owns(&corev1.Secret{}).
+		Watches(&v1.SecretTemplate{}, handler.EnqueueRequestsFromMapFunc(r.bindingsForSecretTemplate)).
+		Watches(&v1.ClusterSecretTemplate{}, handler.EnqueueRequestsFromMapFunc(r.bindingsForSecretTemplate)).
+		Watches(&v1.ServiceBindingGrant{}, handler.EnqueueRequestsFromMapFunc(r.bindingsForServiceBindingGrant)).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.bindingsForNamespace), builder.WithPredicates(utils.PausedAnnotationChanged())).
 		WithOptions(controller.Options{RateLimiter: workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](r.Config.RetryBaseDelay, r.Config.RetryMaxDelay)}).
 		Complete(r)
 }
@@ -229,21 +267,25 @@ func (r *ServiceBindingReconciler) createBinding(ctx context.Context, smClient s
 		return utils.MarkAsNonTransientError(ctx, r.Client, smClientTypes.CREATE, err, serviceBinding)
 	}
 
+	smLabels := utils.GetSyncedSMLabels(serviceBinding, r.Config)
 	smBinding, operationURL, bindErr := smClient.Bind(&smClientTypes.ServiceBinding{
 		Name: serviceBinding.Spec.ExternalName,
-		Labels: smClientTypes.Labels{
+		Labels: utils.AddSyncedSMLabels(smClientTypes.Labels{
 			common.NamespaceLabel: []string{serviceBinding.Namespace},
 			common.K8sNameLabel:   []string{serviceBinding.Name},
 			common.ClusterIDLabel: []string{r.Config.ClusterID},
-		},
+		}, smLabels),
 		ServiceInstanceID: serviceInstance.Status.InstanceID,
 		Parameters:        bindingParameters,
 	}, nil, utils.BuildUserInfo(ctx, serviceBinding.Spec.UserInfo))
 
 	if bindErr != nil {
 		log.Error(err, "failed to create service binding", "serviceInstanceID", serviceInstance.Status.InstanceID)
+		utils.RecordOperationCompleted(serviceBinding, smClientTypes.CREATE, "", smClientTypes.FAILED, bindErr.Error())
+		utils.RecordOperationEvent(r.Recorder, serviceBinding, smClientTypes.CREATE, smClientTypes.FAILED, bindErr.Error())
 		return utils.HandleError(ctx, r.Client, smClientTypes.CREATE, bindErr, serviceBinding)
 	}
+	serviceBinding.Status.SyncedLabels = smLabels
 
 	if operationURL != "" {
 		var bindingID string
@@ -255,6 +297,8 @@ func (r *ServiceBindingReconciler) createBinding(ctx context.Context, smClient s
 		log.Info("Create smBinding request is async")
 		serviceBinding.Status.OperationURL = operationURL
 		serviceBinding.Status.OperationType = smClientTypes.CREATE
+		utils.RecordOperationStarted(serviceBinding, smClientTypes.CREATE, operationURL)
+		utils.RecordOperationEvent(r.Recorder, serviceBinding, smClientTypes.CREATE, smClientTypes.INPROGRESS, "creating binding in SM")
 		utils.SetInProgressConditions(ctx, smClientTypes.CREATE, "", serviceBinding, false)
 		if err := utils.UpdateStatus(ctx, r.Client, serviceBinding); err != nil {
 			log.Error(err, "unable to update ServiceBinding status")
@@ -264,6 +308,8 @@ func (r *ServiceBindingReconciler) createBinding(ctx context.Context, smClient s
 	}
 
 	log.Info("Binding created successfully")
+	utils.RecordOperationCompleted(serviceBinding, smClientTypes.CREATE, "", smClientTypes.SUCCEEDED, "")
+	utils.RecordOperationEvent(r.Recorder, serviceBinding, smClientTypes.CREATE, smClientTypes.SUCCEEDED, fmt.Sprintf("binding %s created successfully", smBinding.ID))
 
 	if err := r.storeBindingSecret(ctx, serviceBinding, smBinding); err != nil {
 		return r.handleSecretError(ctx, smClientTypes.CREATE, err, serviceBinding)
@@ -324,6 +370,8 @@ func (r *ServiceBindingReconciler) delete(ctx context.Context, serviceBinding *v
 		log.Info(fmt.Sprintf("Deleting binding with id %v from SM", serviceBinding.Status.BindingID))
 		operationURL, unbindErr := smClient.Unbind(serviceBinding.Status.BindingID, nil, utils.BuildUserInfo(ctx, serviceBinding.Spec.UserInfo))
 		if unbindErr != nil {
+			utils.RecordOperationCompleted(serviceBinding, smClientTypes.DELETE, "", smClientTypes.FAILED, unbindErr.Error())
+			utils.RecordOperationEvent(r.Recorder, serviceBinding, smClientTypes.DELETE, smClientTypes.FAILED, unbindErr.Error())
 			return utils.HandleDeleteError(ctx, r.Client, unbindErr, serviceBinding)
 		}
 
@@ -331,6 +379,8 @@ func (r *ServiceBindingReconciler) delete(ctx context.Context, serviceBinding *v
 			log.Info("Deleting binding async")
 			serviceBinding.Status.OperationURL = operationURL
 			serviceBinding.Status.OperationType = smClientTypes.DELETE
+			utils.RecordOperationStarted(serviceBinding, smClientTypes.DELETE, operationURL)
+			utils.RecordOperationEvent(r.Recorder, serviceBinding, smClientTypes.DELETE, smClientTypes.INPROGRESS, "deleting binding in SM")
 			utils.SetInProgressConditions(ctx, smClientTypes.DELETE, "", serviceBinding, false)
 			if err := utils.UpdateStatus(ctx, r.Client, serviceBinding); err != nil {
 				return ctrl.Result{}, err
@@ -339,6 +389,7 @@ func (r *ServiceBindingReconciler) delete(ctx context.Context, serviceBinding *v
 		}
 
 		log.Info("Binding was deleted successfully")
+		utils.RecordOperationEvent(r.Recorder, serviceBinding, smClientTypes.DELETE, smClientTypes.SUCCEEDED, "binding deleted successfully")
 		return r.deleteSecretAndRemoveFinalizer(ctx, serviceBinding)
 	}
 	return ctrl.Result{}, nil
@@ -359,6 +410,7 @@ func (r *ServiceBindingReconciler) poll(ctx context.Context, serviceBinding *v1.
 		utils.SetInProgressConditions(ctx, serviceBinding.Status.OperationType, string(smClientTypes.INPROGRESS), serviceBinding, false)
 		freshStatus := v1.ServiceBindingStatus{
 			Conditions: serviceBinding.GetConditions(),
+			Operations: serviceBinding.Status.Operations,
 		}
 		if utils.IsMarkedForDeletion(serviceBinding.ObjectMeta) {
 			freshStatus.BindingID = serviceBinding.Status.BindingID
@@ -378,6 +430,10 @@ func (r *ServiceBindingReconciler) poll(ctx context.Context, serviceBinding *v1.
 		fallthrough
 	case smClientTypes.PENDING:
 		if len(status.Description) != 0 {
+			if lastOpCondition := meta.FindStatusCondition(serviceBinding.GetConditions(), common.ConditionSucceeded); lastOpCondition == nil || lastOpCondition.Message != status.Description {
+				// the description is recorded once, not on every poll
+				utils.RecordOperationEvent(r.Recorder, serviceBinding, serviceBinding.Status.OperationType, status.State, status.Description)
+			}
 			utils.SetInProgressConditions(ctx, status.Type, status.Description, serviceBinding, true)
 			if err := utils.UpdateStatus(ctx, r.Client, serviceBinding); err != nil {
 				log.Error(err, "unable to update ServiceBinding polling description")
@@ -387,6 +443,8 @@ func (r *ServiceBindingReconciler) poll(ctx context.Context, serviceBinding *v1.
 		return ctrl.Result{Requeue: true, RequeueAfter: r.Config.PollInterval}, nil
 	case smClientTypes.FAILED:
 		// non transient error - should not retry
+		utils.RecordOperationCompleted(serviceBinding, serviceBinding.Status.OperationType, status.ID, smClientTypes.FAILED, status.Description)
+		utils.RecordOperationEvent(r.Recorder, serviceBinding, serviceBinding.Status.OperationType, smClientTypes.FAILED, status.Description)
 		utils.SetFailureConditions(status.Type, status.Description, serviceBinding, true)
 		if serviceBinding.Status.OperationType == smClientTypes.DELETE {
 			serviceBinding.Status.OperationURL = ""
@@ -402,6 +460,8 @@ func (r *ServiceBindingReconciler) poll(ctx context.Context, serviceBinding *v1.
 			return ctrl.Result{}, errors.New(errMsg)
 		}
 	case smClientTypes.SUCCEEDED:
+		utils.RecordOperationCompleted(serviceBinding, serviceBinding.Status.OperationType, status.ID, smClientTypes.SUCCEEDED, "")
+		utils.RecordOperationEvent(r.Recorder, serviceBinding, serviceBinding.Status.OperationType, smClientTypes.SUCCEEDED, fmt.Sprintf("operation %s finished successfully", status.ID))
 		utils.SetSuccessConditions(status.Type, serviceBinding, true)
 		switch serviceBinding.Status.OperationType {
 		case smClientTypes.CREATE:
@@ -433,9 +493,9 @@ func (r *ServiceBindingReconciler) poll(ctx context.Context, serviceBinding *v1.
 func (r *ServiceBindingReconciler) getBindingForRecovery(ctx context.Context, smClient sm.Client, serviceBinding *v1.ServiceBinding) (*smClientTypes.ServiceBinding, error) {
 	log := utils.GetLogger(ctx)
 	nameQuery := fmt.Sprintf("name eq '%s'", serviceBinding.Spec.ExternalName)
-	clusterIDQuery := fmt.Sprintf("context/clusterid eq '%s'", r.Config.ClusterID)
 	namespaceQuery := fmt.Sprintf("context/namespace eq '%s'", serviceBinding.Namespace)
 	k8sNameQuery := fmt.Sprintf("%s eq '%s'", common.K8sNameLabel, serviceBinding.Name)
+	clusterIDQuery := fmt.Sprintf("context/clusterid eq '%s'", r.Config.ClusterID)
 	parameters := sm.Parameters{
 		FieldQuery:    []string{nameQuery, clusterIDQuery, namespaceQuery},
 		LabelQuery:    []string{k8sNameQuery},
@@ -448,6 +508,17 @@ func (r *ServiceBindingReconciler) getBindingForRecovery(ctx context.Context, sm
 		log.Error(err, "failed to list bindings in SM")
 		return nil, err
 	}
+	if (bindings == nil || len(bindings.ServiceBindings) == 0) && r.Config.EnableClusterIDMigration {
+		// the context of a migrated binding keeps the cluster ID it was created with, only its cluster ID label is changed
+		clusterIDLabelQuery := fmt.Sprintf("%s eq '%s'", common.ClusterIDLabel, r.Config.ClusterID)
+		log.Info(fmt.Sprintf("binding not found in SM by context, querying by cluster ID label: %s", clusterIDLabelQuery))
+		parameters.FieldQuery = []string{nameQuery, namespaceQuery}
+		parameters.LabelQuery = []string{k8sNameQuery, clusterIDLabelQuery}
+		if bindings, err = smClient.ListBindings(&parameters); err != nil {
+			log.Error(err, "failed to list bindings in SM")
+			return nil, err
+		}
+	}
 	if bindings != nil {
 		log.Info(fmt.Sprintf("found %d bindings", len(bindings.ServiceBindings)))
 		if len(bindings.ServiceBindings) == 1 {
@@ -464,21 +535,98 @@ func (r *ServiceBindingReconciler) maintain(ctx context.Context, binding *v1.Ser
 		return r.handleSecretError(ctx, smClientTypes.UPDATE, err, binding)
 	}
 
+	if smLabels := utils.GetSyncedSMLabels(binding, r.Config); !maps.Equal(smLabels, binding.Status.SyncedLabels) {
+		if err := r.syncSMLabels(ctx, binding, instance, smLabels); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	nextRotationTime := getNextCredRotationTime(binding)
+	if !nextRotationTimeEqual(binding.Status.NextCredentialsRotationTime, nextRotationTime) {
+		log.Info(fmt.Sprintf("next credentials rotation time is %v", nextRotationTime))
+		binding.Status.NextCredentialsRotationTime = nextRotationTime
+		if err := utils.UpdateStatus(ctx, r.Client, binding); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	log.Info("maintain finished successfully")
-	return ctrl.Result{}, nil
+	// requeue exactly when the credentials should be rotated or the stale binding should be deleted
+	return requeueAt(nextRotationTime, getStaleBindingExpirationTime(binding)), nil
+}
+
+// syncSMLabels updates the labels of the binding in SM with the labels mapped from its labels and annotations
+func (r *ServiceBindingReconciler) syncSMLabels(ctx context.Context, binding *v1.ServiceBinding, instance *v1.ServiceInstance, smLabels map[string]string) error {
+	log := utils.GetLogger(ctx)
+	smClient, err := r.GetSMClient(ctx, instance)
+	if err != nil {
+		log.Error(err, "failed to get sm client")
+		return err
+	}
+
+	changes := utils.GetSMLabelChanges(binding.Status.SyncedLabels, smLabels)
+	log.Info(fmt.Sprintf("syncing %d label changes to SM", len(changes)))
+	if _, err := smClient.UpdateBindingLabels(binding.Status.BindingID, changes); err != nil {
+		log.Error(err, "failed to update the labels of the binding in SM")
+		r.Recorder.Event(binding, corev1.EventTypeWarning, common.LabelSyncFailed, err.Error())
+		return err
+	}
+	binding.Status.SyncedLabels = smLabels
+	return utils.UpdateStatus(ctx, r.Client, binding)
 }
 
 func (r *ServiceBindingReconciler) maintainSecret(ctx context.Context, serviceBinding *v1.ServiceBinding, serviceInstance *v1.ServiceInstance) error {
 	log := utils.GetLogger(ctx)
 	if common.GetObservedGeneration(serviceBinding) == serviceBinding.Generation {
 		log.Info("observed generation is up to date, checking if secret exists")
-		if _, err := r.getSecret(ctx, serviceBinding.Namespace, serviceBinding.Spec.SecretName); err == nil {
-			log.Info("secret exists, no need to maintain secret")
-			return nil
+		sink, err := r.GetSecretSink(ctx, serviceBinding)
+		if err != nil {
+			return err
+		}
+		if secret, err := sink.Get(ctx, serviceBinding); err == nil {
+			templateChanged, err := r.secretTemplateChanged(ctx, serviceBinding, secret)
+			if err != nil {
+				return err
+			}
+			// a secret written by an older version of the operator has no data hash, the hash of its current data is backfilled
+			// instead of regenerating it from SM
+			if _, hashed := secret.Annotations[common.SecretDataHashAnnotation]; !hashed && !templateChanged {
+				log.Info("secret has no data hash, backfilling it")
+				if secret.Annotations == nil {
+					secret.Annotations = map[string]string{}
+				}
+				secret.Annotations[common.SecretDataHashAnnotation] = getSecretHash(secret)
+				if _, err := sink.Store(ctx, serviceBinding, secret); err != nil {
+					return err
+				}
+			}
+			drifted := secretDataDrifted(secret)
+			if !templateChanged && (!drifted || serviceBinding.Spec.SecretDriftPolicy == v1.SecretDriftPolicyReport) {
+				log.Info("secret exists, no need to maintain secret")
+				statusChanged := setSecretDriftedCondition(serviceBinding, secret, drifted)
+				if statusChanged && drifted {
+					r.Recorder.Event(serviceBinding, corev1.EventTypeWarning, common.SecretDataChanged, fmt.Sprintf("the data of secret %s was edited", secret.Name))
+				}
+				if setSecretReference(serviceBinding, sink) {
+					statusChanged = true
+				}
+				if statusChanged {
+					return utils.UpdateStatus(ctx, r.Client, serviceBinding)
+				}
+				return nil
+			}
+			if templateChanged {
+				log.Info("referenced secret template changed")
+			} else {
+				log.Info("secret data was edited, restoring it")
+				r.Recorder.Event(serviceBinding, corev1.EventTypeWarning, common.SecretDataChanged, fmt.Sprintf("the data of secret %s was edited, restoring it", secret.Name))
+			}
+		} else if apierrors.IsNotFound(err) {
+			log.Info("binding's secret was not found")
+			r.Recorder.Event(serviceBinding, corev1.EventTypeWarning, "SecretDeleted", "SecretDeleted")
+		} else {
+			return err
 		}
-
-		log.Info("binding's secret was not found")
-		r.Recorder.Event(serviceBinding, corev1.EventTypeWarning, "SecretDeleted", "SecretDeleted")
 	}
 
 	log.Info("maintaining binding's secret")
@@ -492,13 +640,13 @@ func (r *ServiceBindingReconciler) maintainSecret(ctx context.Context, serviceBi
 		return err
 	}
 	if smBinding != nil {
-		if smBinding.Credentials != nil {
-			if err = r.storeBindingSecret(ctx, serviceBinding, smBinding); err != nil {
-				return err
-			}
-			log.Info("Updating binding", "bindingID", smBinding.ID)
-			utils.SetSuccessConditions(smClientTypes.UPDATE, serviceBinding, false)
+		// the secret of a binding with empty credentials is written too, so that it has a data hash and is not regenerated again
+		if err = r.storeBindingSecret(ctx, serviceBinding, smBinding); err != nil {
+			return err
 		}
+		log.Info("Updating binding", "bindingID", smBinding.ID)
+		utils.SetSuccessConditions(smClientTypes.UPDATE, serviceBinding, false)
+		setSecretDriftedCondition(serviceBinding, nil, false)
 	}
 
 	return utils.UpdateStatus(ctx, r.Client, serviceBinding)
@@ -570,7 +718,7 @@ func (r *ServiceBindingReconciler) storeBindingSecret(ctx context.Context, k8sBi
 	var secret *corev1.Secret
 	var err error
 
-	if k8sBinding.Spec.SecretTemplate != "" {
+	if k8sBinding.Spec.SecretTemplate != "" || k8sBinding.Spec.SecretTemplateRef != nil {
 		secret, err = r.createBindingSecretFromSecretTemplate(ctx, k8sBinding, smBinding)
 	} else {
 		secret, err = r.createBindingSecret(ctx, k8sBinding, smBinding)
@@ -594,7 +742,42 @@ func (r *ServiceBindingReconciler) storeBindingSecret(ctx context.Context, k8sBi
 	}
 	secret.Annotations["binding"] = k8sBinding.Name
 
-	return r.createOrUpdateBindingSecret(ctx, k8sBinding, secret)
+	if k8sBinding.Spec.ServiceBindingIO != nil {
+		if err = r.setServiceBindingIOEntries(ctx, k8sBinding, secret); err != nil {
+			logger.Error(err, "Failed to set the servicebinding.io entries of the secret")
+			return err
+		}
+	}
+	secret.Annotations[common.SecretDataHashAnnotation] = getSecretHash(secret)
+
+	sink, err := r.GetSecretSink(ctx, k8sBinding)
+	if err != nil {
+		logger.Error(err, "Failed to get the secret sink")
+		return err
+	}
+	created, err := sink.Store(ctx, k8sBinding, secret)
+	if err != nil {
+		return err
+	}
+	if created {
+		r.Recorder.Event(k8sBinding, corev1.EventTypeNormal, "SecretCreated", "SecretCreated")
+	}
+	setSecretReference(k8sBinding, sink)
+	return nil
+}
+
+// setServiceBindingIOEntries makes the secret compliant with the Service Binding for Kubernetes specification
+func (r *ServiceBindingReconciler) setServiceBindingIOEntries(ctx context.Context, k8sBinding *v1.ServiceBinding, secret *corev1.Secret) error {
+	instance, err := r.getServiceInstanceForBinding(ctx, k8sBinding)
+	if err != nil {
+		return err
+	}
+
+	bindingType, provider := getServiceBindingIOEntries(k8sBinding, instance)
+	secret.Type = corev1.SecretType(common.ServiceBindingIOSecretTypePrefix + bindingType)
+	setSecretEntry(secret, "type", bindingType)
+	setSecretEntry(secret, "provider", provider)
+	return nil
 }
 
 func (r *ServiceBindingReconciler) createBindingSecret(ctx context.Context, k8sBinding *v1.ServiceBinding, smBinding *smClientTypes.ServiceBinding) (*corev1.Secret, error) {
@@ -635,6 +818,13 @@ func (r *ServiceBindingReconciler) getSecretDefaultData(ctx context.Context, k8s
 				Container: true,
 			},
 		}
+	} else if k8sBinding.Spec.SecretFormat != nil {
+		var err error
+		credentialsMap, credentialProperties, err = utils.FormatCredentials(smBinding.Credentials, k8sBinding.Spec.SecretFormat)
+		if err != nil {
+			log.Error(err, "Failed to store binding secret")
+			return nil, fmt.Errorf("failed to create secret. Error: %v", err.Error())
+		}
 	} else {
 		var err error
 		credentialsMap, credentialProperties, err = utils.NormalizeCredentials(smBinding.Credentials)
@@ -675,6 +865,12 @@ func (r *ServiceBindingReconciler) createBindingSecretFromSecretTemplate(ctx con
 	logger := log.WithValues("bindingName", k8sBinding.Name, "secretName", k8sBinding.Spec.SecretName)
 
 	logger.Info("Create Object using SecretTemplate from ServiceBinding Specs")
+	secretTemplate, err := r.getSecretTemplate(ctx, k8sBinding)
+	if err != nil {
+		logger.Error(err, "failed to get the secret template")
+		return nil, err
+	}
+
 	inputSmCredentials := smBinding.Credentials
 	smBindingCredentials := make(map[string]interface{})
 	if inputSmCredentials != nil {
@@ -685,15 +881,23 @@ func (r *ServiceBindingReconciler) createBindingSecretFromSecretTemplate(ctx con
 		}
 	}
 
-	instanceInfos, err := r.getInstanceInfo(ctx, k8sBinding)
+	serviceInstance, err := r.getServiceInstanceForBinding(ctx, k8sBinding)
+	if err != nil {
+		logger.Error(err, "failed to addInstanceInfo")
+		return nil, errors.Wrap(err, "failed to add service instance info")
+	}
+	instanceInfos, err := getInstanceInfo(serviceInstance)
 	if err != nil {
 		logger.Error(err, "failed to addInstanceInfo")
 		return nil, errors.Wrap(err, "failed to add service instance info")
 	}
 
-	parameters := commonutils.GetSecretDataForTemplate(smBindingCredentials, instanceInfos)
+	parameters := commonutils.GetSecretDataForTemplate(smBindingCredentials, instanceInfos, getBindingInfo(k8sBinding, smBinding), getSubaccountInfo(k8sBinding, serviceInstance, smBinding))
+	if secretTemplate.parameters != nil {
+		parameters[common.ParametersKey] = secretTemplate.parameters
+	}
 	templateName := fmt.Sprintf("%s/%s", k8sBinding.Namespace, k8sBinding.Name)
-	secret, err := commonutils.CreateSecretFromTemplate(templateName, k8sBinding.Spec.SecretTemplate, "missingkey=error", parameters)
+	secret, err := commonutils.CreateSecretFromTemplate(templateName, secretTemplate.text, "missingkey=error", parameters)
 	if err != nil {
 		logger.Error(err, "failed to create secret from template")
 		return nil, errors.Wrap(err, "failed to create secret from template")
@@ -704,6 +908,12 @@ func (r *ServiceBindingReconciler) createBindingSecretFromSecretTemplate(ctx con
 		secret.Labels = map[string]string{}
 	}
 	secret.Labels[common.ManagedByBTPOperatorLabel] = "true"
+	if len(secretTemplate.hash) > 0 {
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[common.SecretTemplateHashAnnotation] = secretTemplate.hash
+	}
 
 	// if no data provided use the default data
 	if len(secret.Data) == 0 && len(secret.StringData) == 0 {
@@ -716,57 +926,15 @@ func (r *ServiceBindingReconciler) createBindingSecretFromSecretTemplate(ctx con
 	return secret, nil
 }
 
-func (r *ServiceBindingReconciler) createOrUpdateBindingSecret(ctx context.Context, binding *v1.ServiceBinding, secret *corev1.Secret) error {
-	log := utils.GetLogger(ctx)
-	dbSecret := &corev1.Secret{}
-	create := false
-	if err := r.Client.Get(ctx, types.NamespacedName{Name: binding.Spec.SecretName, Namespace: binding.Namespace}, dbSecret); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return err
-		}
-		create = true
-	}
-
-	if create {
-		log.Info("Creating binding secret", "name", secret.Name)
-		if err := r.Client.Create(ctx, secret); err != nil {
-			if !apierrors.IsAlreadyExists(err) {
-				return err
-			}
-			return nil
-		}
-		r.Recorder.Event(binding, corev1.EventTypeNormal, "SecretCreated", "SecretCreated")
-		return nil
-	}
-
-	log.Info("Updating existing binding secret", "name", secret.Name)
-	dbSecret.Data = secret.Data
-	dbSecret.StringData = secret.StringData
-	dbSecret.Labels = secret.Labels
-	dbSecret.Annotations = secret.Annotations
-	return r.Client.Update(ctx, dbSecret)
-}
-
 func (r *ServiceBindingReconciler) deleteBindingSecret(ctx context.Context, binding *v1.ServiceBinding) error {
 	log := utils.GetLogger(ctx)
 	log.Info("Deleting binding secret")
-	bindingSecret := &corev1.Secret{}
-	if err := r.Client.Get(ctx, types.NamespacedName{
-		Namespace: binding.Namespace,
-		Name:      binding.Spec.SecretName,
-	}, bindingSecret); err != nil {
-		if !apierrors.IsNotFound(err) {
-			log.Error(err, "unable to fetch binding secret")
-			return err
-		}
-
-		// secret not found, nothing more to do
-		log.Info("secret was deleted successfully")
-		return nil
+	sink, err := r.GetSecretSink(ctx, binding)
+	if err != nil {
+		log.Error(err, "Failed to get the secret sink")
+		return err
 	}
-	bindingSecret = bindingSecret.DeepCopy()
-
-	if err := r.Client.Delete(ctx, bindingSecret); err != nil {
+	if err := sink.Delete(ctx, binding); err != nil {
 		log.Error(err, "Failed to delete binding secret")
 		return err
 	}
@@ -791,6 +959,28 @@ func (r *ServiceBindingReconciler) getSecret(ctx context.Context, namespace stri
 }
 
 func (r *ServiceBindingReconciler) validateSecretNameIsAvailable(ctx context.Context, binding *v1.ServiceBinding) error {
+	log := utils.GetLogger(ctx)
+	sink, err := r.GetSecretSink(ctx, binding)
+	if err != nil {
+		return err
+	}
+	if kvSink, ok := sink.(*utils.KVSecretSink); ok {
+		owner, err := kvSink.GetOtherOwner(ctx, binding)
+		if err != nil {
+			// the owner is checked again when the secret is stored
+			log.Info(fmt.Sprintf("failed to check the owner of the secret in the key-value store: %s", err.Error()))
+			return nil
+		}
+		if len(owner) > 0 {
+			return fmt.Errorf(secretAlreadyOwnedErrorFormat, binding.Spec.SecretName, owner)
+		}
+		return nil
+	}
+	if _, ok := sink.(*utils.KubernetesSecretSink); !ok {
+		// the secret is not stored in the namespace of the binding
+		return nil
+	}
+
 	currentSecret, err := r.getSecret(ctx, binding.Namespace, binding.Spec.SecretName)
 	if err != nil {
 		return client.IgnoreNotFound(err)
@@ -818,18 +1008,22 @@ func (r *ServiceBindingReconciler) validateSecretNameIsAvailable(ctx context.Con
 func (r *ServiceBindingReconciler) handleSecretError(ctx context.Context, op smClientTypes.OperationCategory, err error, binding *v1.ServiceBinding) (ctrl.Result, error) {
 	log := utils.GetLogger(ctx)
 	log.Error(err, fmt.Sprintf("failed to store secret %s for binding %s", binding.Spec.SecretName, binding.Name))
+	var sinkErr *utils.KVSecretSinkError
+	if errors.As(err, &sinkErr) {
+		if sinkErr.IsTransient() {
+			return utils.MarkAsTransientError(ctx, r.Client, op, err, binding)
+		}
+		return utils.MarkAsNonTransientError(ctx, r.Client, op, err, binding)
+	}
 	if apierrors.ReasonForError(err) == metav1.StatusReasonUnknown {
 		return utils.MarkAsNonTransientError(ctx, r.Client, op, err, binding)
 	}
 	return utils.MarkAsTransientError(ctx, r.Client, op, err, binding)
 }
 
-func (r *ServiceBindingReconciler) getInstanceInfo(ctx context.Context, binding *v1.ServiceBinding) (map[string]string, error) {
-	instance, err := r.getServiceInstanceForBinding(ctx, binding)
-	if err != nil {
-		return nil, err
-	}
-	instanceInfos := make(map[string]string)
+// getInstanceInfo returns the instance data of secret templates, the parameters from secrets are not included
+func getInstanceInfo(instance *v1.ServiceInstance) (map[string]interface{}, error) {
+	instanceInfos := make(map[string]interface{})
 	instanceInfos["instance_name"] = string(getInstanceNameForSecretCredentials(instance))
 	instanceInfos["instance_guid"] = instance.Status.InstanceID
 	instanceInfos["plan"] = instance.Spec.ServicePlanName
@@ -839,9 +1033,45 @@ func (r *ServiceBindingReconciler) getInstanceInfo(ctx context.Context, binding
 		tags := mergeInstanceTags(instance.Status.Tags, instance.Spec.CustomTags)
 		instanceInfos["tags"] = strings.Join(tags, ",")
 	}
+
+	parameters := make(map[string]interface{})
+	if instance.Spec.Parameters != nil && len(instance.Spec.Parameters.Raw) > 0 {
+		if err := json.Unmarshal(instance.Spec.Parameters.Raw, &parameters); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal service instance parameters")
+		}
+	}
+	instanceInfos["parameters"] = parameters
 	return instanceInfos, nil
 }
 
+// getBindingInfo returns the binding data of secret templates
+func getBindingInfo(binding *v1.ServiceBinding, smBinding *smClientTypes.ServiceBinding) map[string]interface{} {
+	lastRotationTime := ""
+	if binding.Status.LastCredentialsRotationTime != nil {
+		lastRotationTime = binding.Status.LastCredentialsRotationTime.UTC().Format(time.RFC3339)
+	}
+	return map[string]interface{}{
+		"name":                           binding.Name,
+		"namespace":                      binding.Namespace,
+		"external_name":                  binding.Spec.ExternalName,
+		"binding_id":                     smBinding.ID,
+		"labels":                         smBinding.Labels,
+		"last_credentials_rotation_time": lastRotationTime,
+	}
+}
+
+// getSubaccountInfo returns the subaccount data of secret templates
+func getSubaccountInfo(binding *v1.ServiceBinding, instance *v1.ServiceInstance, smBinding *smClientTypes.ServiceBinding) map[string]interface{} {
+	subaccountID := binding.Status.SubaccountID
+	if len(smBinding.Labels["subaccount_id"]) > 0 {
+		subaccountID = smBinding.Labels["subaccount_id"][0]
+	}
+	return map[string]interface{}{
+		"id":          subaccountID,
+		"data_center": instance.Spec.DataCenter,
+	}
+}
+
 func (r *ServiceBindingReconciler) addInstanceInfo(ctx context.Context, binding *v1.ServiceBinding, credentialsMap map[string][]byte) ([]utils.SecretMetadataProperty, error) {
 	instance, err := r.getServiceInstanceForBinding(ctx, binding)
 	if err != nil {
@@ -853,6 +1083,11 @@ func (r *ServiceBindingReconciler) addInstanceInfo(ctx context.Context, binding
 	credentialsMap["plan"] = []byte(instance.Spec.ServicePlanName)
 	credentialsMap["label"] = []byte(instance.Spec.ServiceOfferingName)
 	credentialsMap["type"] = []byte(instance.Spec.ServiceOfferingName)
+	if binding.Spec.ServiceBindingIO != nil {
+		bindingType, provider := getServiceBindingIOEntries(binding, instance)
+		credentialsMap["type"] = []byte(bindingType)
+		credentialsMap["provider"] = []byte(provider)
+	}
 	if len(instance.Status.Tags) > 0 || len(instance.Spec.CustomTags) > 0 {
 		tagsBytes, err := json.Marshal(mergeInstanceTags(instance.Status.Tags, instance.Spec.CustomTags))
 		if err != nil {
@@ -883,6 +1118,9 @@ func (r *ServiceBindingReconciler) addInstanceInfo(ctx context.Context, binding
 			Format: string(utils.TEXT),
 		},
 	}
+	if _, ok := credentialsMap["provider"]; ok {
+		metadata = append(metadata, utils.SecretMetadataProperty{Name: "provider", Format: string(utils.TEXT)})
+	}
 	if _, ok := credentialsMap["tags"]; ok {
 		metadata = append(metadata, utils.SecretMetadataProperty{Name: "tags", Format: string(utils.JSON)})
 	}
@@ -898,14 +1136,22 @@ func (r *ServiceBindingReconciler) rotateCredentials(ctx context.Context, bindin
 	}
 
 	credInProgressCondition := meta.FindStatusCondition(binding.GetConditions(), common.ConditionCredRotationInProgress)
+	if credInProgressCondition.Reason == common.CredRollingOut {
+		return r.waitForWorkloadsRollout(ctx, binding)
+	}
 	if credInProgressCondition.Reason == common.CredRotating {
 		if len(binding.Status.BindingID) > 0 && binding.Status.Ready == metav1.ConditionTrue {
-			log.Info("Credentials rotation - finished successfully")
 			now := metav1.NewTime(time.Now())
 			binding.Status.LastCredentialsRotationTime = &now
+			if len(getRolloutWorkloads(binding)) > 0 {
+				return r.rolloutWorkloads(ctx, binding)
+			}
+			log.Info("Credentials rotation - finished successfully")
+			r.Recorder.Event(binding, corev1.EventTypeNormal, common.CredRotated, "credentials rotated successfully")
 			return r.stopRotation(ctx, binding)
 		} else if utils.IsFailed(binding) {
 			log.Info("Credentials rotation - binding failed stopping rotation")
+			r.Recorder.Event(binding, corev1.EventTypeWarning, common.CredRotationFailed, "the new binding failed, stopping credentials rotation")
 			return r.stopRotation(ctx, binding)
 		}
 		log.Info("Credentials rotation - waiting to finish")
@@ -935,6 +1181,7 @@ func (r *ServiceBindingReconciler) rotateCredentials(ctx context.Context, bindin
 		log.Info("Credentials rotation - renaming binding to old in SM", "current", binding.Spec.ExternalName)
 		if _, errRenaming := smClient.RenameBinding(binding.Status.BindingID, binding.Spec.ExternalName+suffix, binding.Name+suffix); errRenaming != nil {
 			log.Error(errRenaming, "Credentials rotation - failed renaming binding to old in SM", "binding", binding.Spec.ExternalName)
+			r.Recorder.Event(binding, corev1.EventTypeWarning, common.CredPreparing, fmt.Sprintf("failed to rename the binding in SM: %s", errRenaming.Error()))
 			utils.SetCredRotationInProgressConditions(common.CredPreparing, errRenaming.Error(), binding)
 			if errStatus := utils.UpdateStatus(ctx, r.Client, binding); errStatus != nil {
 				return errStatus
@@ -945,6 +1192,7 @@ func (r *ServiceBindingReconciler) rotateCredentials(ctx context.Context, bindin
 		log.Info("Credentials rotation - backing up old binding in K8S", "name", binding.Name+suffix)
 		if err := r.createOldBinding(ctx, suffix, binding); err != nil {
 			log.Error(err, "Credentials rotation - failed to back up old binding in K8S")
+			r.Recorder.Event(binding, corev1.EventTypeWarning, common.CredPreparing, fmt.Sprintf("failed to back up the binding: %s", err.Error()))
 
 			utils.SetCredRotationInProgressConditions(common.CredPreparing, err.Error(), binding)
 			if errStatus := utils.UpdateStatus(ctx, r.Client, binding); errStatus != nil {
@@ -958,6 +1206,74 @@ func (r *ServiceBindingReconciler) rotateCredentials(ctx context.Context, bindin
 	binding.Status.Ready = metav1.ConditionFalse
 	utils.SetInProgressConditions(ctx, smClientTypes.CREATE, "rotating binding credentials", binding, false)
 	utils.SetCredRotationInProgressConditions(common.CredRotating, "", binding)
+	r.Recorder.Event(binding, corev1.EventTypeNormal, common.CredRotating, "rotating binding credentials")
+	return utils.UpdateStatus(ctx, r.Client, binding)
+}
+
+// rolloutWorkloads rolls out the workloads of the rotation policy with the new secret, the rotation ends once they are rolled out
+func (r *ServiceBindingReconciler) rolloutWorkloads(ctx context.Context, binding *v1.ServiceBinding) error {
+	log := utils.GetLogger(ctx)
+	sink, err := r.GetSecretSink(ctx, binding)
+	if err != nil {
+		return err
+	}
+	secret, err := sink.Get(ctx, binding)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Credentials rotation - rolling out workloads")
+	if err := utils.RolloutWorkloads(ctx, r.Client, binding.Namespace, getRolloutWorkloads(binding), getSecretHash(secret)); err != nil {
+		log.Error(err, "Credentials rotation - failed to roll out workloads")
+		r.Recorder.Event(binding, corev1.EventTypeWarning, common.CredRotating, fmt.Sprintf("failed to roll out workloads: %s", err.Error()))
+		utils.SetCredRotationInProgressConditions(common.CredRotating, fmt.Sprintf("failed to roll out workloads: %s", err.Error()), binding)
+		if errStatus := utils.UpdateStatus(ctx, r.Client, binding); errStatus != nil {
+			return errStatus
+		}
+		return err
+	}
+	r.Recorder.Event(binding, corev1.EventTypeNormal, common.CredRollingOut, "rolling out workloads with the rotated credentials")
+	utils.SetCredRotationInProgressConditions(common.CredRollingOut, "rolling out workloads with the rotated credentials", binding)
+	return utils.UpdateStatus(ctx, r.Client, binding)
+}
+
+// waitForWorkloadsRollout ends the rotation once the workloads of the rotation policy are rolled out, the progress is reported in the rotation condition.
+// The rollout fails when it does not end within rotatedBindingTTL, so that the stale binding with the old credentials is deleted anyway.
+func (r *ServiceBindingReconciler) waitForWorkloadsRollout(ctx context.Context, binding *v1.ServiceBinding) error {
+	log := utils.GetLogger(ctx)
+	done, message, err := utils.GetWorkloadsRolloutStatus(ctx, r.Client, binding.Namespace, getRolloutWorkloads(binding))
+	if err != nil {
+		log.Error(err, "Credentials rotation - failed to get the rollout status of workloads")
+		return err
+	}
+	if done {
+		log.Info("Credentials rotation - workloads rolled out, finished successfully")
+		r.Recorder.Event(binding, corev1.EventTypeNormal, common.CredRotated, "credentials rotated and workloads rolled out successfully")
+		return r.stopRotation(ctx, binding)
+	}
+
+	if deadline := getRolloutDeadline(binding); deadline != nil && time.Now().After(deadline.Time) {
+		failure := fmt.Sprintf("the workloads were not rolled out within the rotatedBindingTTL, %s", message)
+		log.Info(fmt.Sprintf("Credentials rotation - %s", failure))
+		r.Recorder.Event(binding, corev1.EventTypeWarning, common.CredRotationFailed, failure)
+		conditions := binding.GetConditions()
+		meta.SetStatusCondition(&conditions, metav1.Condition{
+			Type:               common.ConditionCredRotationInProgress,
+			Status:             metav1.ConditionFalse,
+			Reason:             common.CredRotationFailed,
+			Message:            failure,
+			ObservedGeneration: binding.GetGeneration(),
+		})
+		binding.SetConditions(conditions)
+		return utils.UpdateStatus(ctx, r.Client, binding)
+	}
+
+	log.Info(fmt.Sprintf("Credentials rotation - %s", message))
+	credInProgressCondition := meta.FindStatusCondition(binding.GetConditions(), common.ConditionCredRotationInProgress)
+	if credInProgressCondition.Message == message {
+		return nil
+	}
+	utils.SetCredRotationInProgressConditions(common.CredRollingOut, message, binding)
 	return utils.UpdateStatus(ctx, r.Client, binding)
 }
 
@@ -1003,38 +1319,55 @@ func (r *ServiceBindingReconciler) handleStaleServiceBinding(ctx context.Context
 	if !ok {
 		//if the user removed the "rotationOf" label the stale binding should be deleted otherwise it will remain forever
 		log.Info("missing rotationOf label, unable to fetch original binding, deleting stale")
-		return ctrl.Result{}, r.Client.Delete(ctx, serviceBinding)
+		return r.deleteStaleBinding(ctx, serviceBinding, "the rotationOf label is missing")
 	}
 	origBinding := &v1.ServiceBinding{}
 	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: serviceBinding.Namespace, Name: originalBindingName}, origBinding); err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("original binding not found, deleting stale binding")
-			return ctrl.Result{}, r.Client.Delete(ctx, serviceBinding)
+			return r.deleteStaleBinding(ctx, serviceBinding, fmt.Sprintf("the rotated binding %s was not found", originalBindingName))
 		}
 		return ctrl.Result{}, err
 	}
-	if meta.IsStatusConditionTrue(origBinding.Status.Conditions, common.ConditionReady) {
-		return ctrl.Result{}, r.Client.Delete(ctx, serviceBinding)
+	message := "waiting for new credentials to be ready"
+	if isRollingOutWorkloads(origBinding) {
+		// the workloads may still use the credentials of the stale binding
+		message = "waiting for workloads to roll out the new credentials"
+	} else if meta.IsStatusConditionTrue(origBinding.Status.Conditions, common.ConditionReady) {
+		return r.deleteStaleBinding(ctx, serviceBinding, fmt.Sprintf("the new credentials of binding %s are ready", originalBindingName))
 	}
 
-	log.Info("not deleting stale binding since original binding is not ready")
-	if !meta.IsStatusConditionPresentAndEqual(serviceBinding.Status.Conditions, common.ConditionPendingTermination, metav1.ConditionTrue) {
+	log.Info(fmt.Sprintf("not deleting stale binding, %s", message))
+	if pendingTermination := meta.FindStatusCondition(serviceBinding.Status.Conditions, common.ConditionPendingTermination); pendingTermination == nil || pendingTermination.Message != message {
 		pendingTerminationCondition := metav1.Condition{
 			Type:               common.ConditionPendingTermination,
 			Status:             metav1.ConditionTrue,
 			Reason:             common.ConditionPendingTermination,
-			Message:            "waiting for new credentials to be ready",
+			Message:            message,
 			ObservedGeneration: serviceBinding.GetGeneration(),
 		}
 		meta.SetStatusCondition(&serviceBinding.Status.Conditions, pendingTerminationCondition)
-		return ctrl.Result{}, utils.UpdateStatus(ctx, r.Client, serviceBinding)
+		r.Recorder.Event(serviceBinding, corev1.EventTypeNormal, common.ConditionPendingTermination, message)
+		if err := utils.UpdateStatus(ctx, r.Client, serviceBinding); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	// the original binding is not watched, check it again later
+	return ctrl.Result{RequeueAfter: r.Config.PollInterval}, nil
+}
+
+func (r *ServiceBindingReconciler) deleteStaleBinding(ctx context.Context, serviceBinding *v1.ServiceBinding, reason string) (ctrl.Result, error) {
+	if err := r.Client.Delete(ctx, serviceBinding); err != nil {
+		return ctrl.Result{}, err
 	}
+	r.Recorder.Event(serviceBinding, corev1.EventTypeNormal, common.StaleBindingDeleted, fmt.Sprintf("deleting stale binding, %s", reason))
 	return ctrl.Result{}, nil
 }
 
 func (r *ServiceBindingReconciler) recover(ctx context.Context, serviceBinding *v1.ServiceBinding, smBinding *smClientTypes.ServiceBinding) (ctrl.Result, error) {
 	log := utils.GetLogger(ctx)
 	log.Info(fmt.Sprintf("found existing smBinding in SM with id %s, updating status", smBinding.ID))
+	r.Recorder.Event(serviceBinding, corev1.EventTypeNormal, common.Recovered, fmt.Sprintf("found existing binding in SM with id %s", smBinding.ID))
 
 	if smBinding.Credentials != nil {
 		if err := r.storeBindingSecret(ctx, serviceBinding, smBinding); err != nil {
@@ -1050,22 +1383,43 @@ func (r *ServiceBindingReconciler) recover(ctx context.Context, serviceBinding *
 	return ctrl.Result{}, utils.UpdateStatus(ctx, r.Client, serviceBinding)
 }
 
+// isBlocked returns whether the last operation of the binding is blocked
+func isBlocked(binding *v1.ServiceBinding) bool {
+	lastOpCondition := meta.FindStatusCondition(binding.GetConditions(), common.ConditionSucceeded)
+	return lastOpCondition != nil && lastOpCondition.Status == metav1.ConditionFalse && lastOpCondition.Reason == common.Blocked
+}
+
 func isStaleServiceBinding(binding *v1.ServiceBinding) bool {
 	if utils.IsMarkedForDeletion(binding.ObjectMeta) {
 		return false
 	}
 
-	if binding.Labels != nil {
-		if _, ok := binding.Labels[common.StaleBindingIDLabel]; ok {
-			if binding.Spec.CredRotationPolicy != nil {
-				keepFor, _ := time.ParseDuration(binding.Spec.CredRotationPolicy.RotatedBindingTTL)
-				if time.Since(binding.CreationTimestamp.Time) > keepFor {
-					return true
-				}
-			}
+	expirationTime := getStaleBindingExpirationTime(binding)
+	return expirationTime != nil && time.Now().After(expirationTime.Time)
+}
+
+// getStaleBindingExpirationTime returns the time after which the rotated binding should be deleted, nil if it is not a rotated binding
+func getStaleBindingExpirationTime(binding *v1.ServiceBinding) *metav1.Time {
+	if _, ok := binding.Labels[common.StaleBindingIDLabel]; !ok || binding.Spec.CredRotationPolicy == nil {
+		return nil
+	}
+	keepFor, _ := time.ParseDuration(binding.Spec.CredRotationPolicy.RotatedBindingTTL)
+	expirationTime := metav1.NewTime(binding.CreationTimestamp.Add(keepFor))
+	return &expirationTime
+}
+
+// requeueAt returns a result that requeues at the earliest of the future times, or does not requeue if there is none
+func requeueAt(times ...*metav1.Time) ctrl.Result {
+	var requeueAfter time.Duration
+	for _, t := range times {
+		if t == nil {
+			continue
+		}
+		if until := time.Until(t.Time); until > 0 && (requeueAfter == 0 || until < requeueAfter) {
+			requeueAfter = until
 		}
 	}
-	return false
+	return ctrl.Result{RequeueAfter: requeueAfter}
 }
 
 func initCredRotationIfRequired(binding *v1.ServiceBinding) bool {
@@ -1074,19 +1428,135 @@ func initCredRotationIfRequired(binding *v1.ServiceBinding) bool {
 	}
 	_, forceRotate := binding.Annotations[common.ForceRotateAnnotation]
 
+	nextRotationTime := getNextCredRotationTime(binding)
+	if forceRotate || (nextRotationTime != nil && !time.Now().Before(nextRotationTime.Time)) {
+		utils.SetCredRotationInProgressConditions(common.CredPreparing, "", binding)
+		return true
+	}
+
+	return false
+}
+
+// getNextCredRotationTime returns the time of the next credentials rotation according to the rotation policy, nil if it is disabled
+func getNextCredRotationTime(binding *v1.ServiceBinding) *metav1.Time {
+	if !credRotationEnabled(binding) {
+		return nil
+	}
+
 	lastCredentialRotationTime := binding.Status.LastCredentialsRotationTime
 	if lastCredentialRotationTime == nil {
 		ts := metav1.NewTime(binding.CreationTimestamp.Time)
 		lastCredentialRotationTime = &ts
 	}
 
-	rotationInterval, _ := time.ParseDuration(binding.Spec.CredRotationPolicy.RotationFrequency)
-	if time.Since(lastCredentialRotationTime.Time) > rotationInterval || forceRotate {
-		utils.SetCredRotationInProgressConditions(common.CredPreparing, "", binding)
+	nextRotationTime, err := binding.Spec.CredRotationPolicy.NextRotationTime(lastCredentialRotationTime.Time)
+	if err != nil {
+		return nil
+	}
+	if now := time.Now(); nextRotationTime.Before(now) {
+		// a missed rotation is done in the current or the next maintenance window
+		if nextRotationTime, err = binding.Spec.CredRotationPolicy.NextMaintenanceWindowTime(now); err != nil {
+			return nil
+		}
+	}
+	next := metav1.NewTime(nextRotationTime)
+	return &next
+}
+
+// nextRotationTimeEqual compares the times in seconds, the precision in which they are stored in the status
+func nextRotationTimeEqual(current, next *metav1.Time) bool {
+	if current == nil || next == nil {
+		return current == next
+	}
+	return current.Unix() == next.Unix()
+}
+
+func isRollingOutWorkloads(binding *v1.ServiceBinding) bool {
+	credInProgressCondition := meta.FindStatusCondition(binding.GetConditions(), common.ConditionCredRotationInProgress)
+	return credInProgressCondition != nil && credInProgressCondition.Status == metav1.ConditionTrue && credInProgressCondition.Reason == common.CredRollingOut
+}
+
+// getRolloutDeadline returns the time the workloads must be rolled out by, rotatedBindingTTL after the credentials were rotated,
+// nil if there is no deadline
+func getRolloutDeadline(binding *v1.ServiceBinding) *metav1.Time {
+	if binding.Status.LastCredentialsRotationTime == nil || binding.Spec.CredRotationPolicy == nil {
+		return nil
+	}
+	ttl, err := time.ParseDuration(binding.Spec.CredRotationPolicy.RotatedBindingTTL)
+	if err != nil {
+		return nil
+	}
+	deadline := metav1.NewTime(binding.Status.LastCredentialsRotationTime.Add(ttl))
+	return &deadline
+}
+
+func getRolloutWorkloads(binding *v1.ServiceBinding) []v1.WorkloadReference {
+	if binding.Spec.CredRotationPolicy == nil {
+		return nil
+	}
+	return binding.Spec.CredRotationPolicy.RolloutWorkloads
+}
+
+// getSecretHash returns the hash of the secret data, it changes when the credentials are rotated
+func getSecretHash(secret *corev1.Secret) string {
+	data := make(map[string][]byte, len(secret.Data)+len(secret.StringData))
+	for key, value := range secret.Data {
+		data[key] = value
+	}
+	// the string data is merged into the data when the secret is written
+	for key, value := range secret.StringData {
+		data[key] = []byte(value)
+	}
+	dataBytes, _ := json.Marshal(data)
+	hash := md5.Sum(dataBytes)
+	return hex.EncodeToString(hash[:])
+}
+
+// setSecretReference refers to the secret of the binding in the status, to the secret in its namespace or to the path in the key-value store,
+// and returns true if the reference changed
+func setSecretReference(binding *v1.ServiceBinding, sink utils.SecretSink) bool {
+	var secretRef *v1.BindingSecretReference
+	var externalSecretPath string
+	if kvSink, ok := sink.(*utils.KVSecretSink); ok {
+		externalSecretPath = kvSink.SecretPath(binding)
+	} else {
+		secretRef = &v1.BindingSecretReference{Name: binding.Spec.SecretName}
+	}
+
+	if reflect.DeepEqual(binding.Status.Binding, secretRef) && binding.Status.ExternalSecretPath == externalSecretPath {
+		return false
+	}
+	binding.Status.Binding = secretRef
+	binding.Status.ExternalSecretPath = externalSecretPath
+	return true
+}
+
+// secretDataDrifted returns true if the data of the secret differs from the data the operator wrote
+func secretDataDrifted(secret *corev1.Secret) bool {
+	return secret.Annotations[common.SecretDataHashAnnotation] != getSecretHash(secret)
+}
+
+// setSecretDriftedCondition sets or removes the SecretDrifted condition, and returns true if it changed
+func setSecretDriftedCondition(binding *v1.ServiceBinding, secret *corev1.Secret, drifted bool) bool {
+	conditions := binding.GetConditions()
+	if !drifted {
+		if meta.FindStatusCondition(conditions, common.ConditionSecretDrifted) == nil {
+			return false
+		}
+		meta.RemoveStatusCondition(&conditions, common.ConditionSecretDrifted)
+		binding.SetConditions(conditions)
 		return true
 	}
 
-	return false
+	changed := meta.SetStatusCondition(&conditions, metav1.Condition{
+		Type:               common.ConditionSecretDrifted,
+		Status:             metav1.ConditionTrue,
+		Reason:             common.SecretDataChanged,
+		Message:            fmt.Sprintf("the data of secret %s was edited and differs from the binding", secret.Name),
+		ObservedGeneration: binding.GetGeneration(),
+	})
+	binding.SetConditions(conditions)
+	return changed
 }
 
 func credRotationEnabled(binding *v1.ServiceBinding) bool {
@@ -1138,6 +1608,34 @@ func serviceInstanceReady(instance *v1.ServiceInstance) bool {
 	return instance.Status.Ready == metav1.ConditionTrue
 }
 
+// getServiceBindingIOEntries returns the type and provider entries of a secret compliant with the Service Binding for Kubernetes specification
+func getServiceBindingIOEntries(binding *v1.ServiceBinding, instance *v1.ServiceInstance) (string, string) {
+	bindingType := binding.Spec.ServiceBindingIO.Type
+	if len(bindingType) == 0 {
+		bindingType = instance.Spec.ServiceOfferingName
+		if tags := mergeInstanceTags(instance.Status.Tags, instance.Spec.CustomTags); len(tags) > 0 {
+			bindingType = tags[0]
+		}
+	}
+	provider := binding.Spec.ServiceBindingIO.Provider
+	if len(provider) == 0 {
+		provider = instance.Spec.ServiceOfferingName
+	}
+	return bindingType, provider
+}
+
+// setSecretEntry sets the entry in the string data of the secret if it is there, otherwise in its data
+func setSecretEntry(secret *corev1.Secret, key, value string) {
+	if _, ok := secret.StringData[key]; ok {
+		secret.StringData[key] = value
+		return
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[key] = []byte(value)
+}
+
 func getInstanceNameForSecretCredentials(instance *v1.ServiceInstance) []byte {
 	if useMetaName, ok := instance.Annotations[common.UseInstanceMetadataNameInSecret]; ok && useMetaName == "true" {
 		return []byte(instance.Name)
@@ -1160,3 +1658,117 @@ func singleKeyMap(credentialsMap map[string][]byte, key string) (map[string][]by
 		key: credBytes,
 	}, nil
 }
+
+// secretTemplate is the template a binding secret is rendered with
+type secretTemplate struct {
+	text       string
+	parameters map[string]string
+	// hash identifies the content of a referenced template, empty for inline templates
+	hash string
+}
+
+// getSecretTemplate returns the inline secret template of the binding or resolves its secretTemplateRef
+func (r *ServiceBindingReconciler) getSecretTemplate(ctx context.Context, binding *v1.ServiceBinding) (*secretTemplate, error) {
+	ref := binding.Spec.SecretTemplateRef
+	if ref == nil {
+		return &secretTemplate{text: binding.Spec.SecretTemplate}, nil
+	}
+
+	var spec v1.SecretTemplateSpec
+	if ref.IsClusterScoped() {
+		clusterSecretTemplate := &v1.ClusterSecretTemplate{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: ref.Name}, clusterSecretTemplate); err != nil {
+			return nil, err
+		}
+		spec = clusterSecretTemplate.Spec
+	} else {
+		namespacedSecretTemplate := &v1.SecretTemplate{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: binding.Namespace}, namespacedSecretTemplate); err != nil {
+			return nil, err
+		}
+		spec = namespacedSecretTemplate.Spec
+	}
+
+	parameters := make(map[string]string)
+	for key, value := range spec.Parameters {
+		parameters[key] = value
+	}
+	for key, value := range ref.Parameters {
+		parameters[key] = value
+	}
+
+	result := &secretTemplate{text: spec.Template, parameters: parameters}
+	templateBytes, err := json.Marshal(map[string]interface{}{"template": result.text, "parameters": result.parameters})
+	if err != nil {
+		return nil, err
+	}
+	hash := md5.Sum(templateBytes)
+	result.hash = hex.EncodeToString(hash[:])
+	return result, nil
+}
+
+// secretTemplateChanged returns true if the secret was rendered with an older version of the referenced secret template
+func (r *ServiceBindingReconciler) secretTemplateChanged(ctx context.Context, binding *v1.ServiceBinding, secret *corev1.Secret) (bool, error) {
+	if binding.Spec.SecretTemplateRef == nil {
+		return false, nil
+	}
+	secretTemplate, err := r.getSecretTemplate(ctx, binding)
+	if err != nil {
+		return false, err
+	}
+	return secret.Annotations[common.SecretTemplateHashAnnotation] != secretTemplate.hash, nil
+}
+
+// bindingsForSecretTemplate maps a SecretTemplate or ClusterSecretTemplate to the bindings that reference it
+func (r *ServiceBindingReconciler) bindingsForSecretTemplate(ctx context.Context, obj client.Object) []reconcile.Request {
+	_, clusterScoped := obj.(*v1.ClusterSecretTemplate)
+	bindings := &v1.ServiceBindingList{}
+	var opts []client.ListOption
+	if !clusterScoped {
+		opts = append(opts, client.InNamespace(obj.GetNamespace()))
+	}
+	if err := r.Client.List(ctx, bindings, opts...); err != nil {
+		r.Log.Error(err, "failed to list service bindings of secret template", "name", obj.GetName(), "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, binding := range bindings.Items {
+		ref := binding.Spec.SecretTemplateRef
+		if ref != nil && ref.Name == obj.GetName() && ref.IsClusterScoped() == clusterScoped {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: binding.Name, Namespace: binding.Namespace}})
+		}
+	}
+	return requests
+}
+
+// bindingsForServiceBindingGrant returns the bindings that refer to the service instances of the namespace of the grant
+func (r *ServiceBindingReconciler) bindingsForServiceBindingGrant(ctx context.Context, obj client.Object) []reconcile.Request {
+	bindings := &v1.ServiceBindingList{}
+	if err := r.Client.List(ctx, bindings); err != nil {
+		r.Log.Error(err, "failed to list service bindings of service binding grant", "name", obj.GetName(), "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, binding := range bindings.Items {
+		if binding.IsCrossNamespace() && binding.Spec.ServiceInstanceNamespace == obj.GetNamespace() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: binding.Name, Namespace: binding.Namespace}})
+		}
+	}
+	return requests
+}
+
+func (r *ServiceBindingReconciler) bindingsForNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	bindings := &v1.ServiceBindingList{}
+	if err := r.Client.List(ctx, bindings, client.InNamespace(obj.GetName())); err != nil {
+		r.Log.Error(err, "failed to list service bindings of namespace", "namespace", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(bindings.Items))
+	for _, binding := range bindings.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: binding.Name, Namespace: binding.Namespace}})
+	}
+	return requests
+}
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/lithammer/dedent"
 	authv1 "k8s.io/api/authentication/v1"
@@ -22,6 +25,7 @@ import (
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -271,6 +275,7 @@ var _ = Describe("ServiceBinding controller", func() {
 					},
 				}
 				validateSecretMetadata(bindingSecret, credentialProperties)
+				Expect(createdBinding.Status.Binding).To(Equal(&v1.BindingSecretReference{Name: createdBinding.Spec.SecretName}))
 			})
 
 			It("should put the raw broker response into the secret if spec.secretKey is provided", func() {
@@ -319,6 +324,61 @@ var _ = Describe("ServiceBinding controller", func() {
 				Expect(res).To(HaveKey("instance_guid"))
 			})
 
+			It("should store the credentials in a dotenv file if spec.secretFormat is provided", func() {
+				binding := newBindingObject("binding-with-secretformat", bindingTestNamespace)
+				binding.Spec.ServiceInstanceName = instanceName
+				binding.Spec.SecretFormat = &v1.SecretFormat{Type: v1.SecretFormatDotenv, Key: ".env", KeyCase: v1.KeyCaseUpper}
+				Expect(k8sClient.Create(ctx, binding)).To(Succeed())
+
+				waitForResourceToBeReady(ctx, binding)
+
+				bindingSecret := getSecret(ctx, binding.Spec.SecretName, bindingTestNamespace, true)
+				validateSecretData(bindingSecret, ".env", `ESCAPED="{\"escaped_key\":\"escaped_val\"}"`+"\n"+`SECRET_KEY="secret_value"`+"\n")
+				validateInstanceInfo(bindingSecret, instanceExternalName)
+				credentialProperties := []utils.SecretMetadataProperty{
+					{
+						Name:      ".env",
+						Format:    string(utils.DOTENV),
+						Container: true,
+					},
+				}
+				validateSecretMetadata(bindingSecret, credentialProperties)
+			})
+
+			It("should create a servicebinding.io secret if spec.serviceBindingIO is provided", func() {
+				binding := newBindingObject("binding-with-servicebindingio", bindingTestNamespace)
+				binding.Spec.ServiceInstanceName = instanceName
+				binding.Spec.ServiceBindingIO = &v1.ServiceBindingIO{}
+				Expect(k8sClient.Create(ctx, binding)).To(Succeed())
+
+				waitForResourceToBeReady(ctx, binding)
+
+				bindingSecret := getSecret(ctx, binding.Spec.SecretName, bindingTestNamespace, true)
+				Expect(bindingSecret.Type).To(Equal(corev1.SecretType("servicebinding.io/test")))
+				validateSecretData(bindingSecret, "type", "test")
+				validateSecretData(bindingSecret, "provider", "an-offering-name")
+				validateSecretData(bindingSecret, "secret_key", "secret_value")
+				metadata := make(map[string][]utils.SecretMetadataProperty)
+				Expect(json.Unmarshal(bindingSecret.Data[".metadata"], &metadata)).To(Succeed())
+				Expect(metadata["metaDataProperties"]).To(ContainElement(utils.SecretMetadataProperty{Name: "provider", Format: string(utils.TEXT)}))
+				Expect(binding.Status.Binding).To(Equal(&v1.BindingSecretReference{Name: binding.Spec.SecretName}))
+			})
+
+			It("should recreate the secret with the servicebinding.io type if spec.serviceBindingIO is added", func() {
+				createdBinding = createAndValidateBinding(ctx, bindingName, bindingTestNamespace, instanceName, "", "binding-external-name", "")
+				bindingSecret := getSecret(ctx, createdBinding.Spec.SecretName, createdBinding.Namespace, true)
+				Expect(bindingSecret.Type).To(Equal(corev1.SecretTypeOpaque))
+
+				createdBinding.Spec.ServiceBindingIO = &v1.ServiceBindingIO{Type: "postgresql", Provider: "sap"}
+				updateBinding(ctx, defaultLookupKey, createdBinding)
+				Eventually(func() bool {
+					bindingSecret = getSecret(ctx, createdBinding.Spec.SecretName, createdBinding.Namespace, true)
+					return bindingSecret.Type == "servicebinding.io/postgresql"
+				}, timeout, interval).Should(BeTrue())
+				validateSecretData(bindingSecret, "type", "postgresql")
+				validateSecretData(bindingSecret, "provider", "sap")
+			})
+
 			When("secret deleted by user", func() {
 				It("should recreate the secret", func() {
 					createdBinding = createAndValidateBinding(ctx, bindingName, bindingTestNamespace, instanceName, "", "binding-external-name", "")
@@ -349,6 +409,111 @@ var _ = Describe("ServiceBinding controller", func() {
 				})
 			})
 
+			When("secret written by an older version of the operator", func() {
+				It("should backfill the data hash without calling SM", func() {
+					createdBinding = createAndValidateBinding(ctx, bindingName, bindingTestNamespace, instanceName, "", "binding-external-name", "")
+					secretLookupKey := types.NamespacedName{Name: createdBinding.Spec.SecretName, Namespace: createdBinding.Namespace}
+					bindingSecret := getSecret(ctx, secretLookupKey.Name, secretLookupKey.Namespace, true)
+					originalHash := bindingSecret.Annotations[common.SecretDataHashAnnotation]
+					delete(bindingSecret.Annotations, common.SecretDataHashAnnotation)
+					Expect(k8sClient.Update(ctx, bindingSecret)).To(Succeed())
+					getBindingCalls := fakeClient.GetBindingByIDCallCount()
+
+					//tickle the binding
+					createdBinding.Annotations = map[string]string{"tickle": "true"}
+					Expect(k8sClient.Update(ctx, createdBinding)).To(Succeed())
+
+					Eventually(func() string {
+						return getSecret(ctx, secretLookupKey.Name, secretLookupKey.Namespace, true).Annotations[common.SecretDataHashAnnotation]
+					}, timeout, interval).Should(Equal(originalHash))
+					Expect(fakeClient.GetBindingByIDCallCount()).To(Equal(getBindingCalls))
+				})
+			})
+
+			When("secret of a binding with empty credentials deleted by user", func() {
+				It("should recreate the secret with a data hash", func() {
+					createdBinding = createAndValidateBinding(ctx, bindingName, bindingTestNamespace, instanceName, "", "binding-external-name", "")
+					secretLookupKey := types.NamespacedName{Name: createdBinding.Spec.SecretName, Namespace: createdBinding.Namespace}
+					bindingSecret := getSecret(ctx, secretLookupKey.Name, secretLookupKey.Namespace, true)
+					originalSecretUID := bindingSecret.UID
+					Expect(k8sClient.Delete(ctx, bindingSecret)).To(Succeed())
+
+					fakeClient.GetBindingByIDReturns(&smClientTypes.ServiceBinding{
+						ID: createdBinding.Status.BindingID,
+						LastOperation: &smClientTypes.Operation{
+							Type:  smClientTypes.CREATE,
+							State: smClientTypes.SUCCEEDED,
+						},
+					}, nil)
+
+					//tickle the binding
+					createdBinding.Annotations = map[string]string{"tickle": "true"}
+					Expect(k8sClient.Update(ctx, createdBinding)).To(Succeed())
+
+					newSecret := &corev1.Secret{}
+					Eventually(func() bool {
+						err := k8sClient.Get(ctx, secretLookupKey, newSecret)
+						return err == nil && newSecret.UID != originalSecretUID
+					}, timeout, interval).Should(BeTrue())
+					Expect(newSecret.Annotations).To(HaveKey(common.SecretDataHashAnnotation))
+				})
+			})
+
+			When("secret data edited by user", func() {
+				var secretLookupKey types.NamespacedName
+				BeforeEach(func() {
+					fakeClient.GetBindingByIDReturns(&smClientTypes.ServiceBinding{
+						ID:          fakeBindingID,
+						Credentials: json.RawMessage("{\"secret_key\": \"secret_value\"}"),
+						LastOperation: &smClientTypes.Operation{
+							Type:        smClientTypes.CREATE,
+							State:       smClientTypes.SUCCEEDED,
+							Description: "fake-description",
+						},
+					}, nil)
+				})
+
+				editSecret := func() {
+					secretLookupKey = types.NamespacedName{Name: createdBinding.Spec.SecretName, Namespace: createdBinding.Namespace}
+					bindingSecret := getSecret(ctx, secretLookupKey.Name, secretLookupKey.Namespace, true)
+					Expect(bindingSecret.Annotations).To(HaveKey(common.SecretDataHashAnnotation))
+					bindingSecret.Data["secret_key"] = []byte("edited")
+					Expect(k8sClient.Update(ctx, bindingSecret)).To(Succeed())
+				}
+
+				It("should restore the secret", func() {
+					createdBinding = createAndValidateBinding(ctx, bindingName, bindingTestNamespace, instanceName, "", "binding-external-name", "")
+					editSecret()
+
+					Eventually(func() bool {
+						bindingSecret := getSecret(ctx, secretLookupKey.Name, secretLookupKey.Namespace, true)
+						return string(bindingSecret.Data["secret_key"]) == "secret_value"
+					}, timeout, interval).Should(BeTrue())
+				})
+
+				It("should set the SecretDrifted condition with the Report policy", func() {
+					binding := newBindingObject(bindingName, bindingTestNamespace)
+					binding.Spec.ServiceInstanceName = instanceName
+					binding.Spec.ExternalName = "binding-external-name"
+					binding.Spec.SecretDriftPolicy = v1.SecretDriftPolicyReport
+					Expect(k8sClient.Create(ctx, binding)).To(Succeed())
+					createdBinding = binding
+					waitForResourceToBeReady(ctx, createdBinding)
+					editSecret()
+
+					waitForResourceCondition(ctx, createdBinding, common.ConditionSecretDrifted, metav1.ConditionTrue, common.SecretDataChanged, "")
+					bindingSecret := getSecret(ctx, secretLookupKey.Name, secretLookupKey.Namespace, true)
+					Expect(string(bindingSecret.Data["secret_key"])).To(Equal("edited"))
+
+					By("removing the condition when the secret is deleted")
+					Expect(k8sClient.Delete(ctx, bindingSecret)).To(Succeed())
+					Eventually(func() bool {
+						err := k8sClient.Get(ctx, getResourceNamespacedName(createdBinding), createdBinding)
+						return err == nil && meta.FindStatusCondition(createdBinding.Status.Conditions, common.ConditionSecretDrifted) == nil
+					}, timeout, interval).Should(BeTrue())
+				})
+			})
+
 			When("bind call to SM returns error", func() {
 				var errorMessage string
 
@@ -476,6 +641,12 @@ var _ = Describe("ServiceBinding controller", func() {
 					binding, err := createBindingWithoutAssertions(ctx, bindingName, bindingTestNamespace, instanceName, "", "existing-name", "", false)
 					Expect(err).ToNot(HaveOccurred())
 					waitForResourceCondition(ctx, binding, common.ConditionFailed, metav1.ConditionTrue, "", errorMessage)
+					waitForEvent(ctx, binding, corev1.EventTypeWarning, common.CreateFailed)
+					Expect(binding.Status.Operations).To(HaveLen(1))
+					Expect(binding.Status.Operations[0].Type).To(Equal(smClientTypes.CREATE))
+					Expect(binding.Status.Operations[0].ID).To(Equal("an-operation-id"))
+					Expect(binding.Status.Operations[0].State).To(Equal(smClientTypes.FAILED))
+					Expect(binding.Status.Operations[0].Description).To(Equal(errorMessage))
 				})
 			})
 
@@ -637,18 +808,17 @@ stringData:
 				Expect(bindingSecret.Labels["instance_plan"]).To(Equal("a-plan-name"))
 				Expect(bindingSecret.Annotations["instance_name"]).To(Equal(instanceExternalName))
 			})
-			It("should fail to create the secret if forbidden field is provided under spec.secretTemplate.metadata", func() {
+			It("should reject the binding if forbidden field is provided under spec.secretTemplate.metadata", func() {
 				ctx := context.Background()
 				secretTemplate := dedent.Dedent(`
 				                                       apiVersion: v1
 				                                       kind: Secret
 				                                       metadata:
 				                                         name: my-secret-name`)
-				binding, err := createBindingWithoutAssertions(ctx, bindingName, bindingTestNamespace, instanceName, "", "", secretTemplate, false)
-				Expect(err).ToNot(HaveOccurred())
-				waitForResourceCondition(ctx, binding, common.ConditionFailed, metav1.ConditionTrue, "", "the Secret template is invalid: Secret's metadata field")
+				_, err := createBindingWithoutAssertions(ctx, bindingName, bindingTestNamespace, instanceName, "", "", secretTemplate, false)
+				Expect(err).To(MatchError(ContainSubstring("the Secret template is invalid: Secret's metadata field")))
 			})
-			It("should fail to create the secret if wrong template key in the spec.secretTemplate is provided", func() {
+			It("should reject the binding if wrong template key in the spec.secretTemplate is provided", func() {
 				ctx := context.Background()
 				secretTemplate := dedent.Dedent(`
 				                                       apiVersion: v1
@@ -656,25 +826,16 @@ stringData:
 				                                       stringData:
 				                                         foo: {{ .non_existing_key }}`)
 
-				binding, err := createBindingWithoutAssertions(ctx, bindingName, bindingTestNamespace, instanceName, "", "", secretTemplate, false)
-				Expect(err).To(BeNil())
-				bindingLookupKey := getResourceNamespacedName(binding)
-				Eventually(func() bool {
-					if err := k8sClient.Get(ctx, bindingLookupKey, binding); err != nil {
-						return false
-					}
-					cond := meta.FindStatusCondition(binding.GetConditions(), common.ConditionSucceeded)
-					return cond != nil && cond.Reason == "CreateFailed" && strings.Contains(cond.Message, "map has no entry for key \"non_existing_key\"")
-				}, timeout*2, interval).Should(BeTrue())
+				_, err := createBindingWithoutAssertions(ctx, bindingName, bindingTestNamespace, instanceName, "", "", secretTemplate, false)
+				Expect(err).To(MatchError(ContainSubstring("line 5, column 11: map has no entry for key \"non_existing_key\"")))
 			})
-			It("should fail to create the secret if secretTemplate is an unexpected type", func() {
+			It("should reject the binding if secretTemplate is an unexpected type", func() {
 				ctx := context.Background()
 				secretTemplate := dedent.Dedent(`
 				                                       apiVersion: v1
 				                                       kind: Pod`)
-				binding, err := createBindingWithoutAssertions(ctx, bindingName, bindingTestNamespace, instanceName, "", "", secretTemplate, false)
-				Expect(err).ToNot(HaveOccurred())
-				waitForResourceCondition(ctx, binding, common.ConditionFailed, metav1.ConditionTrue, "", "but needs to be of kind 'Secret'")
+				_, err := createBindingWithoutAssertions(ctx, bindingName, bindingTestNamespace, instanceName, "", "", secretTemplate, false)
+				Expect(err).To(MatchError(ContainSubstring("but needs to be of kind 'Secret'")))
 			})
 			It("should succeed to create the secret- empty data", func() {
 				ctx := context.Background()
@@ -770,6 +931,112 @@ stringData:
 				Expect(bindingSecret.Labels["instance_plan"]).To(Equal("a-plan-name"))
 				Expect(bindingSecret.Annotations["instance_name"]).To(Equal(instanceExternalName))
 			})
+			It("should succeed to create the secret with binding and subaccount data", func() {
+				fakeClient.BindReturns(&smClientTypes.ServiceBinding{
+					ID:          fakeBindingID,
+					Credentials: json.RawMessage(`{"secret_key": "secret_value"}`),
+					Labels:      smClientTypes.Labels{"subaccount_id": []string{"a-subaccount-id"}},
+				}, "", nil)
+
+				ctx := context.Background()
+				secretTemplate := dedent.Dedent(
+					`stringData:
+  version: "{{ .version }}"
+  binding: {{ .binding.namespace }}/{{ .binding.name }}
+  bindingID: {{ .binding.binding_id }}
+  subaccount: {{ .subaccount.id }}
+  subaccountLabel: {{ index .binding.labels.subaccount_id 0 }}
+  instanceParameters: {{ .instance.parameters | toJson | quote }}`)
+
+				createdBinding, err := createBindingWithoutAssertions(ctx, bindingName, bindingTestNamespace, instanceName, "", "", secretTemplate, true)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isResourceReady(createdBinding)).To(BeTrue())
+				bindingSecret := getSecret(ctx, createdBinding.Spec.SecretName, createdBinding.Namespace, true)
+				validateSecretData(bindingSecret, "version", "2")
+				validateSecretData(bindingSecret, "binding", bindingTestNamespace+"/"+bindingName)
+				validateSecretData(bindingSecret, "bindingID", fakeBindingID)
+				validateSecretData(bindingSecret, "subaccount", "a-subaccount-id")
+				validateSecretData(bindingSecret, "subaccountLabel", "a-subaccount-id")
+				validateSecretData(bindingSecret, "instanceParameters", "{}")
+			})
+		})
+
+		When("secretTemplateRef", func() {
+			var secretTemplateName string
+
+			createBindingWithTemplateRef := func(ctx context.Context, ref *v1.SecretTemplateReference) *v1.ServiceBinding {
+				binding := generateBasicBindingTemplate(bindingName, bindingTestNamespace, instanceName, "", "", "")
+				binding.Spec.SecretTemplateRef = ref
+				Expect(k8sClient.Create(ctx, binding)).To(Succeed())
+				waitForResourceToBeReady(ctx, binding)
+				return binding
+			}
+
+			BeforeEach(func() {
+				secretTemplateName = "template-" + testUUID
+			})
+
+			It("should create the secret from a SecretTemplate and re-render it when the template changes", func() {
+				ctx := context.Background()
+				secretTemplate := &v1.SecretTemplate{
+					ObjectMeta: metav1.ObjectMeta{Name: secretTemplateName, Namespace: bindingTestNamespace},
+					Spec: v1.SecretTemplateSpec{
+						Template: dedent.Dedent(
+							`stringData:
+  key: {{ .credentials.secret_key }}
+  env: {{ .parameters.env }}`),
+						Parameters: map[string]string{"env": "dev"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, secretTemplate)).To(Succeed())
+				defer func() { Expect(k8sClient.Delete(ctx, secretTemplate)).To(Succeed()) }()
+
+				binding := createBindingWithTemplateRef(ctx, &v1.SecretTemplateReference{Name: secretTemplateName})
+				bindingSecret := getSecret(ctx, binding.Spec.SecretName, binding.Namespace, true)
+				validateSecretData(bindingSecret, "key", "secret_value")
+				validateSecretData(bindingSecret, "env", "dev")
+				Expect(bindingSecret.Annotations).To(HaveKey(common.SecretTemplateHashAnnotation))
+
+				By("Update the secret template")
+				Expect(k8sClient.Get(ctx, getResourceNamespacedName(secretTemplate), secretTemplate)).To(Succeed())
+				secretTemplate.Spec.Parameters["env"] = "prod"
+				Expect(k8sClient.Update(ctx, secretTemplate)).To(Succeed())
+				Eventually(func() string {
+					bindingSecret = getSecret(ctx, binding.Spec.SecretName, binding.Namespace, false)
+					return string(bindingSecret.Data["env"])
+				}, timeout, interval).Should(Equal("prod"))
+			})
+
+			It("should create the secret from a ClusterSecretTemplate with the binding parameters", func() {
+				ctx := context.Background()
+				clusterSecretTemplate := &v1.ClusterSecretTemplate{
+					ObjectMeta: metav1.ObjectMeta{Name: secretTemplateName},
+					Spec: v1.SecretTemplateSpec{
+						Template: dedent.Dedent(
+							`stringData:
+  env: {{ .parameters.env }}`),
+						Parameters: map[string]string{"env": "dev"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, clusterSecretTemplate)).To(Succeed())
+				defer func() { Expect(k8sClient.Delete(ctx, clusterSecretTemplate)).To(Succeed()) }()
+
+				binding := createBindingWithTemplateRef(ctx, &v1.SecretTemplateReference{
+					Kind:       v1.ClusterSecretTemplateKind,
+					Name:       secretTemplateName,
+					Parameters: map[string]string{"env": "test"},
+				})
+				bindingSecret := getSecret(ctx, binding.Spec.SecretName, binding.Namespace, true)
+				validateSecretData(bindingSecret, "env", "test")
+			})
+
+			It("should fail when the referenced secret template does not exist", func() {
+				ctx := context.Background()
+				binding := generateBasicBindingTemplate(bindingName, bindingTestNamespace, instanceName, "", "", "")
+				binding.Spec.SecretTemplateRef = &v1.SecretTemplateReference{Name: "non-existing-template"}
+				Expect(k8sClient.Create(ctx, binding)).To(Succeed())
+				waitForResourceCondition(ctx, binding, common.ConditionSucceeded, metav1.ConditionFalse, "", "non-existing-template")
+			})
 		})
 	})
 
@@ -790,6 +1057,27 @@ stringData:
 			Expect(isResourceReady(createdBinding)).To(BeTrue())
 		})
 
+		When("labels synced to SM are changed", func() {
+			It("should update the labels of the binding in SM", func() {
+				Expect(createdBinding.Status.SyncedLabels).To(BeEmpty())
+				Eventually(func() error {
+					if err := k8sClient.Get(ctx, getResourceNamespacedName(createdBinding), createdBinding); err != nil {
+						return err
+					}
+					createdBinding.Labels = map[string]string{"team": "a-team"}
+					return k8sClient.Update(ctx, createdBinding)
+				}, timeout, interval).Should(Succeed())
+				Eventually(func() map[string]string {
+					Expect(k8sClient.Get(ctx, getResourceNamespacedName(createdBinding), createdBinding)).To(Succeed())
+					return createdBinding.Status.SyncedLabels
+				}, timeout, interval).Should(Equal(map[string]string{"team": "a-team"}))
+				Expect(fakeClient.UpdateBindingLabelsCallCount()).To(Equal(1))
+				id, changes := fakeClient.UpdateBindingLabelsArgsForCall(0)
+				Expect(id).To(Equal(fakeBindingID))
+				Expect(changes).To(Equal([]*smClientTypes.LabelChange{{Operation: smClientTypes.AddLabelOperation, Key: "team", Values: []string{"a-team"}}}))
+			})
+		})
+
 		When("external name is changed", func() {
 			It("should fail", func() {
 				createdBinding.Spec.ExternalName = "new-external-name"
@@ -1180,6 +1468,155 @@ stringData:
 		})
 	})
 
+	Context("KV secret sink", func() {
+		var (
+			kvServer   *httptest.Server
+			kvData     map[string]map[string]string
+			kvMetadata map[string]map[string]string
+			kvMutex    sync.Mutex
+			kvStatus   int
+			sinkSecret *corev1.Secret
+		)
+
+		BeforeEach(func() {
+			kvStatus = 0
+			kvData = map[string]map[string]string{}
+			kvMetadata = map[string]map[string]string{}
+			// a stand-in for the KV secrets engine version 2
+			kvServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				kvMutex.Lock()
+				defer kvMutex.Unlock()
+				if kvStatus != 0 {
+					w.WriteHeader(kvStatus)
+					return
+				}
+				secretPath := strings.Replace(strings.Replace(r.URL.Path, "/data/", "/", 1), "/metadata/", "/", 1)
+				switch {
+				case r.Method == http.MethodGet:
+					data, ok := kvData[secretPath]
+					if !ok {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+						"data":     data,
+						"metadata": map[string]interface{}{"custom_metadata": kvMetadata[secretPath]},
+					}})
+				case r.Method == http.MethodPost:
+					body := struct {
+						Data           map[string]string `json:"data"`
+						CustomMetadata map[string]string `json:"custom_metadata"`
+					}{}
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					if strings.Contains(r.URL.Path, "/data/") {
+						kvData[secretPath] = body.Data
+					} else {
+						kvMetadata[secretPath] = body.CustomMetadata
+					}
+				case r.Method == http.MethodDelete:
+					delete(kvData, secretPath)
+					delete(kvMetadata, secretPath)
+				}
+			}))
+			sinkSecret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: utils.SecretSinkSecretName, Namespace: testNamespace},
+				Data:       map[string][]byte{"address": []byte(kvServer.URL), "token": []byte("token")},
+			}
+			Expect(k8sClient.Create(ctx, sinkSecret)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			deleteAndWait(ctx, sinkSecret)
+			kvServer.Close()
+		})
+
+		It("should store the credentials in the key-value store instead of a secret", func() {
+			binding := newBindingObject(bindingName, bindingTestNamespace)
+			binding.Spec.ServiceInstanceName = instanceName
+			binding.Spec.ExternalName = "binding-external-name"
+			binding.Spec.SecretSink = v1.SecretSinkKV
+			Expect(k8sClient.Create(ctx, binding)).To(Succeed())
+			createdBinding = binding
+			waitForResourceToBeReady(ctx, createdBinding)
+
+			secretPath := "secret/sap-btp-service-operator/" + bindingTestNamespace + "/" + createdBinding.Spec.SecretName
+			Expect(createdBinding.Status.ExternalSecretPath).To(Equal(secretPath))
+			Expect(createdBinding.Status.Binding).To(BeNil())
+			kvMutex.Lock()
+			Expect(kvData).To(HaveKey("/v1/" + secretPath))
+			Expect(kvData["/v1/"+secretPath]).To(HaveKeyWithValue("secret_key", "secret_value"))
+			kvMutex.Unlock()
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: createdBinding.Spec.SecretName, Namespace: bindingTestNamespace}, &corev1.Secret{})
+			Expect(client.IgnoreNotFound(err)).To(Succeed())
+			Expect(err).To(HaveOccurred())
+
+			By("deleting the credentials from the key-value store with the binding")
+			fakeClient.UnbindReturns("", nil)
+			deleteAndWait(ctx, createdBinding)
+			createdBinding = nil
+			kvMutex.Lock()
+			Expect(kvData).To(BeEmpty())
+			kvMutex.Unlock()
+		})
+
+		It("should reject a binding that overrides an enforced secret sink", func() {
+			sinkSecret.Data["type"] = []byte(v1.SecretSinkKV)
+			sinkSecret.Data[v1.SecretSinkEnforceKey] = []byte("true")
+			Expect(k8sClient.Update(ctx, sinkSecret)).To(Succeed())
+			binding := newBindingObject(bindingName, bindingTestNamespace)
+			binding.Spec.ServiceInstanceName = instanceName
+			binding.Spec.SecretSink = v1.SecretSinkKubernetes
+			err := k8sClient.Create(ctx, binding)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the KV secret sink is enforced"))
+		})
+
+		It("should not store the credentials of another binding with the same secret name", func() {
+			binding := newBindingObject(bindingName, bindingTestNamespace)
+			binding.Spec.ServiceInstanceName = instanceName
+			binding.Spec.ExternalName = "binding-external-name"
+			binding.Spec.SecretSink = v1.SecretSinkKV
+			Expect(k8sClient.Create(ctx, binding)).To(Succeed())
+			createdBinding = binding
+			waitForResourceToBeReady(ctx, createdBinding)
+
+			otherBinding := newBindingObject("other-"+bindingName, bindingTestNamespace)
+			otherBinding.Spec.ServiceInstanceName = instanceName
+			otherBinding.Spec.ExternalName = "other-binding-external-name"
+			otherBinding.Spec.SecretName = createdBinding.Spec.SecretName
+			otherBinding.Spec.SecretSink = v1.SecretSinkKV
+			Expect(k8sClient.Create(ctx, otherBinding)).To(Succeed())
+			waitForResourceCondition(ctx, otherBinding, common.ConditionSucceeded, metav1.ConditionFalse, common.Blocked, fmt.Sprintf("belongs to another binding %s", createdBinding.Name))
+			deleteAndWait(ctx, otherBinding)
+
+			kvMutex.Lock()
+			Expect(kvData).To(HaveLen(1))
+			kvMutex.Unlock()
+		})
+
+		It("should retry storing the credentials while the key-value store is unavailable", func() {
+			kvMutex.Lock()
+			kvStatus = http.StatusServiceUnavailable
+			kvMutex.Unlock()
+			binding := newBindingObject(bindingName, bindingTestNamespace)
+			binding.Spec.ServiceInstanceName = instanceName
+			binding.Spec.ExternalName = "binding-external-name"
+			binding.Spec.SecretSink = v1.SecretSinkKV
+			Expect(k8sClient.Create(ctx, binding)).To(Succeed())
+			createdBinding = binding
+			waitForResourceCondition(ctx, createdBinding, common.ConditionSucceeded, metav1.ConditionFalse, common.CreateInProgress, "failed with status 503")
+			Expect(meta.IsStatusConditionTrue(createdBinding.GetConditions(), common.ConditionFailed)).To(BeFalse())
+
+			kvMutex.Lock()
+			kvStatus = 0
+			kvMutex.Unlock()
+			waitForResourceToBeReady(ctx, createdBinding)
+			kvMutex.Lock()
+			Expect(kvData).To(HaveLen(1))
+			kvMutex.Unlock()
+		})
+	})
+
 	Context("Credential Rotation", func() {
 		BeforeEach(func() {
 			fakeClient.RenameBindingReturns(nil, nil)
@@ -1272,6 +1709,150 @@ stringData:
 			Expect(ok).To(BeFalse())
 		})
 
+		It("should rotate the credentials on time without further events", func() {
+			Expect(k8sClient.Get(ctx, defaultLookupKey, createdBinding)).To(Succeed())
+			createdBinding.Spec.CredRotationPolicy = &v1.CredentialsRotationPolicy{
+				Enabled:           true,
+				RotatedBindingTTL: "1h",
+				RotationFrequency: "8s",
+			}
+			updateBinding(ctx, defaultLookupKey, createdBinding)
+			dueTime := createdBinding.CreationTimestamp.Add(8 * time.Second)
+
+			myBinding := &v1.ServiceBinding{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, defaultLookupKey, myBinding)
+				return err == nil && myBinding.Status.NextCredentialsRotationTime != nil
+			}, timeout, interval).Should(BeTrue())
+			Expect(myBinding.Status.NextCredentialsRotationTime.Unix()).To(Equal(dueTime.Unix()))
+			Expect(myBinding.Status.LastCredentialsRotationTime).To(BeNil())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, defaultLookupKey, myBinding)
+				return err == nil && myBinding.Status.LastCredentialsRotationTime != nil
+			}, timeout, interval).Should(BeTrue())
+			Expect(myBinding.Status.LastCredentialsRotationTime.Unix()).To(BeNumerically(">=", dueTime.Unix()))
+		})
+
+		It("should set the next rotation time according to the schedule and maintenance windows", func() {
+			Expect(k8sClient.Get(ctx, defaultLookupKey, createdBinding)).To(Succeed())
+			createdBinding.Spec.CredRotationPolicy = &v1.CredentialsRotationPolicy{
+				Enabled:            true,
+				RotatedBindingTTL:  "1h",
+				Schedule:           "0 2 * * SAT",
+				TimeZone:           "Europe/Berlin",
+				MaintenanceWindows: []v1.MaintenanceWindow{{Days: []v1.MaintenanceWindowDay{"Sat"}, Start: "01:00", Duration: "4h"}},
+			}
+			updateBinding(ctx, defaultLookupKey, createdBinding)
+
+			expected, err := createdBinding.Spec.CredRotationPolicy.NextRotationTime(createdBinding.CreationTimestamp.Time)
+			Expect(err).ToNot(HaveOccurred())
+			myBinding := &v1.ServiceBinding{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, defaultLookupKey, myBinding)
+				return err == nil && myBinding.Status.NextCredentialsRotationTime != nil
+			}, timeout, interval).Should(BeTrue())
+			Expect(myBinding.Status.NextCredentialsRotationTime.Unix()).To(Equal(expected.Unix()))
+			Expect(myBinding.Status.LastCredentialsRotationTime).To(BeNil())
+		})
+
+		It("should roll out the workloads after rotating the credentials", func() {
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-" + testUUID, Namespace: bindingTestNamespace},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "app"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "app"}},
+						Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app"}}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, defaultLookupKey, createdBinding)).To(Succeed())
+			createdBinding.Spec.CredRotationPolicy = &v1.CredentialsRotationPolicy{
+				Enabled:           true,
+				RotationFrequency: "1h",
+				RotatedBindingTTL: "1h",
+				RolloutWorkloads:  []v1.WorkloadReference{{Kind: v1.WorkloadKindDeployment, Name: deployment.Name}},
+			}
+			createdBinding.Annotations = map[string]string{
+				common.ForceRotateAnnotation: "true",
+			}
+			updateBinding(ctx, defaultLookupKey, createdBinding)
+
+			By("waiting for the deployment to roll out")
+			myBinding := &v1.ServiceBinding{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, defaultLookupKey, myBinding)
+				cond := meta.FindStatusCondition(myBinding.Status.Conditions, common.ConditionCredRotationInProgress)
+				return err == nil && cond != nil && cond.Reason == common.CredRollingOut && strings.Contains(cond.Message, deployment.Name)
+			}, timeout, interval).Should(BeTrue())
+			Expect(k8sClient.Get(ctx, getResourceNamespacedName(deployment), deployment)).To(Succeed())
+			secret := getSecret(ctx, myBinding.Spec.SecretName, bindingTestNamespace, true)
+			Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue(common.SecretHashAnnotation, Not(BeEmpty())))
+			Expect(secret.Data).ToNot(BeEmpty())
+
+			By("keeping the stale binding until the deployment is rolled out")
+			bindingList := &v1.ServiceBindingList{}
+			Expect(k8sClient.List(ctx, bindingList, client.MatchingLabels{common.StaleBindingRotationOfLabel: myBinding.Name}, client.InNamespace(bindingTestNamespace))).To(Succeed())
+			Expect(bindingList.Items).To(HaveLen(1))
+
+			deployment.Status = appsv1.DeploymentStatus{
+				ObservedGeneration: deployment.Generation,
+				Replicas:           1,
+				UpdatedReplicas:    1,
+				AvailableReplicas:  1,
+			}
+			Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, defaultLookupKey, myBinding)
+				return err == nil && meta.FindStatusCondition(myBinding.Status.Conditions, common.ConditionCredRotationInProgress) == nil
+			}, timeout, interval).Should(BeTrue())
+		})
+
+		It("should fail the rollout when the workloads are not rolled out within the rotated binding TTL", func() {
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "stuck-app-" + testUUID, Namespace: bindingTestNamespace},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "stuck-app"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "stuck-app"}},
+						Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app"}}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, defaultLookupKey, createdBinding)).To(Succeed())
+			createdBinding.Spec.CredRotationPolicy = &v1.CredentialsRotationPolicy{
+				Enabled:           true,
+				RotationFrequency: "1h",
+				RotatedBindingTTL: "2s",
+				RolloutWorkloads:  []v1.WorkloadReference{{Kind: v1.WorkloadKindDeployment, Name: deployment.Name}},
+			}
+			createdBinding.Annotations = map[string]string{
+				common.ForceRotateAnnotation: "true",
+			}
+			updateBinding(ctx, defaultLookupKey, createdBinding)
+
+			myBinding := &v1.ServiceBinding{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, defaultLookupKey, myBinding)
+				cond := meta.FindStatusCondition(myBinding.Status.Conditions, common.ConditionCredRotationInProgress)
+				return err == nil && cond != nil && cond.Status == metav1.ConditionFalse && cond.Reason == common.CredRotationFailed &&
+					strings.Contains(cond.Message, "not rolled out within the rotatedBindingTTL")
+			}, timeout, interval).Should(BeTrue())
+			waitForEvent(ctx, myBinding, corev1.EventTypeWarning, common.CredRotationFailed)
+
+			By("deleting the stale binding")
+			Eventually(func() bool {
+				bindingList := &v1.ServiceBindingList{}
+				err := k8sClient.List(ctx, bindingList, client.MatchingLabels{common.StaleBindingRotationOfLabel: myBinding.Name}, client.InNamespace(bindingTestNamespace))
+				return err == nil && len(bindingList.Items) == 0
+			}, timeout, interval).Should(BeTrue())
+		})
+
 		When("original binding ready=true", func() {
 			It("should delete old binding when stale", func() {
 				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: createdBinding.Name, Namespace: bindingTestNamespace}, createdBinding)).To(Succeed())
@@ -1288,6 +1869,26 @@ stringData:
 				Expect(k8sClient.Create(ctx, staleBinding)).To(Succeed())
 				waitForResourceToBeDeleted(ctx, getResourceNamespacedName(staleBinding), staleBinding)
 			})
+
+			It("should delete old binding when its TTL expires without further events", func() {
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: createdBinding.Name, Namespace: bindingTestNamespace}, createdBinding)).To(Succeed())
+				staleBinding := generateBasicStaleBinding(createdBinding)
+				staleBinding.Labels = map[string]string{
+					common.StaleBindingIDLabel:         createdBinding.Status.BindingID,
+					common.StaleBindingRotationOfLabel: createdBinding.Name,
+				}
+				staleBinding.Spec.CredRotationPolicy = &v1.CredentialsRotationPolicy{
+					Enabled:           false,
+					RotatedBindingTTL: "5s",
+					RotationFrequency: "0ns",
+				}
+				Expect(k8sClient.Create(ctx, staleBinding)).To(Succeed())
+				waitForResourceCondition(ctx, staleBinding, common.ConditionReady, metav1.ConditionTrue, "", "")
+				expirationTime := staleBinding.CreationTimestamp.Add(5 * time.Second)
+
+				waitForResourceToBeDeleted(ctx, getResourceNamespacedName(staleBinding), staleBinding)
+				Expect(time.Now().After(expirationTime)).To(BeTrue())
+			})
 		})
 
 		When("original binding ready=false (rotation failed)", func() {
@@ -1338,14 +1939,38 @@ stringData:
 	Context("Cross Namespace", func() {
 		var crossBinding *v1.ServiceBinding
 		var serviceInstanceInAnotherNamespace *v1.ServiceInstance
+		var grant *v1.ServiceBindingGrant
 		BeforeEach(func() {
 			serviceInstanceInAnotherNamespace = createInstance(ctx, instanceName, testNamespace, instanceExternalName)
+			grant = &v1.ServiceBindingGrant{
+				ObjectMeta: metav1.ObjectMeta{Name: "binding-grant", Namespace: testNamespace},
+				Spec: v1.ServiceBindingGrantSpec{
+					From:                 []v1.ServiceBindingGrantFrom{{Namespace: bindingTestNamespace}},
+					ServiceInstanceNames: []string{instanceName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, grant)).To(Succeed())
 		})
 
 		AfterEach(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, grant))).To(Succeed())
 			deleteAndWait(ctx, serviceInstanceInAnotherNamespace)
 		})
 
+		When("binding to the instance is not granted", func() {
+			It("should fail", func() {
+				Expect(k8sClient.Delete(ctx, grant)).To(Succeed())
+				Eventually(func() string {
+					binding, err := createBindingWithoutAssertions(ctx, bindingName, bindingTestNamespace, instanceName, testNamespace, "cross-binding-external-name", "", false)
+					if err == nil {
+						deleteAndWait(ctx, binding)
+						return ""
+					}
+					return err.Error()
+				}, timeout, interval).Should(ContainSubstring("binding to service instance %s in namespace %s is not granted to namespace %s", instanceName, testNamespace, bindingTestNamespace))
+			})
+		})
+
 		When("binding is created in a different namespace than the instance", func() {
 			AfterEach(func() {
 				if crossBinding != nil {
@@ -1358,6 +1983,25 @@ stringData:
 				By("Verify binding secret created")
 				getSecret(ctx, createdBinding.Spec.SecretName, createdBinding.Namespace, true)
 			})
+
+			It("should block the binding while its grant is revoked without unbinding it", func() {
+				crossBinding = createAndValidateBinding(ctx, bindingName, bindingTestNamespace, instanceName, testNamespace, "cross-binding-external-name", "")
+				unbindCalls := fakeClient.UnbindCallCount()
+
+				Expect(k8sClient.Delete(ctx, grant)).To(Succeed())
+				waitForResourceCondition(ctx, crossBinding, common.ConditionSucceeded, metav1.ConditionFalse, common.Blocked, "is not granted to namespace")
+				Expect(fakeClient.UnbindCallCount()).To(Equal(unbindCalls))
+				Expect(crossBinding.Status.BindingID).ToNot(BeEmpty())
+				getSecret(ctx, crossBinding.Spec.SecretName, crossBinding.Namespace, true)
+
+				By("unblocking the binding when the grant is restored")
+				grant = &v1.ServiceBindingGrant{
+					ObjectMeta: metav1.ObjectMeta{Name: "binding-grant", Namespace: testNamespace},
+					Spec:       grant.Spec,
+				}
+				Expect(k8sClient.Create(ctx, grant)).To(Succeed())
+				waitForResourceCondition(ctx, crossBinding, common.ConditionSucceeded, metav1.ConditionTrue, "", "")
+			})
 		})
 
 		Context("cred rotation", func() {
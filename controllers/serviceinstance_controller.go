@@ -20,14 +20,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"maps"
 	"net/http"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"k8s.io/apimachinery/pkg/types"
 
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/SAP/sap-btp-service-operator/api/common"
@@ -47,13 +54,27 @@ import (
 
 	"github.com/SAP/sap-btp-service-operator/client/sm"
 	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+const (
+	// planCheckJitterFactor is the maximal part of the plan check interval that is added to it randomly
+	planCheckJitterFactor = 0.1
+	// firstPlanCheckWindow spreads the first plan checks after the operator starts, so that the instances
+	// that were not checked yet or whose check is overdue are not all checked at once
+	firstPlanCheckWindow = 5 * time.Minute
+)
+
+// planChecksStartTime is the time the first plan checks are spread from
+var planChecksStartTime = time.Now()
+
 // ServiceInstanceReconciler reconciles a ServiceInstance object
 type ServiceInstanceReconciler struct {
 	client.Client
@@ -66,7 +87,9 @@ type ServiceInstanceReconciler struct {
 
 // +kubebuilder:rbac:groups=services.cloud.sap.com,resources=serviceinstances,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=services.cloud.sap.com,resources=serviceinstances/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=services.cloud.sap.com,resources=servicepolicies;clusterservicepolicies,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update
 
 func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -85,6 +108,10 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 	serviceInstance = serviceInstance.DeepCopy()
 
+	if paused, err := utils.HandlePaused(ctx, r.Client, serviceInstance, r.Config.AllowDeleteWhenPaused); err != nil || paused {
+		return ctrl.Result{}, err
+	}
+
 	if utils.IsMarkedForDeletion(serviceInstance.ObjectMeta) {
 		return r.deleteInstance(ctx, serviceInstance)
 	}
@@ -104,6 +131,15 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			}
 		}
 
+		if serviceInstance.Status.Ready == metav1.ConditionTrue {
+			if smLabels := utils.GetSyncedSMLabels(serviceInstance, r.Config); !maps.Equal(smLabels, serviceInstance.Status.SyncedLabels) {
+				return ctrl.Result{}, r.syncSMLabels(ctx, serviceInstance, smLabels)
+			}
+			if due, nextCheck := r.servicePlanCheckDue(serviceInstance); !due {
+				return ctrl.Result{RequeueAfter: nextCheck}, nil
+			}
+			return r.checkServicePlan(ctx, serviceInstance)
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -125,6 +161,10 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return utils.MarkAsTransientError(ctx, r.Client, common.Unknown, err, serviceInstance)
 	}
 
+	if _, ok := serviceInstance.Annotations[common.ForceReconcileAnnotation]; ok {
+		return r.forceReconcile(ctx, smClient, serviceInstance)
+	}
+
 	// RECOVER flag: broaden recovery if set (now from label)
 	if serviceInstance.Status.InstanceID == "" {
 		if getBoolLabel(serviceInstance, "services.cloud.sap.com/recover") {
@@ -169,6 +209,7 @@ func (r *ServiceInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 func (r *ServiceInstanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1.ServiceInstance{}).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.instancesForNamespace), builder.WithPredicates(utils.PausedAnnotationChanged())).
 		WithOptions(controller.Options{RateLimiter: workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](r.Config.RetryBaseDelay, r.Config.RetryMaxDelay)}).
 		Complete(r)
 }
@@ -184,25 +225,34 @@ func (r *ServiceInstanceReconciler) createInstance(ctx context.Context, smClient
 		return utils.MarkAsTransientError(ctx, r.Client, smClientTypes.CREATE, err, serviceInstance)
 	}
 
+	smLabels := utils.GetSyncedSMLabels(serviceInstance, r.Config)
 	provision, provisionErr := smClient.Provision(&smClientTypes.ServiceInstance{
 		Name:          serviceInstance.Spec.ExternalName,
 		ServicePlanID: serviceInstance.Spec.ServicePlanID,
 		Parameters:    instanceParameters,
-		Labels: smClientTypes.Labels{
+		Labels: utils.AddSyncedSMLabels(smClientTypes.Labels{
 			common.NamespaceLabel: []string{serviceInstance.Namespace},
 			common.K8sNameLabel:   []string{serviceInstance.Name},
 			common.ClusterIDLabel: []string{r.Config.ClusterID},
-		},
+		}, smLabels),
 	}, serviceInstance.Spec.ServiceOfferingName, serviceInstance.Spec.ServicePlanName, nil, utils.BuildUserInfo(ctx, serviceInstance.Spec.UserInfo), serviceInstance.Spec.DataCenter)
 
 	if provisionErr != nil {
 		log.Error(provisionErr, "failed to create service instance", "serviceOfferingName", serviceInstance.Spec.ServiceOfferingName,
 			"servicePlanName", serviceInstance.Spec.ServicePlanName)
+		utils.RecordOperationCompleted(serviceInstance, smClientTypes.CREATE, "", smClientTypes.FAILED, provisionErr.Error())
+		utils.RecordOperationEvent(r.Recorder, serviceInstance, smClientTypes.CREATE, smClientTypes.FAILED, provisionErr.Error())
 		return utils.HandleError(ctx, r.Client, smClientTypes.CREATE, provisionErr, serviceInstance)
 	}
 
 	serviceInstance.Status.InstanceID = provision.InstanceID
 	serviceInstance.Status.SubaccountID = provision.SubaccountID
+	serviceInstance.Status.ServicePlanID = provision.PlanID
+	serviceInstance.Status.ServiceOfferingID = provision.OfferingID
+	if provision.Instance != nil {
+		setSMInstanceStatus(serviceInstance, provision.Instance)
+	}
+	serviceInstance.Status.SyncedLabels = smLabels
 	if len(provision.Tags) > 0 {
 		tags, err := getTags(provision.Tags)
 		if err != nil {
@@ -216,6 +266,8 @@ func (r *ServiceInstanceReconciler) createInstance(ctx context.Context, smClient
 		log.Info("Provision request is in progress (async)")
 		serviceInstance.Status.OperationURL = provision.Location
 		serviceInstance.Status.OperationType = smClientTypes.CREATE
+		utils.RecordOperationStarted(serviceInstance, smClientTypes.CREATE, provision.Location)
+		utils.RecordOperationEvent(r.Recorder, serviceInstance, smClientTypes.CREATE, smClientTypes.INPROGRESS, "provisioning instance in SM")
 		utils.SetInProgressConditions(ctx, smClientTypes.CREATE, "", serviceInstance, false)
 
 		return ctrl.Result{Requeue: true, RequeueAfter: r.Config.PollInterval}, utils.UpdateStatus(ctx, r.Client, serviceInstance)
@@ -223,6 +275,8 @@ func (r *ServiceInstanceReconciler) createInstance(ctx context.Context, smClient
 
 	log.Info(fmt.Sprintf("Instance provisioned successfully, instanceID: %s, subaccountID: %s", serviceInstance.Status.InstanceID,
 		serviceInstance.Status.SubaccountID))
+	utils.RecordOperationCompleted(serviceInstance, smClientTypes.CREATE, "", smClientTypes.SUCCEEDED, "")
+	utils.RecordOperationEvent(r.Recorder, serviceInstance, smClientTypes.CREATE, smClientTypes.SUCCEEDED, fmt.Sprintf("instance %s provisioned successfully", serviceInstance.Status.InstanceID))
 	utils.SetSuccessConditions(smClientTypes.CREATE, serviceInstance, false)
 	return ctrl.Result{}, utils.UpdateStatus(ctx, r.Client, serviceInstance)
 }
@@ -238,21 +292,63 @@ func (r *ServiceInstanceReconciler) updateInstance(ctx context.Context, smClient
 	}
 
 	updateHashedSpecValue(serviceInstance)
-	_, operationURL, err := smClient.UpdateInstance(serviceInstance.Status.InstanceID, &smClientTypes.ServiceInstance{
+	if !servicePlansResolved(serviceInstance) {
+		// the service plans are usually resolved by the check of the service plan after the instance is created
+		plans, err := getCurrentServicePlans(smClient, serviceInstance)
+		if err != nil {
+			log.Error(err, "failed to get the current service plan of the instance")
+			return utils.MarkAsTransientError(ctx, r.Client, smClientTypes.UPDATE, err, serviceInstance)
+		}
+		if plans != nil {
+			setServicePlansStatus(serviceInstance, plans)
+		}
+	}
+	if servicePlansResolved(serviceInstance) {
+		if err := validateServicePlanChange(serviceInstance); err != nil {
+			utils.RecordOperationEvent(r.Recorder, serviceInstance, smClientTypes.UPDATE, smClientTypes.FAILED, err.Error())
+			return utils.MarkAsNonTransientError(ctx, r.Client, smClientTypes.UPDATE, err, serviceInstance)
+		}
+		if serviceInstance.Status.ServicePlanName != serviceInstance.Spec.ServicePlanName {
+			// the allowed service plans are resolved again for the new plan
+			serviceInstance.Status.ServicePlanName = ""
+			serviceInstance.Status.PlanUpdatable = nil
+			serviceInstance.Status.AllowedServicePlans = nil
+			serviceInstance.Status.PlanMaintenanceInfo = nil
+		}
+	}
+
+	maintenanceInfo := getTargetMaintenanceInfo(serviceInstance)
+	smInstance := &smClientTypes.ServiceInstance{
 		Name:          serviceInstance.Spec.ExternalName,
 		ServicePlanID: serviceInstance.Spec.ServicePlanID,
 		Parameters:    instanceParameters,
-	}, serviceInstance.Spec.ServiceOfferingName, serviceInstance.Spec.ServicePlanName, nil, utils.BuildUserInfo(ctx, serviceInstance.Spec.UserInfo), serviceInstance.Spec.DataCenter)
+	}
+	if maintenanceInfo != nil {
+		log.Info(fmt.Sprintf("upgrading instance to maintenance info version %s", maintenanceInfo.Version))
+		smInstance.MaintenanceInfo = &smClientTypes.MaintenanceInfo{Version: maintenanceInfo.Version, Description: maintenanceInfo.Description}
+	}
+
+	updatedInstance, operationURL, err := smClient.UpdateInstance(serviceInstance.Status.InstanceID, smInstance, serviceInstance.Spec.ServiceOfferingName, serviceInstance.Spec.ServicePlanName, nil, utils.BuildUserInfo(ctx, serviceInstance.Spec.UserInfo), serviceInstance.Spec.DataCenter)
 
 	if err != nil {
 		log.Error(err, fmt.Sprintf("failed to update service instance with ID %s", serviceInstance.Status.InstanceID))
+		utils.RecordOperationCompleted(serviceInstance, smClientTypes.UPDATE, "", smClientTypes.FAILED, err.Error())
+		utils.RecordOperationEvent(r.Recorder, serviceInstance, smClientTypes.UPDATE, smClientTypes.FAILED, err.Error())
 		return utils.HandleError(ctx, r.Client, smClientTypes.UPDATE, err, serviceInstance)
 	}
 
+	if maintenanceInfo != nil {
+		// the maintenance info of the instance is checked again in SM on the next check of its service plan
+		serviceInstance.Status.MaintenanceInfo = maintenanceInfo.DeepCopy()
+	}
+	setUpgradeAvailableCondition(serviceInstance)
+
 	if operationURL != "" {
 		log.Info(fmt.Sprintf("Update request accepted, operation URL: %s", operationURL))
 		serviceInstance.Status.OperationURL = operationURL
 		serviceInstance.Status.OperationType = smClientTypes.UPDATE
+		utils.RecordOperationStarted(serviceInstance, smClientTypes.UPDATE, operationURL)
+		utils.RecordOperationEvent(r.Recorder, serviceInstance, smClientTypes.UPDATE, smClientTypes.INPROGRESS, "updating instance in SM")
 		utils.SetInProgressConditions(ctx, smClientTypes.UPDATE, "", serviceInstance, false)
 		serviceInstance.Status.ForceReconcile = false
 		if err := utils.UpdateStatus(ctx, r.Client, serviceInstance); err != nil {
@@ -262,6 +358,11 @@ func (r *ServiceInstanceReconciler) updateInstance(ctx context.Context, smClient
 		return ctrl.Result{Requeue: true, RequeueAfter: r.Config.PollInterval}, nil
 	}
 	log.Info("Instance updated successfully")
+	if updatedInstance != nil {
+		setSMInstanceStatus(serviceInstance, updatedInstance)
+	}
+	utils.RecordOperationCompleted(serviceInstance, smClientTypes.UPDATE, "", smClientTypes.SUCCEEDED, "")
+	utils.RecordOperationEvent(r.Recorder, serviceInstance, smClientTypes.UPDATE, smClientTypes.SUCCEEDED, "instance updated successfully")
 	utils.SetSuccessConditions(smClientTypes.UPDATE, serviceInstance, false)
 	serviceInstance.Status.ForceReconcile = false
 	return ctrl.Result{}, utils.UpdateStatus(ctx, r.Client, serviceInstance)
@@ -337,6 +438,8 @@ func (r *ServiceInstanceReconciler) deleteInstance(ctx context.Context, serviceI
 		operationURL, deprovisionErr := smClient.Deprovision(serviceInstance.Status.InstanceID, nil, utils.BuildUserInfo(ctx, serviceInstance.Spec.UserInfo))
 		if deprovisionErr != nil {
 			// delete will proceed anyway
+			utils.RecordOperationCompleted(serviceInstance, smClientTypes.DELETE, "", smClientTypes.FAILED, deprovisionErr.Error())
+			utils.RecordOperationEvent(r.Recorder, serviceInstance, smClientTypes.DELETE, smClientTypes.FAILED, deprovisionErr.Error())
 			return utils.HandleDeleteError(ctx, r.Client, deprovisionErr, serviceInstance)
 		}
 
@@ -346,6 +449,7 @@ func (r *ServiceInstanceReconciler) deleteInstance(ctx context.Context, serviceI
 		}
 
 		log.Info("Instance was deleted successfully, removing finalizer")
+		utils.RecordOperationEvent(r.Recorder, serviceInstance, smClientTypes.DELETE, smClientTypes.SUCCEEDED, "instance deleted successfully")
 		// remove our finalizer from the list and update it.
 		return ctrl.Result{}, utils.RemoveFinalizer(ctx, r.Client, serviceInstance, common.FinalizerName)
 	}
@@ -364,6 +468,7 @@ func (r *ServiceInstanceReconciler) handleInstanceSharing(ctx context.Context, s
 			return r.handleInstanceSharingError(ctx, serviceInstance, metav1.ConditionFalse, common.ShareFailed, err)
 		}
 		log.Info("instance shared successfully")
+		r.Recorder.Event(serviceInstance, corev1.EventTypeNormal, common.ShareSucceeded, "instance shared successfully")
 		setSharedCondition(serviceInstance, metav1.ConditionTrue, common.ShareSucceeded, "instance shared successfully")
 	} else { //un-share
 		log.Info("Service instance appears to be shared, un-sharing the instance")
@@ -373,6 +478,7 @@ func (r *ServiceInstanceReconciler) handleInstanceSharing(ctx context.Context, s
 			return r.handleInstanceSharingError(ctx, serviceInstance, metav1.ConditionTrue, common.UnShareFailed, err)
 		}
 		log.Info("instance un-shared successfully")
+		r.Recorder.Event(serviceInstance, corev1.EventTypeNormal, common.UnShareSucceeded, "instance un-shared successfully")
 		if serviceInstance.Spec.Shared != nil {
 			setSharedCondition(serviceInstance, metav1.ConditionFalse, common.UnShareSucceeded, "instance un-shared successfully")
 		} else {
@@ -400,7 +506,7 @@ func (r *ServiceInstanceReconciler) poll(ctx context.Context, serviceInstance *v
 		log.Info(fmt.Sprintf("failed to fetch operation, got error from SM: %s", statusErr.Error()), "operationURL", serviceInstance.Status.OperationURL)
 		utils.SetInProgressConditions(ctx, serviceInstance.Status.OperationType, string(smClientTypes.INPROGRESS), serviceInstance, false)
 		// if failed to read operation status we cleanup the status to trigger re-sync from SM
-		freshStatus := v1.ServiceInstanceStatus{Conditions: serviceInstance.GetConditions()}
+		freshStatus := v1.ServiceInstanceStatus{Conditions: serviceInstance.GetConditions(), Operations: serviceInstance.Status.Operations}
 		if utils.IsMarkedForDeletion(serviceInstance.ObjectMeta) {
 			freshStatus.InstanceID = serviceInstance.Status.InstanceID
 		}
@@ -415,12 +521,17 @@ func (r *ServiceInstanceReconciler) poll(ctx context.Context, serviceInstance *v
 		log.Error(fmt.Errorf("last operation is nil"), fmt.Sprintf("polling %s returned nil", serviceInstance.Status.OperationURL))
 		return ctrl.Result{}, fmt.Errorf("last operation is nil")
 	}
+	serviceInstance.Status.LastOperation = convertLastOperation(status)
 	switch status.State {
 	case smClientTypes.INPROGRESS:
 		fallthrough
 	case smClientTypes.PENDING:
 		if len(status.Description) > 0 {
 			log.Info(fmt.Sprintf("last operation description is '%s'", status.Description))
+			if lastOpCondition := meta.FindStatusCondition(serviceInstance.GetConditions(), common.ConditionSucceeded); lastOpCondition == nil || lastOpCondition.Message != status.Description {
+				// the description is recorded once, not on every poll
+				utils.RecordOperationEvent(r.Recorder, serviceInstance, serviceInstance.Status.OperationType, status.State, status.Description)
+			}
 			utils.SetInProgressConditions(ctx, status.Type, status.Description, serviceInstance, true)
 			if err := utils.UpdateStatus(ctx, r.Client, serviceInstance); err != nil {
 				log.Error(err, "unable to update ServiceInstance polling description")
@@ -430,6 +541,8 @@ func (r *ServiceInstanceReconciler) poll(ctx context.Context, serviceInstance *v
 		return ctrl.Result{Requeue: true, RequeueAfter: r.Config.PollInterval}, nil
 	case smClientTypes.FAILED:
 		errMsg := getErrorMsgFromLastOperation(status)
+		utils.RecordOperationCompleted(serviceInstance, serviceInstance.Status.OperationType, status.ID, smClientTypes.FAILED, errMsg)
+		utils.RecordOperationEvent(r.Recorder, serviceInstance, serviceInstance.Status.OperationType, smClientTypes.FAILED, errMsg)
 		utils.SetFailureConditions(status.Type, errMsg, serviceInstance, true)
 		// in order to delete eventually the object we need return with error
 		if serviceInstance.Status.OperationType == smClientTypes.DELETE {
@@ -441,6 +554,8 @@ func (r *ServiceInstanceReconciler) poll(ctx context.Context, serviceInstance *v
 			return ctrl.Result{}, errors.New(errMsg)
 		}
 	case smClientTypes.SUCCEEDED:
+		utils.RecordOperationCompleted(serviceInstance, serviceInstance.Status.OperationType, status.ID, smClientTypes.SUCCEEDED, "")
+		utils.RecordOperationEvent(r.Recorder, serviceInstance, serviceInstance.Status.OperationType, smClientTypes.SUCCEEDED, fmt.Sprintf("operation %s finished successfully", status.ID))
 		if serviceInstance.Status.OperationType == smClientTypes.CREATE {
 			smInstance, err := smClient.GetInstanceByID(serviceInstance.Status.InstanceID, nil)
 			if err != nil {
@@ -450,6 +565,7 @@ func (r *ServiceInstanceReconciler) poll(ctx context.Context, serviceInstance *v
 			if len(smInstance.Labels["subaccount_id"]) > 0 {
 				serviceInstance.Status.SubaccountID = smInstance.Labels["subaccount_id"][0]
 			}
+			setSMInstanceStatus(serviceInstance, smInstance)
 			serviceInstance.Status.Ready = metav1.ConditionTrue
 		} else if serviceInstance.Status.OperationType == smClientTypes.DELETE {
 			// delete was successful - remove our finalizer from the list and update it.
@@ -469,6 +585,8 @@ func (r *ServiceInstanceReconciler) poll(ctx context.Context, serviceInstance *v
 func (r *ServiceInstanceReconciler) handleAsyncDelete(ctx context.Context, serviceInstance *v1.ServiceInstance, opURL string) (ctrl.Result, error) {
 	serviceInstance.Status.OperationURL = opURL
 	serviceInstance.Status.OperationType = smClientTypes.DELETE
+	utils.RecordOperationStarted(serviceInstance, smClientTypes.DELETE, opURL)
+	utils.RecordOperationEvent(r.Recorder, serviceInstance, smClientTypes.DELETE, smClientTypes.INPROGRESS, "deleting instance in SM")
 	utils.SetInProgressConditions(ctx, smClientTypes.DELETE, "", serviceInstance, false)
 
 	if err := utils.UpdateStatus(ctx, r.Client, serviceInstance); err != nil {
@@ -480,13 +598,12 @@ func (r *ServiceInstanceReconciler) handleAsyncDelete(ctx context.Context, servi
 
 func (r *ServiceInstanceReconciler) getInstanceForRecovery(ctx context.Context, smClient sm.Client, serviceInstance *v1.ServiceInstance) (*smClientTypes.ServiceInstance, error) {
 	log := utils.GetLogger(ctx)
+	nameQuery := fmt.Sprintf("name eq '%s'", serviceInstance.Spec.ExternalName)
+	namespaceQuery := fmt.Sprintf("context/namespace eq '%s'", serviceInstance.Namespace)
+	k8sNameQuery := fmt.Sprintf("%s eq '%s'", common.K8sNameLabel, serviceInstance.Name)
 	parameters := sm.Parameters{
-		FieldQuery: []string{
-			fmt.Sprintf("name eq '%s'", serviceInstance.Spec.ExternalName),
-			fmt.Sprintf("context/clusterid eq '%s'", r.Config.ClusterID),
-			fmt.Sprintf("context/namespace eq '%s'", serviceInstance.Namespace)},
-		LabelQuery: []string{
-			fmt.Sprintf("%s eq '%s'", common.K8sNameLabel, serviceInstance.Name)},
+		FieldQuery:    []string{nameQuery, fmt.Sprintf("context/clusterid eq '%s'", r.Config.ClusterID), namespaceQuery},
+		LabelQuery:    []string{k8sNameQuery},
 		GeneralParams: []string{"attach_last_operations=true"},
 	}
 
@@ -496,6 +613,17 @@ func (r *ServiceInstanceReconciler) getInstanceForRecovery(ctx context.Context,
 		return nil, err
 	}
 
+	if (instances == nil || len(instances.ServiceInstances) == 0) && r.Config.EnableClusterIDMigration {
+		// the context of a migrated instance keeps the cluster ID it was created with, only its cluster ID label is changed
+		log.Info("instance not found in SM by context, querying by cluster ID label")
+		parameters.FieldQuery = []string{nameQuery, namespaceQuery}
+		parameters.LabelQuery = []string{k8sNameQuery, fmt.Sprintf("%s eq '%s'", common.ClusterIDLabel, r.Config.ClusterID)}
+		if instances, err = smClient.ListInstances(&parameters); err != nil {
+			log.Error(err, "failed to list instances in SM")
+			return nil, err
+		}
+	}
+
 	if instances != nil && len(instances.ServiceInstances) > 0 {
 		return &instances.ServiceInstances[0], nil
 	}
@@ -507,6 +635,7 @@ func (r *ServiceInstanceReconciler) recover(ctx context.Context, smClient sm.Cli
 	log := utils.GetLogger(ctx)
 
 	log.Info(fmt.Sprintf("found existing instance in SM with id %s, updating status", smInstance.ID))
+	r.Recorder.Event(k8sInstance, corev1.EventTypeNormal, common.Recovered, fmt.Sprintf("found existing instance in SM with id %s", smInstance.ID))
 	updateHashedSpecValue(k8sInstance)
 	if smInstance.Ready {
 		k8sInstance.Status.Ready = metav1.ConditionTrue
@@ -517,6 +646,7 @@ func (r *ServiceInstanceReconciler) recover(ctx context.Context, smClient sm.Cli
 	k8sInstance.Status.InstanceID = smInstance.ID
 	k8sInstance.Status.OperationURL = ""
 	k8sInstance.Status.OperationType = ""
+	setSMInstanceStatus(k8sInstance, smInstance)
 	tags, err := getOfferingTags(smClient, smInstance.ServicePlanID)
 	if err != nil {
 		log.Error(err, "could not recover offering tags")
@@ -552,6 +682,58 @@ func (r *ServiceInstanceReconciler) recover(ctx context.Context, smClient sm.Cli
 	return ctrl.Result{}, utils.UpdateStatus(ctx, r.Client, k8sInstance)
 }
 
+// forceReconcile handles the force reconcile annotation, instances that are not ready are recovered from SM and ready instances are updated in SM
+func (r *ServiceInstanceReconciler) forceReconcile(ctx context.Context, smClient sm.Client, serviceInstance *v1.ServiceInstance) (ctrl.Result, error) {
+	log := utils.GetLogger(ctx)
+	log.Info("force reconcile annotation is set, reconciling instance with SM")
+	if err := utils.RemoveAnnotations(ctx, r.Client, serviceInstance, common.ForceReconcileAnnotation); err != nil {
+		log.Error(err, "failed to remove force reconcile annotation")
+		return ctrl.Result{}, err
+	}
+
+	var result ctrl.Result
+	var err error
+	if serviceInstance.Status.InstanceID != "" && serviceInstance.Status.Ready == metav1.ConditionTrue {
+		result, err = r.updateInstance(ctx, smClient, serviceInstance)
+	} else {
+		result, err = r.forceRecover(ctx, smClient, serviceInstance)
+	}
+
+	switch {
+	case err != nil:
+		r.Recorder.Event(serviceInstance, corev1.EventTypeWarning, common.ForceReconcileFailed, fmt.Sprintf("forced reconciliation with SM failed: %s", err.Error()))
+	case utils.IsFailed(serviceInstance):
+		r.Recorder.Event(serviceInstance, corev1.EventTypeWarning, common.ForceReconcileFailed, fmt.Sprintf("forced reconciliation with SM failed: %s", meta.FindStatusCondition(serviceInstance.GetConditions(), common.ConditionFailed).Message))
+	case len(serviceInstance.Status.OperationURL) > 0:
+		r.Recorder.Event(serviceInstance, corev1.EventTypeNormal, common.ForceReconciled, "forced reconciliation with SM is in progress")
+	default:
+		r.Recorder.Event(serviceInstance, corev1.EventTypeNormal, common.ForceReconciled, "forced reconciliation with SM succeeded")
+	}
+	return result, err
+}
+
+func (r *ServiceInstanceReconciler) forceRecover(ctx context.Context, smClient sm.Client, serviceInstance *v1.ServiceInstance) (ctrl.Result, error) {
+	log := utils.GetLogger(ctx)
+	if serviceInstance.Status.InstanceID == "" {
+		smInstance, err := r.getInstanceForRecovery(ctx, smClient, serviceInstance)
+		if err != nil {
+			log.Error(err, "failed to check instance recovery")
+			return utils.MarkAsTransientError(ctx, r.Client, common.Unknown, err, serviceInstance)
+		}
+		if smInstance == nil {
+			return r.createInstance(ctx, smClient, serviceInstance)
+		}
+		return r.recover(ctx, smClient, serviceInstance, smInstance)
+	}
+
+	smInstance, err := smClient.GetInstanceByID(serviceInstance.Status.InstanceID, &sm.Parameters{GeneralParams: []string{"attach_last_operations=true"}})
+	if err != nil {
+		log.Error(err, fmt.Sprintf("failed to get instance %s from SM", serviceInstance.Status.InstanceID))
+		return utils.HandleError(ctx, r.Client, common.Unknown, err, serviceInstance)
+	}
+	return r.recover(ctx, smClient, serviceInstance, smInstance)
+}
+
 func (r *ServiceInstanceReconciler) handleInstanceSharingError(ctx context.Context, object common.SAPBTPResource, status metav1.ConditionStatus, reason string, err error) (ctrl.Result, error) {
 	log := utils.GetLogger(ctx)
 
@@ -575,6 +757,9 @@ func (r *ServiceInstanceReconciler) handleInstanceSharingError(ctx context.Conte
 		}
 	}
 
+	if reason != common.InProgress {
+		r.Recorder.Event(object, corev1.EventTypeWarning, reason, errMsg)
+	}
 	setSharedCondition(object, status, reason, errMsg)
 	return ctrl.Result{Requeue: isTransient}, utils.UpdateStatus(ctx, r.Client, object)
 }
@@ -638,6 +823,11 @@ func isFinalState(ctx context.Context, serviceInstance *v1.ServiceInstance) bool
 		return false
 	}
 
+	if _, ok := serviceInstance.Annotations[common.ForceReconcileAnnotation]; ok {
+		log.Info("instance is not in final state, force reconcile annotation is set")
+		return false
+	}
+
 	if len(serviceInstance.Status.OperationURL) > 0 {
 		log.Info(fmt.Sprintf("instance is not in final state, async operation is in progress (%s)", serviceInstance.Status.OperationURL))
 		return false
@@ -741,6 +931,319 @@ func getOfferingTags(smClient sm.Client, planID string) ([]string, error) {
 	return tags, nil
 }
 
+// servicePlans holds the current service plan of an instance, its service offering and the plans of the offering
+type servicePlans struct {
+	plan     smClientTypes.ServicePlan
+	offering smClientTypes.ServiceOffering
+	plans    []smClientTypes.ServicePlan
+}
+
+// getCurrentServicePlans returns the current service plan of the instance in SM, or nil if it is unknown
+func getCurrentServicePlans(smClient sm.Client, serviceInstance *v1.ServiceInstance) (*servicePlans, error) {
+	smInstance, err := smClient.GetInstanceByID(serviceInstance.Status.InstanceID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return getServicePlans(smClient, smInstance)
+}
+
+// getServicePlans returns the service plan of the instance in SM, or nil if it is unknown
+func getServicePlans(smClient sm.Client, smInstance *smClientTypes.ServiceInstance) (*servicePlans, error) {
+	if smInstance == nil || len(smInstance.ServicePlanID) == 0 {
+		return nil, nil
+	}
+
+	plans, err := smClient.ListPlans(&sm.Parameters{FieldQuery: []string{fmt.Sprintf("id eq '%s'", smInstance.ServicePlanID)}})
+	if err != nil {
+		return nil, err
+	}
+	if plans == nil || len(plans.ServicePlans) != 1 {
+		return nil, fmt.Errorf("could not find plan with id %s", smInstance.ServicePlanID)
+	}
+	result := &servicePlans{plan: plans.ServicePlans[0]}
+
+	offerings, err := smClient.ListOfferings(&sm.Parameters{FieldQuery: []string{fmt.Sprintf("id eq '%s'", result.plan.ServiceOfferingID)}})
+	if err != nil {
+		return nil, err
+	}
+	if offerings == nil || len(offerings.ServiceOfferings) != 1 {
+		return nil, fmt.Errorf("could not find offering with id %s", result.plan.ServiceOfferingID)
+	}
+	result.offering = offerings.ServiceOfferings[0]
+
+	plans, err = smClient.ListPlans(&sm.Parameters{FieldQuery: []string{fmt.Sprintf("service_offering_id eq '%s'", result.offering.ID)}})
+	if err != nil {
+		return nil, err
+	}
+	if plans != nil {
+		result.plans = plans.ServicePlans
+	}
+	return result, nil
+}
+
+// updatable returns whether the current plan can be changed, the plan_updateable of the plan overrides the one of the offering
+func (p *servicePlans) updatable() bool {
+	if p.plan.PlanUpdatable != nil {
+		return *p.plan.PlanUpdatable
+	}
+	return p.offering.PlanUpdatable
+}
+
+// allowedPlanNames returns the names of the plans the instance can be changed to
+func (p *servicePlans) allowedPlanNames() []string {
+	if !p.updatable() {
+		return nil
+	}
+	var names []string
+	for _, plan := range p.plans {
+		if plan.ID != p.plan.ID && !slices.Contains(names, plan.CatalogName) {
+			names = append(names, plan.CatalogName)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// setServicePlansStatus sets the current service plan of the instance, whether it is updatable and the plans it can be changed to
+func setServicePlansStatus(serviceInstance *v1.ServiceInstance, plans *servicePlans) {
+	updatable := plans.updatable()
+	serviceInstance.Status.PlanUpdatable = &updatable
+	serviceInstance.Status.AllowedServicePlans = plans.allowedPlanNames()
+	serviceInstance.Status.ServiceOfferingID = plans.offering.ID
+	serviceInstance.Status.ServiceOfferingName = plans.offering.CatalogName
+	serviceInstance.Status.ServicePlanName = plans.plan.CatalogName
+	serviceInstance.Status.PlanMaintenanceInfo = convertMaintenanceInfo(plans.plan.MaintenanceInfo)
+}
+
+// servicePlansResolved returns whether the current service plan of the instance and the plans it can be changed to are known
+func servicePlansResolved(serviceInstance *v1.ServiceInstance) bool {
+	return serviceInstance.Status.PlanUpdatable != nil && len(serviceInstance.Status.ServicePlanName) > 0
+}
+
+// validateServicePlanChange returns an error if the instance can't be changed from its current plan to the offering and plan of its spec,
+// according to the service plans resolved in its status
+func validateServicePlanChange(serviceInstance *v1.ServiceInstance) error {
+	spec, status := serviceInstance.Spec, serviceInstance.Status
+	if len(status.ServiceOfferingName) > 0 && spec.ServiceOfferingName != status.ServiceOfferingName {
+		return fmt.Errorf("changing the service offering of an existing instance from %s to %s is not allowed", status.ServiceOfferingName, spec.ServiceOfferingName)
+	}
+	if spec.ServicePlanName == status.ServicePlanName && (len(spec.ServicePlanID) == 0 || spec.ServicePlanID == status.ServicePlanID) {
+		return nil
+	}
+	if !*status.PlanUpdatable {
+		return fmt.Errorf("the service plan %s of the service offering %s is not updatable", status.ServicePlanName, status.ServiceOfferingName)
+	}
+	if !slices.Contains(status.AllowedServicePlans, spec.ServicePlanName) {
+		return fmt.Errorf("changing the service plan from %s to %s is not allowed, the allowed service plans are %v", status.ServicePlanName, spec.ServicePlanName, status.AllowedServicePlans)
+	}
+	return nil
+}
+
+// syncSMLabels updates the labels of the instance in SM with the labels mapped from its labels and annotations
+func (r *ServiceInstanceReconciler) syncSMLabels(ctx context.Context, serviceInstance *v1.ServiceInstance, smLabels map[string]string) error {
+	log := utils.GetLogger(ctx)
+	smClient, err := r.GetSMClient(ctx, serviceInstance)
+	if err != nil {
+		log.Error(err, "failed to get sm client")
+		return err
+	}
+
+	changes := utils.GetSMLabelChanges(serviceInstance.Status.SyncedLabels, smLabels)
+	log.Info(fmt.Sprintf("syncing %d label changes to SM", len(changes)))
+	if _, err := smClient.UpdateInstanceLabels(serviceInstance.Status.InstanceID, changes); err != nil {
+		log.Error(err, "failed to update the labels of the instance in SM")
+		r.Recorder.Event(serviceInstance, corev1.EventTypeWarning, common.LabelSyncFailed, err.Error())
+		return err
+	}
+	serviceInstance.Status.SyncedLabels = smLabels
+	return utils.UpdateStatus(ctx, r.Client, serviceInstance)
+}
+
+// servicePlanCheckDue returns whether the service plan of the instance should be checked in SM, and otherwise the time until the next check.
+// A plan that is not resolved yet is checked again after the retry base delay, a failed check is retried with the backoff of the
+// controller, and the first checks of the instances that existed when the operator started are spread over the first plan check window.
+func (r *ServiceInstanceReconciler) servicePlanCheckDue(serviceInstance *v1.ServiceInstance) (bool, time.Duration) {
+	interval := r.Config.PlanCheckInterval
+	if serviceInstance.Status.PlanUpdatable == nil {
+		interval = r.Config.RetryBaseDelay
+	} else if interval <= 0 {
+		// the service plan is checked only when it is unknown
+		return false, 0
+	}
+
+	next := time.Until(firstPlanCheckTime(serviceInstance))
+	if lastCheck := serviceInstance.Status.LastPlanCheckTime; lastCheck != nil {
+		next = max(next, interval-time.Since(lastCheck.Time))
+	}
+	return next <= 0, next
+}
+
+// firstPlanCheckTime returns the earliest time the plan of an instance that existed when the operator started is checked,
+// spread over the first plan check window by the UID of the instance so that it does not change between reconciles
+func firstPlanCheckTime(serviceInstance *v1.ServiceInstance) time.Time {
+	if serviceInstance.CreationTimestamp.After(planChecksStartTime) {
+		return serviceInstance.CreationTimestamp.Time
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(serviceInstance.UID))
+	return planChecksStartTime.Add(time.Duration(hash.Sum32()) % firstPlanCheckWindow)
+}
+
+// checkServicePlan sets whether the plan of the instance is updatable, the plans it can be changed to and the maintenance info
+// of the instance and of its plan, and upgrades the instance if its upgrade policy is Automatic and a new version is available.
+// A failed check returns an error so that it is retried with the backoff of the controller, and updates the status only if it changed,
+// which would otherwise trigger another reconcile right away.
+func (r *ServiceInstanceReconciler) checkServicePlan(ctx context.Context, serviceInstance *v1.ServiceInstance) (ctrl.Result, error) {
+	log := utils.GetLogger(ctx)
+	smClient, err := r.GetSMClient(ctx, serviceInstance)
+	if err != nil {
+		log.Error(err, "failed to get sm client")
+		return ctrl.Result{}, err
+	}
+	smInstance, err := smClient.GetInstanceByID(serviceInstance.Status.InstanceID, nil)
+	if err != nil {
+		log.Info("could not get the instance from SM", "error", err)
+		return ctrl.Result{}, err
+	}
+	if smInstance == nil {
+		return ctrl.Result{}, fmt.Errorf("instance %s was not found in SM", serviceInstance.Status.InstanceID)
+	}
+
+	oldStatus := serviceInstance.Status.DeepCopy()
+	setSMInstanceStatus(serviceInstance, smInstance)
+	serviceInstance.Status.MaintenanceInfo = convertMaintenanceInfo(smInstance.MaintenanceInfo)
+	plans, err := getServicePlans(smClient, smInstance)
+	if err != nil || plans == nil {
+		if err == nil {
+			err = fmt.Errorf("the service plans of instance %s could not be resolved", serviceInstance.Status.InstanceID)
+		}
+		log.Info("could not resolve the service plans of the instance", "error", err)
+		if !apiequality.Semantic.DeepEqual(oldStatus, &serviceInstance.Status) {
+			if updateErr := r.Client.Status().Update(ctx, serviceInstance); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+		}
+		return ctrl.Result{}, err
+	}
+
+	// the time of the check is kept in the status so the checks are not repeated after a restart, and the next check
+	// is jittered so the checks of instances that were created or checked together spread out over time
+	serviceInstance.Status.LastPlanCheckTime = &metav1.Time{Time: time.Now()}
+	setServicePlansStatus(serviceInstance, plans)
+	setUpgradeAvailableCondition(serviceInstance)
+
+	if serviceInstance.Spec.UpgradePolicy == v1.UpgradePolicyAutomatic && getTargetMaintenanceInfo(serviceInstance) != nil {
+		log.Info("a new maintenance info version is available and the upgrade policy is Automatic, upgrading the instance")
+		return r.updateInstance(ctx, smClient, serviceInstance)
+	}
+	result := ctrl.Result{}
+	if r.Config.PlanCheckInterval > 0 {
+		result.RequeueAfter = wait.Jitter(r.Config.PlanCheckInterval, planCheckJitterFactor)
+	}
+	return result, r.Client.Status().Update(ctx, serviceInstance)
+}
+
+// getTargetMaintenanceInfo returns the maintenance info the instance should be upgraded to, or nil if it should not be upgraded
+func getTargetMaintenanceInfo(serviceInstance *v1.ServiceInstance) *v1.MaintenanceInfo {
+	target := serviceInstance.Spec.MaintenanceInfo
+	if serviceInstance.Spec.UpgradePolicy == v1.UpgradePolicyAutomatic {
+		target = serviceInstance.Status.PlanMaintenanceInfo
+	}
+	if target == nil || (serviceInstance.Status.MaintenanceInfo != nil && serviceInstance.Status.MaintenanceInfo.Version == target.Version) {
+		return nil
+	}
+	return target
+}
+
+// setUpgradeAvailableCondition sets whether the service plan of the instance has a newer maintenance info version than the instance,
+// the condition is removed if the service plan has no maintenance info
+func setUpgradeAvailableCondition(serviceInstance *v1.ServiceInstance) {
+	conditions := serviceInstance.GetConditions()
+	planMaintenanceInfo := serviceInstance.Status.PlanMaintenanceInfo
+	if planMaintenanceInfo == nil {
+		meta.RemoveStatusCondition(&conditions, common.ConditionUpgradeAvailable)
+		serviceInstance.SetConditions(conditions)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               common.ConditionUpgradeAvailable,
+		Status:             metav1.ConditionFalse,
+		Reason:             common.MaintenanceInfoUpToDate,
+		Message:            fmt.Sprintf("the instance has the current maintenance info version %s of its service plan", planMaintenanceInfo.Version),
+		ObservedGeneration: serviceInstance.GetGeneration(),
+	}
+	if serviceInstance.Status.MaintenanceInfo == nil || serviceInstance.Status.MaintenanceInfo.Version != planMaintenanceInfo.Version {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = common.MaintenanceInfoOutdated
+		condition.Message = fmt.Sprintf("maintenance info version %s of the service plan is available", planMaintenanceInfo.Version)
+		if len(planMaintenanceInfo.Description) > 0 {
+			condition.Message = fmt.Sprintf("%s: %s", condition.Message, planMaintenanceInfo.Description)
+		}
+	}
+	meta.SetStatusCondition(&conditions, condition)
+	serviceInstance.SetConditions(conditions)
+}
+
+// setSMInstanceStatus sets the status fields of the instance that describe the instance in SM
+func setSMInstanceStatus(serviceInstance *v1.ServiceInstance, smInstance *smClientTypes.ServiceInstance) {
+	if len(smInstance.ServicePlanID) > 0 {
+		serviceInstance.Status.ServicePlanID = smInstance.ServicePlanID
+	}
+	if len(smInstance.ServiceID) > 0 {
+		serviceInstance.Status.ServiceOfferingID = smInstance.ServiceID
+	}
+	if dashboardURL := getDashboardURL(smInstance); len(dashboardURL) > 0 {
+		serviceInstance.Status.DashboardURL = dashboardURL
+	}
+	usable := smInstance.Usable
+	serviceInstance.Status.Usable = &usable
+	serviceInstance.Status.CreatedAt = parseSMTime(smInstance.CreatedAt)
+	serviceInstance.Status.UpdatedAt = parseSMTime(smInstance.UpdatedAt)
+	if smInstance.LastOperation != nil {
+		serviceInstance.Status.LastOperation = convertLastOperation(smInstance.LastOperation)
+	}
+}
+
+// getDashboardURL returns the dashboard URL of the instance, or the one of its context if the instance doesn't have one
+func getDashboardURL(smInstance *smClientTypes.ServiceInstance) string {
+	if len(smInstance.DashboardURL) > 0 {
+		return smInstance.DashboardURL
+	}
+	instanceContext := struct {
+		DashboardURL string `json:"dashboard_url"`
+	}{}
+	if len(smInstance.Context) > 0 && json.Unmarshal(smInstance.Context, &instanceContext) == nil {
+		return instanceContext.DashboardURL
+	}
+	return ""
+}
+
+func convertLastOperation(operation *smClientTypes.Operation) *v1.LastOperation {
+	return &v1.LastOperation{
+		Type:        operation.Type,
+		State:       operation.State,
+		Description: operation.Description,
+		UpdatedAt:   parseSMTime(operation.Updated),
+	}
+}
+
+// parseSMTime returns the time of an SM timestamp in the precision of the status, or nil if it is not set
+func parseSMTime(value string) *metav1.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &metav1.Time{Time: t.Truncate(time.Second)}
+}
+
+func convertMaintenanceInfo(maintenanceInfo *smClientTypes.MaintenanceInfo) *v1.MaintenanceInfo {
+	if maintenanceInfo == nil || len(maintenanceInfo.Version) == 0 {
+		return nil
+	}
+	return &v1.MaintenanceInfo{Version: maintenanceInfo.Version, Description: maintenanceInfo.Description}
+}
+
 func getTags(tags []byte) ([]string, error) {
 	var tagsArr []string
 	if err := json.Unmarshal(tags, &tagsArr); err != nil {
@@ -797,6 +1300,20 @@ func getErrorMsgFromLastOperation(status *smClientTypes.Operation) string {
 	return errMsg
 }
 
+func (r *ServiceInstanceReconciler) instancesForNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	instances := &v1.ServiceInstanceList{}
+	if err := r.Client.List(ctx, instances, client.InNamespace(obj.GetName())); err != nil {
+		r.Log.Error(err, "failed to list service instances of namespace", "namespace", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(instances.Items))
+	for _, instance := range instances.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}})
+	}
+	return requests
+}
+
 type SecretPredicate struct {
 	predicate.Funcs
 }
@@ -2,10 +2,12 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -225,6 +227,33 @@ var _ = Describe("ServiceInstance controller", func() {
 					Expect(params).To(ContainSubstring("\"key\":\"value\""))
 					Expect(params).To(ContainSubstring("\"secret-key\":\"secret-value\""))
 				})
+
+				It("should record an event of the provisioning", func() {
+					serviceInstance = createInstance(ctx, fakeInstanceName, instanceSpec, nil, true)
+					waitForEvent(ctx, serviceInstance, corev1.EventTypeNormal, common.Created)
+				})
+
+				It("should show the instance details of SM in the status", func() {
+					fakeClient.ProvisionReturns(&sm.ProvisionResponse{InstanceID: fakeInstanceID, PlanID: "plan-id", OfferingID: "offering-id",
+						Instance: &smclientTypes.ServiceInstance{
+							ID:            fakeInstanceID,
+							ServicePlanID: "plan-id",
+							Usable:        true,
+							CreatedAt:     "2024-05-01T10:00:00.123Z",
+							UpdatedAt:     "2024-05-01T10:05:00Z",
+							Context:       json.RawMessage(`{"dashboard_url":"https://dashboard.example.com"}`),
+							LastOperation: &smClientTypes.Operation{Type: smClientTypes.CREATE, State: smClientTypes.SUCCEEDED, Updated: "2024-05-01T10:05:00Z"},
+						}}, nil)
+					serviceInstance = createInstance(ctx, fakeInstanceName, instanceSpec, nil, true)
+					Expect(serviceInstance.Status.ServicePlanID).To(Equal("plan-id"))
+					Expect(serviceInstance.Status.ServiceOfferingID).To(Equal("offering-id"))
+					Expect(serviceInstance.Status.DashboardURL).To(Equal("https://dashboard.example.com"))
+					Expect(*serviceInstance.Status.Usable).To(BeTrue())
+					Expect(serviceInstance.Status.CreatedAt.UTC()).To(Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+					Expect(serviceInstance.Status.UpdatedAt.UTC()).To(Equal(time.Date(2024, 5, 1, 10, 5, 0, 0, time.UTC)))
+					Expect(serviceInstance.Status.LastOperation.Type).To(Equal(smClientTypes.CREATE))
+					Expect(serviceInstance.Status.LastOperation.State).To(Equal(smClientTypes.SUCCEEDED))
+				})
 			})
 			When("provision request to SM fails", func() {
 				errMessage := "failed to provision instance"
@@ -242,6 +271,17 @@ var _ = Describe("ServiceInstance controller", func() {
 						serviceInstance = createInstance(ctx, fakeInstanceName, instanceSpec, nil, false)
 						waitForResourceCondition(ctx, serviceInstance, common.ConditionSucceeded, metav1.ConditionTrue, common.Created, "")
 					})
+
+					It("should record the failed and the succeeded operations", func() {
+						serviceInstance = createInstance(ctx, fakeInstanceName, instanceSpec, nil, false)
+						waitForResourceCondition(ctx, serviceInstance, common.ConditionSucceeded, metav1.ConditionTrue, common.Created, "")
+						Expect(serviceInstance.Status.Operations).To(HaveLen(2))
+						Expect(serviceInstance.Status.Operations[0].Type).To(Equal(smClientTypes.CREATE))
+						Expect(serviceInstance.Status.Operations[0].State).To(Equal(smClientTypes.FAILED))
+						Expect(serviceInstance.Status.Operations[0].Description).To(ContainSubstring(errMessage))
+						Expect(serviceInstance.Status.Operations[1].State).To(Equal(smClientTypes.SUCCEEDED))
+						Expect(serviceInstance.Status.Operations[1].CompletedAt).NotTo(BeNil())
+					})
 				})
 
 				Context("removing non transient error handling", func() {
@@ -331,6 +371,12 @@ var _ = Describe("ServiceInstance controller", func() {
 					}, nil)
 					waitForResourceCondition(ctx, serviceInstance, common.ConditionSucceeded, metav1.ConditionTrue, common.Created, "")
 					Expect(serviceInstance.Status.SubaccountID).To(Equal(fakeSubaccountID))
+					Expect(serviceInstance.Status.LastOperation.Type).To(Equal(smClientTypes.CREATE))
+					Expect(serviceInstance.Status.LastOperation.State).To(Equal(smClientTypes.SUCCEEDED))
+					Expect(serviceInstance.Status.Operations).To(HaveLen(1))
+					Expect(serviceInstance.Status.Operations[0].ID).To(Equal("1234"))
+					Expect(serviceInstance.Status.Operations[0].State).To(Equal(smClientTypes.SUCCEEDED))
+					Expect(serviceInstance.Status.Operations[0].CompletedAt).NotTo(BeNil())
 				})
 			})
 
@@ -344,6 +390,10 @@ var _ = Describe("ServiceInstance controller", func() {
 						Errors: []byte(`{"error": "brokerError","description":"broker-failure"}`),
 					}, nil)
 					waitForInstanceConditionAndMessage(ctx, defaultLookupKey, common.ConditionFailed, "broker-failure")
+					Expect(k8sClient.Get(ctx, defaultLookupKey, serviceInstance)).To(Succeed())
+					Expect(serviceInstance.Status.Operations).To(HaveLen(1))
+					Expect(serviceInstance.Status.Operations[0].State).To(Equal(smClientTypes.FAILED))
+					Expect(serviceInstance.Status.Operations[0].Description).To(ContainSubstring("broker-failure"))
 				})
 			})
 
@@ -605,6 +655,185 @@ var _ = Describe("ServiceInstance controller", func() {
 			})
 		})
 
+		Context("service plan changed", func() {
+			var offering smclientTypes.ServiceOffering
+
+			stubServicePlans := func() {
+				fakeClient.GetInstanceByIDReturns(&smclientTypes.ServiceInstance{ID: fakeInstanceID, ServicePlanID: "plan-a-id", Ready: true,
+					LastOperation: &smClientTypes.Operation{State: smClientTypes.SUCCEEDED, Type: smClientTypes.CREATE}}, nil)
+				fakeClient.ListOfferingsReturns(&smclientTypes.ServiceOfferings{ServiceOfferings: []smclientTypes.ServiceOffering{offering}}, nil)
+				fakeClient.ListPlansStub = func(params *sm.Parameters) (*smclientTypes.ServicePlans, error) {
+					plans := []smclientTypes.ServicePlan{
+						{ID: "plan-a-id", CatalogName: fakePlanName, ServiceOfferingID: offering.ID},
+						{ID: "plan-b-id", CatalogName: "plan-b", ServiceOfferingID: offering.ID},
+					}
+					if params.FieldQuery[0] == "id eq 'plan-a-id'" {
+						plans = plans[:1]
+					}
+					return &smclientTypes.ServicePlans{ServicePlans: plans}, nil
+				}
+			}
+
+			BeforeEach(func() {
+				offering = smclientTypes.ServiceOffering{ID: "offering-a-id", CatalogName: fakeOfferingName, PlanUpdatable: true}
+				fakeClient.UpdateInstanceReturns(nil, "", nil)
+			})
+
+			It("should show the allowed service plans and reject the other plans", func() {
+				deleteInstance(ctx, serviceInstance, true)
+				stubServicePlans()
+				serviceInstance = createInstance(ctx, fakeInstanceName, instanceSpec, nil, true)
+				Eventually(func() []string {
+					Expect(k8sClient.Get(ctx, defaultLookupKey, serviceInstance)).To(Succeed())
+					return serviceInstance.Status.AllowedServicePlans
+				}, timeout, interval).Should(Equal([]string{"plan-b"}))
+				Expect(*serviceInstance.Status.PlanUpdatable).To(BeTrue())
+				Expect(serviceInstance.Status.ServicePlanName).To(Equal(fakePlanName))
+				Expect(serviceInstance.Status.ServiceOfferingName).To(Equal(fakeOfferingName))
+
+				serviceInstance.Spec.ServicePlanName = "plan-c"
+				err := k8sClient.Update(ctx, serviceInstance)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("changing the service plan from plan-a to plan-c is not allowed, the allowed service plans are [plan-b]"))
+
+				serviceInstance.Spec.ServicePlanName = "plan-b"
+				serviceInstance = updateInstance(ctx, serviceInstance)
+				waitForResourceCondition(ctx, serviceInstance, common.ConditionSucceeded, metav1.ConditionTrue, common.Updated, "")
+			})
+
+			It("should reject changing the service offering", func() {
+				serviceInstance.Spec.ServiceOfferingName = "offering-b"
+				err := k8sClient.Update(ctx, serviceInstance)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("changing the serviceOfferingName for an existing instance is not allowed"))
+			})
+
+			It("should fail the update if the plan is not updatable", func() {
+				offering.PlanUpdatable = false
+				stubServicePlans()
+				serviceInstance.Spec.ServicePlanName = "plan-b"
+				serviceInstance = updateInstance(ctx, serviceInstance)
+				waitForResourceCondition(ctx, serviceInstance, common.ConditionSucceeded, metav1.ConditionFalse, common.UpdateFailed,
+					"the service plan plan-a of the service offering offering-a is not updatable")
+				Expect(fakeClient.UpdateInstanceCallCount()).To(BeZero())
+			})
+		})
+
+		Context("maintenance info", func() {
+			BeforeEach(func() {
+				offering := smclientTypes.ServiceOffering{ID: "offering-a-id", CatalogName: fakeOfferingName}
+				fakeClient.GetInstanceByIDReturns(&smclientTypes.ServiceInstance{ID: fakeInstanceID, ServicePlanID: "plan-a-id", Ready: true,
+					MaintenanceInfo: &smclientTypes.MaintenanceInfo{Version: "1.0.0"},
+					LastOperation:   &smClientTypes.Operation{State: smClientTypes.SUCCEEDED, Type: smClientTypes.CREATE}}, nil)
+				fakeClient.ListOfferingsReturns(&smclientTypes.ServiceOfferings{ServiceOfferings: []smclientTypes.ServiceOffering{offering}}, nil)
+				fakeClient.ListPlansReturns(&smclientTypes.ServicePlans{ServicePlans: []smclientTypes.ServicePlan{
+					{ID: "plan-a-id", CatalogName: fakePlanName, ServiceOfferingID: offering.ID,
+						MaintenanceInfo: &smclientTypes.MaintenanceInfo{Version: "2.0.0", Description: "security fixes"}},
+				}}, nil)
+				fakeClient.UpdateInstanceReturns(nil, "", nil)
+				deleteInstance(ctx, serviceInstance, true)
+			})
+
+			It("should show the available upgrade and upgrade to spec.maintenanceInfo", func() {
+				serviceInstance = createInstance(ctx, fakeInstanceName, instanceSpec, nil, true)
+				waitForResourceCondition(ctx, serviceInstance, common.ConditionUpgradeAvailable, metav1.ConditionTrue, common.MaintenanceInfoOutdated,
+					"maintenance info version 2.0.0 of the service plan is available: security fixes")
+				Expect(serviceInstance.Status.MaintenanceInfo.Version).To(Equal("1.0.0"))
+				Expect(serviceInstance.Status.PlanMaintenanceInfo.Version).To(Equal("2.0.0"))
+				Expect(fakeClient.UpdateInstanceCallCount()).To(BeZero())
+
+				serviceInstance.Spec.MaintenanceInfo = &v1.MaintenanceInfo{Version: "2.0.0"}
+				serviceInstance = updateInstance(ctx, serviceInstance)
+				waitForResourceCondition(ctx, serviceInstance, common.ConditionUpgradeAvailable, metav1.ConditionFalse, common.MaintenanceInfoUpToDate, "")
+				Expect(serviceInstance.Status.MaintenanceInfo.Version).To(Equal("2.0.0"))
+				Expect(fakeClient.UpdateInstanceCallCount()).To(Equal(1))
+				_, smInstance, _, _, _, _, _ := fakeClient.UpdateInstanceArgsForCall(0)
+				Expect(smInstance.MaintenanceInfo.Version).To(Equal("2.0.0"))
+			})
+
+			It("should not check the service plan again before the next check is due", func() {
+				serviceInstance = createInstance(ctx, fakeInstanceName, instanceSpec, nil, true)
+				Eventually(func() *metav1.Time {
+					Expect(k8sClient.Get(ctx, defaultLookupKey, serviceInstance)).To(Succeed())
+					return serviceInstance.Status.LastPlanCheckTime
+				}, timeout, interval).ShouldNot(BeNil())
+				checks := fakeClient.GetInstanceByIDCallCount()
+
+				serviceInstance.Annotations = map[string]string{"reconcile": "again"}
+				serviceInstance = updateInstance(ctx, serviceInstance)
+				Consistently(fakeClient.GetInstanceByIDCallCount, time.Second, interval).Should(Equal(checks))
+			})
+
+			It("should retry a failed service plan check with a backoff", func() {
+				fakeClient.ListOfferingsReturns(nil, errors.New("catalog unavailable"))
+				serviceInstance = createInstance(ctx, fakeInstanceName, instanceSpec, nil, true)
+				Eventually(fakeClient.ListOfferingsCallCount, timeout, interval).ShouldNot(BeZero())
+				checks := fakeClient.GetInstanceByIDCallCount()
+
+				// the status of the instance from SM may be written once, which triggers one more check
+				Consistently(fakeClient.GetInstanceByIDCallCount, 2*time.Second, interval).Should(BeNumerically("<=", checks+1))
+				Expect(k8sClient.Get(ctx, defaultLookupKey, serviceInstance)).To(Succeed())
+				Expect(serviceInstance.Status.LastPlanCheckTime).To(BeNil())
+				Expect(serviceInstance.Status.PlanUpdatable).To(BeNil())
+			})
+
+			It("should upgrade the instance when the upgrade policy is Automatic", func() {
+				spec := instanceSpec
+				spec.UpgradePolicy = v1.UpgradePolicyAutomatic
+				serviceInstance = createInstance(ctx, fakeInstanceName, spec, nil, true)
+				waitForResourceCondition(ctx, serviceInstance, common.ConditionUpgradeAvailable, metav1.ConditionFalse, common.MaintenanceInfoUpToDate, "")
+				Expect(serviceInstance.Status.MaintenanceInfo.Version).To(Equal("2.0.0"))
+				Expect(fakeClient.UpdateInstanceCallCount()).To(Equal(1))
+				_, smInstance, _, _, _, _, _ := fakeClient.UpdateInstanceArgsForCall(0)
+				Expect(smInstance.MaintenanceInfo.Version).To(Equal("2.0.0"))
+			})
+		})
+
+		When("labels synced to SM changed", func() {
+			It("should update the labels of the instance in SM", func() {
+				deleteInstance(ctx, serviceInstance, true)
+				serviceInstance = createInstance(ctx, fakeInstanceName, instanceSpec, map[string]string{"cost-center": "1234"}, true)
+				smInstance, _, _, _, _, _ := fakeClient.ProvisionArgsForCall(0)
+				Expect(smInstance.Labels["cost_center"]).To(Equal([]string{"1234"}))
+				Expect(serviceInstance.Status.SyncedLabels).To(Equal(map[string]string{"cost_center": "1234"}))
+
+				serviceInstance.Labels = map[string]string{"team": "a-team"}
+				serviceInstance.Annotations["cost-center"] = "5678"
+				serviceInstance = updateInstance(ctx, serviceInstance)
+				Eventually(func() map[string]string {
+					Expect(k8sClient.Get(ctx, defaultLookupKey, serviceInstance)).To(Succeed())
+					return serviceInstance.Status.SyncedLabels
+				}, timeout, interval).Should(Equal(map[string]string{"team": "a-team", "cost_center": "5678"}))
+				Expect(fakeClient.UpdateInstanceLabelsCallCount()).To(Equal(1))
+				id, changes := fakeClient.UpdateInstanceLabelsArgsForCall(0)
+				Expect(id).To(Equal(fakeInstanceID))
+				Expect(changes).To(Equal([]*smClientTypes.LabelChange{
+					{Operation: smClientTypes.RemoveLabelOperation, Key: "cost_center", Values: []string{"1234"}},
+					{Operation: smClientTypes.AddLabelOperation, Key: "cost_center", Values: []string{"5678"}},
+					{Operation: smClientTypes.AddLabelOperation, Key: "team", Values: []string{"a-team"}},
+				}))
+				Expect(fakeClient.UpdateInstanceCallCount()).To(BeZero())
+			})
+		})
+
+		When("force reconcile annotation is set", func() {
+			It("should update the instance in SM and remove the annotation", func() {
+				if serviceInstance.Annotations == nil {
+					serviceInstance.Annotations = map[string]string{}
+				}
+				serviceInstance.Annotations[common.ForceReconcileAnnotation] = time.Now().Format(time.RFC3339)
+				serviceInstance = updateInstance(ctx, serviceInstance)
+				Eventually(func() bool {
+					Expect(k8sClient.Get(ctx, defaultLookupKey, serviceInstance)).To(Succeed())
+					_, ok := serviceInstance.Annotations[common.ForceReconcileAnnotation]
+					return ok
+				}, timeout, interval).Should(BeFalse())
+				Eventually(fakeClient.UpdateInstanceCallCount, timeout, interval).Should(Equal(1))
+				waitForResourceToBeReady(ctx, serviceInstance)
+				waitForEvent(ctx, serviceInstance, corev1.EventTypeNormal, common.ForceReconciled)
+			})
+		})
+
 		When("subaccount id changed", func() {
 			It("should fail", func() {
 				deleteInstance(ctx, serviceInstance, true)
@@ -824,6 +1053,51 @@ var _ = Describe("ServiceInstance controller", func() {
 				Expect(smCallArgs.FieldQuery[2]).To(ContainSubstring("context/namespace"))
 			})
 
+			When("the instance has no cluster ID label", func() {
+				BeforeEach(func() {
+					// instances created by older versions are found by the cluster ID of their context only
+					fakeClient.ListInstancesStub = func(params *sm.Parameters) (*smclientTypes.ServiceInstances, error) {
+						for _, query := range params.LabelQuery {
+							if strings.Contains(query, "_clusterid") {
+								return &smclientTypes.ServiceInstances{}, nil
+							}
+						}
+						return &smclientTypes.ServiceInstances{ServiceInstances: []smclientTypes.ServiceInstance{recoveredInstance}}, nil
+					}
+				})
+
+				It("should recover the instance by the cluster ID of its context", func() {
+					serviceInstance = createInstance(ctx, fakeInstanceName, instanceSpec, nil, true)
+					Expect(fakeClient.ProvisionCallCount()).To(Equal(0))
+					Expect(serviceInstance.Status.InstanceID).To(Equal(fakeInstanceID))
+					Expect(fakeClient.ListInstancesCallCount()).To(Equal(1))
+				})
+			})
+
+			When("the cluster ID was migrated", func() {
+				BeforeEach(func() {
+					// after a migration the cluster ID label is changed, the context keeps the initial cluster ID
+					fakeClient.ListInstancesStub = func(params *sm.Parameters) (*smclientTypes.ServiceInstances, error) {
+						for _, query := range params.FieldQuery {
+							if strings.Contains(query, "context/clusterid") {
+								return &smclientTypes.ServiceInstances{}, nil
+							}
+						}
+						return &smclientTypes.ServiceInstances{ServiceInstances: []smclientTypes.ServiceInstance{recoveredInstance}}, nil
+					}
+				})
+
+				It("should recover the instance by the cluster ID label", func() {
+					serviceInstance = createInstance(ctx, fakeInstanceName, instanceSpec, nil, true)
+					Expect(fakeClient.ProvisionCallCount()).To(Equal(0))
+					Expect(serviceInstance.Status.InstanceID).To(Equal(fakeInstanceID))
+					smCallArgs := fakeClient.ListInstancesArgsForCall(1)
+					Expect(smCallArgs.LabelQuery).To(HaveLen(2))
+					Expect(smCallArgs.LabelQuery[1]).To(ContainSubstring("_clusterid"))
+					Expect(smCallArgs.FieldQuery).To(HaveLen(2))
+				})
+			})
+
 			Context("last operation", func() {
 				When("last operation state is PENDING and ends with success", func() {
 					BeforeEach(func() {
@@ -1527,6 +1801,21 @@ func waitForInstanceConditionAndMessage(ctx context.Context, key types.Namespace
 	}, timeout, interval).Should(BeTrue())
 }
 
+func waitForEvent(ctx context.Context, obj client.Object, eventType, reason string) {
+	Eventually(func() bool {
+		events := &corev1.EventList{}
+		if err := k8sClient.List(ctx, events, client.InNamespace(obj.GetNamespace())); err != nil {
+			return false
+		}
+		for _, event := range events.Items {
+			if event.InvolvedObject.UID == obj.GetUID() && event.Type == eventType && event.Reason == reason {
+				return true
+			}
+		}
+		return false
+	}, timeout, interval).Should(BeTrue(), fmt.Sprintf("expected %s event %s of %s", eventType, reason, obj.GetName()))
+}
+
 func waitForInstanceToBeShared(ctx context.Context, serviceInstance *v1.ServiceInstance) {
 	waitForResourceCondition(ctx, serviceInstance, common.ConditionShared, metav1.ConditionTrue, "", "")
 	Expect(len(serviceInstance.Status.Conditions)).To(Equal(3))
@@ -120,7 +120,7 @@ var _ = BeforeSuite(func(done Done) {
 	Expect(err).ToNot(HaveOccurred())
 	Expect(k8sClient).ToNot(BeNil())
 
-	utils.InitializeSecretsClient(k8sClient, nil, config.Config{EnableLimitedCache: false})
+	utils.InitializeSecretsClient(k8sClient, nil, config.Config{EnableLimitedCache: false, ManagementNamespace: testNamespace, ReleaseNamespace: testNamespace})
 
 	webhookInstallOptions := &testEnv.WebhookInstallOptions
 
@@ -142,12 +142,19 @@ var _ = BeforeSuite(func(done Done) {
 	testConfig := config.Get()
 	testConfig.SyncPeriod = syncPeriod
 	testConfig.PollInterval = pollInterval
+	testConfig.SMLabelsFromLabels = map[string]string{"team": "team"}
+	testConfig.SMLabelsFromAnnotations = map[string]string{"cost-center": "cost_center"}
+	testConfig.EnforceServiceBindingGrants = true
+	testConfig.EnableClusterIDMigration = true
 
 	By("registering webhooks")
 	k8sManager.GetWebhookServer().Register("/mutate-services-cloud-sap-com-v1-serviceinstance", &webhook.Admission{Handler: &webhooks.ServiceInstanceDefaulter{Decoder: admission.NewDecoder(k8sManager.GetScheme())}})
 	k8sManager.GetWebhookServer().Register("/mutate-services-cloud-sap-com-v1-servicebinding", &webhook.Admission{Handler: &webhooks.ServiceBindingDefaulter{Decoder: admission.NewDecoder(k8sManager.GetScheme())}})
 
-	err = (&v1.ServiceBinding{}).SetupWebhookWithManager(k8sManager)
+	err = (&v1.ServiceBinding{}).SetupWebhookWithManager(k8sManager, v1.ServiceBindingWebhookOptions{
+		GetSecretSinkSecret:         utils.GetSecretSinkSecret,
+		EnforceServiceBindingGrants: testConfig.EnforceServiceBindingGrants,
+	})
 	Expect(err).ToNot(HaveOccurred())
 
 	err = (&v1.ServiceInstance{}).SetupWebhookWithManager(k8sManager)
@@ -173,8 +180,9 @@ var _ = BeforeSuite(func(done Done) {
 		GetSMClient: func(_ context.Context, _ *v1.ServiceInstance) (sm.Client, error) {
 			return fakeClient, nil
 		},
-		Config:   testConfig,
-		Recorder: k8sManager.GetEventRecorderFor("ServiceBinding"),
+		GetSecretSink: utils.GetSecretSink,
+		Config:        testConfig,
+		Recorder:      k8sManager.GetEventRecorderFor("ServiceBinding"),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -13,19 +13,28 @@ var (
 )
 
 type Config struct {
-	SyncPeriod             time.Duration `envconfig:"sync_period"`
-	PollInterval           time.Duration `envconfig:"poll_interval"`
-	LongPollInterval       time.Duration `envconfig:"long_poll_interval"`
-	ManagementNamespace    string        `envconfig:"management_namespace"`
-	ReleaseNamespace       string        `envconfig:"release_namespace"`
-	AllowClusterAccess     bool          `envconfig:"allow_cluster_access"`
-	AllowedNamespaces      []string      `envconfig:"allowed_namespaces"`
-	EnableNamespaceSecrets bool          `envconfig:"enable_namespace_secrets"`
-	EnableLimitedCache     bool          `envconfig:"enable_limited_cache"`
-	ClusterID              string        `envconfig:"cluster_id"`
-	InitialClusterID       string        `envconfig:"initial_cluster_id"`
-	RetryBaseDelay         time.Duration
-	RetryMaxDelay          time.Duration
+	SyncPeriod                  time.Duration     `envconfig:"sync_period"`
+	PollInterval                time.Duration     `envconfig:"poll_interval"`
+	LongPollInterval            time.Duration     `envconfig:"long_poll_interval"`
+	ManagementNamespace         string            `envconfig:"management_namespace"`
+	ReleaseNamespace            string            `envconfig:"release_namespace"`
+	AllowClusterAccess          bool              `envconfig:"allow_cluster_access"`
+	AllowedNamespaces           []string          `envconfig:"allowed_namespaces"`
+	EnableNamespaceSecrets      bool              `envconfig:"enable_namespace_secrets"`
+	EnableLimitedCache          bool              `envconfig:"enable_limited_cache"`
+	ClusterID                   string            `envconfig:"cluster_id"`
+	InitialClusterID            string            `envconfig:"initial_cluster_id"`
+	EnableClusterIDMigration    bool              `envconfig:"enable_cluster_id_migration"`
+	OrphanScanInterval          time.Duration     `envconfig:"orphan_scan_interval"`
+	EnableOrphanCleanup         bool              `envconfig:"enable_orphan_cleanup"`
+	OrphanGracePeriod           time.Duration     `envconfig:"orphan_grace_period"`
+	PlanCheckInterval           time.Duration     `envconfig:"plan_check_interval"`
+	SMLabelsFromLabels          map[string]string `envconfig:"sm_labels_from_labels"`
+	SMLabelsFromAnnotations     map[string]string `envconfig:"sm_labels_from_annotations"`
+	AllowDeleteWhenPaused       bool              `envconfig:"allow_delete_when_paused"`
+	EnforceServiceBindingGrants bool              `envconfig:"enforce_service_binding_grants"`
+	RetryBaseDelay              time.Duration
+	RetryMaxDelay               time.Duration
 }
 
 func Get() Config {
@@ -40,6 +49,9 @@ func Get() Config {
 			AllowClusterAccess:     true,
 			RetryBaseDelay:         10 * time.Second,
 			RetryMaxDelay:          3 * time.Hour,
+			OrphanScanInterval:     time.Hour,
+			OrphanGracePeriod:      24 * time.Hour,
+			PlanCheckInterval:      time.Hour,
 		}
 		envconfig.MustProcess("", &config)
 	})
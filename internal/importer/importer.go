@@ -0,0 +1,211 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	"github.com/SAP/sap-btp-service-operator/client/sm"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	"github.com/SAP/sap-btp-service-operator/internal/httputil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Importer builds ServiceInstance and ServiceBinding custom resources for the Service Manager resources of a cluster.
+// The custom resources keep the name, namespace and external name of the original ones,
+// so once applied the operator adopts the existing resources instead of creating new ones.
+type Importer struct {
+	SMClient  sm.Client
+	ClusterID string
+	// Namespace limits the import to the resources of a single namespace, all namespaces are imported if empty
+	Namespace string
+
+	plans     map[string]*smClientTypes.ServicePlan
+	offerings map[string]*smClientTypes.ServiceOffering
+}
+
+// Result holds the outcome of applying the imported resources
+type Result struct {
+	Created []client.Object
+	Skipped []client.Object
+}
+
+// Build returns the ServiceInstances followed by the ServiceBindings of the cluster
+func (i *Importer) Build(ctx context.Context) ([]client.Object, error) {
+	params := &sm.Parameters{
+		LabelQuery: []string{fmt.Sprintf("%s eq '%s'", common.ClusterIDLabel, i.ClusterID)},
+	}
+	if len(i.Namespace) > 0 {
+		params.LabelQuery = append(params.LabelQuery, fmt.Sprintf("%s eq '%s'", common.NamespaceLabel, i.Namespace))
+	}
+
+	smInstances, err := i.SMClient.ListInstances(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []client.Object
+	instancesByID := make(map[string]*v1.ServiceInstance)
+	for _, smInstance := range smInstances.ServiceInstances {
+		instance, err := i.buildInstance(smInstance)
+		if err != nil {
+			return nil, err
+		}
+		if instance == nil {
+			continue
+		}
+		instancesByID[smInstance.ID] = instance
+		objects = append(objects, instance)
+	}
+
+	smBindings, err := i.SMClient.ListBindings(params)
+	if err != nil {
+		return nil, err
+	}
+	for _, smBinding := range smBindings.ServiceBindings {
+		binding, err := i.buildBinding(smBinding, instancesByID)
+		if err != nil {
+			return nil, err
+		}
+		if binding != nil {
+			objects = append(objects, binding)
+		}
+	}
+
+	return objects, nil
+}
+
+// Apply creates the given resources, resources that already exist in the cluster are skipped
+func Apply(ctx context.Context, k8sClient client.Client, objects []client.Object) (*Result, error) {
+	result := &Result{}
+	for _, obj := range objects {
+		if err := k8sClient.Create(ctx, obj); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				result.Skipped = append(result.Skipped, obj)
+				continue
+			}
+			return result, fmt.Errorf("failed to create %s %s/%s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetNamespace(), obj.GetName(), err)
+		}
+		result.Created = append(result.Created, obj)
+	}
+	return result, nil
+}
+
+func (i *Importer) buildInstance(smInstance smClientTypes.ServiceInstance) (*v1.ServiceInstance, error) {
+	name, namespace := labelValue(smInstance.Labels, common.K8sNameLabel), labelValue(smInstance.Labels, common.NamespaceLabel)
+	if len(name) == 0 || len(namespace) == 0 {
+		return nil, nil
+	}
+
+	plan, offering, err := i.getPlanAndOffering(smInstance.ServicePlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	instance := &v1.ServiceInstance{}
+	instance.SetGroupVersionKind(v1.GroupVersion.WithKind("ServiceInstance"))
+	instance.Name = name
+	instance.Namespace = namespace
+	instance.Spec = v1.ServiceInstanceSpec{
+		ServiceOfferingName: offering.CatalogName,
+		ServicePlanName:     plan.CatalogName,
+		ServicePlanID:       plan.ID,
+		ExternalName:        smInstance.Name,
+		Parameters:          i.getParameters(smClientTypes.ServiceInstancesURL, smInstance.ID),
+	}
+	return instance, nil
+}
+
+func (i *Importer) buildBinding(smBinding smClientTypes.ServiceBinding, instancesByID map[string]*v1.ServiceInstance) (*v1.ServiceBinding, error) {
+	name, namespace := labelValue(smBinding.Labels, common.K8sNameLabel), labelValue(smBinding.Labels, common.NamespaceLabel)
+	instance := instancesByID[smBinding.ServiceInstanceID]
+	if len(name) == 0 || len(namespace) == 0 || instance == nil {
+		return nil, nil
+	}
+
+	binding := &v1.ServiceBinding{}
+	binding.SetGroupVersionKind(v1.GroupVersion.WithKind("ServiceBinding"))
+	binding.Name = name
+	binding.Namespace = namespace
+	binding.Spec = v1.ServiceBindingSpec{
+		ServiceInstanceName: instance.Name,
+		ExternalName:        smBinding.Name,
+		Parameters:          i.getParameters(smClientTypes.ServiceBindingsURL, smBinding.ID),
+	}
+	if instance.Namespace != namespace {
+		binding.Spec.ServiceInstanceNamespace = instance.Namespace
+	}
+	return binding, nil
+}
+
+func (i *Importer) getPlanAndOffering(planID string) (*smClientTypes.ServicePlan, *smClientTypes.ServiceOffering, error) {
+	if i.plans == nil {
+		i.plans = make(map[string]*smClientTypes.ServicePlan)
+		i.offerings = make(map[string]*smClientTypes.ServiceOffering)
+	}
+
+	plan, ok := i.plans[planID]
+	if !ok {
+		plans, err := i.SMClient.ListPlans(&sm.Parameters{
+			FieldQuery: []string{fmt.Sprintf("id eq '%s'", planID)},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(plans.ServicePlans) != 1 {
+			return nil, nil, fmt.Errorf("couldn't find the service plan '%s'", planID)
+		}
+		plan = &plans.ServicePlans[0]
+		i.plans[planID] = plan
+	}
+
+	offering, ok := i.offerings[plan.ServiceOfferingID]
+	if !ok {
+		offerings, err := i.SMClient.ListOfferings(&sm.Parameters{
+			FieldQuery: []string{fmt.Sprintf("id eq '%s'", plan.ServiceOfferingID)},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(offerings.ServiceOfferings) != 1 {
+			return nil, nil, fmt.Errorf("couldn't find the service offering '%s'", plan.ServiceOfferingID)
+		}
+		offering = &offerings.ServiceOfferings[0]
+		i.offerings[plan.ServiceOfferingID] = offering
+	}
+	return plan, offering, nil
+}
+
+// getParameters returns the parameters of the resource if the service supports reading them, nil otherwise
+func (i *Importer) getParameters(url, id string) *runtime.RawExtension {
+	response, err := i.SMClient.Call(http.MethodGet, fmt.Sprintf("%s/%s/parameters", url, id), nil, nil)
+	if err != nil {
+		return nil
+	}
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		return nil
+	}
+
+	var parameters map[string]interface{}
+	if err := httputil.UnmarshalResponse(response, &parameters); err != nil || len(parameters) == 0 {
+		return nil
+	}
+	raw, err := json.Marshal(parameters)
+	if err != nil {
+		return nil
+	}
+	return &runtime.RawExtension{Raw: raw}
+}
+
+func labelValue(labels smClientTypes.Labels, key string) string {
+	if len(labels[key]) > 0 {
+		return labels[key][0]
+	}
+	return ""
+}
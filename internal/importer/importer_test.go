@@ -0,0 +1,120 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	"github.com/SAP/sap-btp-service-operator/client/sm"
+	"github.com/SAP/sap-btp-service-operator/client/sm/smfakes"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Importer", func() {
+	const clusterID = "cluster-id"
+
+	var (
+		ctx      context.Context
+		smClient *smfakes.FakeClient
+		imp      *Importer
+	)
+
+	labels := func(namespace, name string) smClientTypes.Labels {
+		return smClientTypes.Labels{
+			common.NamespaceLabel: []string{namespace},
+			common.K8sNameLabel:   []string{name},
+			common.ClusterIDLabel: []string{clusterID},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		smClient = &smfakes.FakeClient{}
+		smClient.ListInstancesReturns(&smClientTypes.ServiceInstances{ServiceInstances: []smClientTypes.ServiceInstance{
+			{ID: "instance-id", Name: "my-instance", ServicePlanID: "plan-id", Labels: labels("ns1", "instance")},
+			{ID: "unlabeled-id", Name: "unlabeled", ServicePlanID: "plan-id"},
+		}}, nil)
+		smClient.ListBindingsReturns(&smClientTypes.ServiceBindings{ServiceBindings: []smClientTypes.ServiceBinding{
+			{ID: "binding-id", Name: "my-binding", ServiceInstanceID: "instance-id", Labels: labels("ns2", "binding")},
+			{ID: "other-binding-id", Name: "other-binding", ServiceInstanceID: "other-instance-id", Labels: labels("ns1", "other-binding")},
+		}}, nil)
+		smClient.ListPlansReturns(&smClientTypes.ServicePlans{ServicePlans: []smClientTypes.ServicePlan{{ID: "plan-id", CatalogName: "small", ServiceOfferingID: "offering-id"}}}, nil)
+		smClient.ListOfferingsReturns(&smClientTypes.ServiceOfferings{ServiceOfferings: []smClientTypes.ServiceOffering{{ID: "offering-id", CatalogName: "xsuaa"}}}, nil)
+		smClient.CallCalls(func(method string, path string, body io.Reader, q *sm.Parameters) (*http.Response, error) {
+			if path == smClientTypes.ServiceInstancesURL+"/instance-id/parameters" {
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"key":"value"}`))}, nil
+			}
+			return &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(strings.NewReader(`{"description":"not retrievable"}`))}, nil
+		})
+
+		imp = &Importer{SMClient: smClient, ClusterID: clusterID}
+	})
+
+	Describe("Build", func() {
+		It("should build the custom resources of the cluster", func() {
+			objects, err := imp.Build(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objects).To(HaveLen(2))
+
+			Expect(smClient.ListInstancesArgsForCall(0).LabelQuery).To(ConsistOf(fmt.Sprintf("%s eq '%s'", common.ClusterIDLabel, clusterID)))
+
+			instance := objects[0].(*v1.ServiceInstance)
+			Expect(instance.Name).To(Equal("instance"))
+			Expect(instance.Namespace).To(Equal("ns1"))
+			Expect(instance.Kind).To(Equal("ServiceInstance"))
+			Expect(instance.Spec.ExternalName).To(Equal("my-instance"))
+			Expect(instance.Spec.ServiceOfferingName).To(Equal("xsuaa"))
+			Expect(instance.Spec.ServicePlanName).To(Equal("small"))
+			Expect(instance.Spec.ServicePlanID).To(Equal("plan-id"))
+			Expect(string(instance.Spec.Parameters.Raw)).To(Equal(`{"key":"value"}`))
+
+			binding := objects[1].(*v1.ServiceBinding)
+			Expect(binding.Name).To(Equal("binding"))
+			Expect(binding.Namespace).To(Equal("ns2"))
+			Expect(binding.Spec.ExternalName).To(Equal("my-binding"))
+			Expect(binding.Spec.ServiceInstanceName).To(Equal("instance"))
+			Expect(binding.Spec.ServiceInstanceNamespace).To(Equal("ns1"))
+			Expect(binding.Spec.Parameters).To(BeNil())
+		})
+
+		It("should filter by namespace", func() {
+			imp.Namespace = "ns1"
+			_, err := imp.Build(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(smClient.ListBindingsArgsForCall(0).LabelQuery).To(ContainElement(fmt.Sprintf("%s eq 'ns1'", common.NamespaceLabel)))
+		})
+
+		It("should fail when the plan is not found", func() {
+			smClient.ListPlansReturns(&smClientTypes.ServicePlans{}, nil)
+			_, err := imp.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("plan-id")))
+		})
+	})
+
+	Describe("Apply", func() {
+		It("should create the resources and skip existing ones", func() {
+			existing := &v1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "ns1"}}
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+			objects, err := imp.Build(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			result, err := Apply(ctx, k8sClient, objects)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Skipped).To(HaveLen(1))
+			Expect(result.Created).To(HaveLen(1))
+
+			binding := &v1.ServiceBinding{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "binding", Namespace: "ns2"}, binding)).To(Succeed())
+			Expect(binding.Spec.ExternalName).To(Equal("my-binding"))
+		})
+	})
+})
@@ -0,0 +1,197 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	"github.com/SAP/sap-btp-service-operator/client/sm"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	"github.com/SAP/sap-btp-service-operator/internal/config"
+	"github.com/SAP/sap-btp-service-operator/internal/utils"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ClusterIDMigrationConfigMapName = "sap-btp-operator-clusterid-migration"
+
+	MigrationFromKey               = "fromClusterID"
+	MigrationToKey                 = "toClusterID"
+	MigrationPhaseKey              = "phase"
+	MigrationInstancesKey          = "migratedInstances"
+	MigrationBindingsKey           = "migratedBindings"
+	MigrationMessageKey            = "message"
+	MigrationLastTransitionTimeKey = "lastTransitionTime"
+
+	MigrationInProgress = "InProgress"
+	MigrationSucceeded  = "Succeeded"
+	MigrationFailed     = "Failed"
+)
+
+// ClusterIDMigrator moves the SM instances and bindings owned by the initial cluster ID to the configured cluster ID.
+// It relabels the resources in SM, reports its progress in a config map in the release namespace and
+// finally updates the cluster ID secret so the next restart of the operator runs with the new cluster ID only.
+type ClusterIDMigrator struct {
+	Client       client.Client
+	APIReader    client.Reader
+	Log          logr.Logger
+	Config       config.Config
+	GetSMClients func(ctx context.Context, k8sClient client.Reader) ([]sm.Client, error)
+}
+
+// Start runs the migration until it succeeds or the context is cancelled
+func (m *ClusterIDMigrator) Start(ctx context.Context) error {
+	ctx = context.WithValue(ctx, utils.LogKey{}, m.Log)
+	delay := m.Config.RetryBaseDelay
+	for {
+		err := m.Migrate(ctx)
+		if err == nil {
+			return nil
+		}
+		m.Log.Error(err, fmt.Sprintf("cluster ID migration failed, retrying in %s", delay))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > m.Config.RetryMaxDelay {
+			delay = m.Config.RetryMaxDelay
+		}
+	}
+}
+
+// Migrate relabels all the SM resources of the initial cluster ID, it is safe to call it again after a failure
+func (m *ClusterIDMigrator) Migrate(ctx context.Context) error {
+	from, to := m.Config.InitialClusterID, m.Config.ClusterID
+	m.Log.Info(fmt.Sprintf("migrating cluster ID from %s to %s", from, to))
+
+	status := map[string]string{
+		MigrationFromKey:      from,
+		MigrationToKey:        to,
+		MigrationPhaseKey:     MigrationInProgress,
+		MigrationInstancesKey: "0",
+		MigrationBindingsKey:  "0",
+	}
+	if err := m.updateStatus(ctx, status); err != nil {
+		return err
+	}
+
+	instances, bindings, err := m.relabelResources(ctx, from, to)
+	status[MigrationInstancesKey] = strconv.Itoa(instances)
+	status[MigrationBindingsKey] = strconv.Itoa(bindings)
+	if err != nil {
+		status[MigrationPhaseKey] = MigrationFailed
+		status[MigrationMessageKey] = err.Error()
+		return errors.Join(err, m.updateStatus(ctx, status))
+	}
+
+	if err := m.updateClusterIDSecret(ctx, to); err != nil {
+		status[MigrationPhaseKey] = MigrationFailed
+		status[MigrationMessageKey] = fmt.Sprintf("failed to update secret %s: %s", utils.ClusterIDSecretName, err.Error())
+		return errors.Join(err, m.updateStatus(ctx, status))
+	}
+
+	m.Log.Info(fmt.Sprintf("cluster ID migration finished, migrated %d instances and %d bindings", instances, bindings))
+	status[MigrationPhaseKey] = MigrationSucceeded
+	status[MigrationMessageKey] = fmt.Sprintf("resources of cluster %s were migrated to cluster %s", from, to)
+	return m.updateStatus(ctx, status)
+}
+
+func (m *ClusterIDMigrator) relabelResources(ctx context.Context, from, to string) (int, int, error) {
+	smClients, err := m.GetSMClients(ctx, m.Client)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var errs []error
+	instancesCount, bindingsCount := 0, 0
+	params := &sm.Parameters{
+		LabelQuery: []string{fmt.Sprintf("%s eq '%s'", common.ClusterIDLabel, from)},
+	}
+	for _, smClient := range smClients {
+		instances, err := smClient.ListInstances(params)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, instance := range instances.ServiceInstances {
+			err := relabel(from, to, func(changes []*smClientTypes.LabelChange) error {
+				_, err := smClient.UpdateInstanceLabels(instance.ID, changes)
+				return err
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to migrate instance %s: %w", instance.ID, err))
+				continue
+			}
+			instancesCount++
+		}
+
+		bindings, err := smClient.ListBindings(params)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, binding := range bindings.ServiceBindings {
+			err := relabel(from, to, func(changes []*smClientTypes.LabelChange) error {
+				_, err := smClient.UpdateBindingLabels(binding.ID, changes)
+				return err
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to migrate binding %s: %w", binding.ID, err))
+				continue
+			}
+			bindingsCount++
+		}
+	}
+	return instancesCount, bindingsCount, errors.Join(errs...)
+}
+
+// relabel replaces the cluster ID label value. SM does not support remove and add of the same label in one request,
+// the new value is added first so a failed removal leaves the resource discoverable for the next attempt
+func relabel(from, to string, update func([]*smClientTypes.LabelChange) error) error {
+	if err := update([]*smClientTypes.LabelChange{{Key: common.ClusterIDLabel, Operation: smClientTypes.AddLabelOperation, Values: []string{to}}}); err != nil {
+		return err
+	}
+	return update([]*smClientTypes.LabelChange{{Key: common.ClusterIDLabel, Operation: smClientTypes.RemoveLabelOperation, Values: []string{from}}})
+}
+
+func (m *ClusterIDMigrator) updateClusterIDSecret(ctx context.Context, clusterID string) error {
+	secret := &corev1.Secret{}
+	if err := m.APIReader.Get(ctx, types.NamespacedName{Name: utils.ClusterIDSecretName, Namespace: m.Config.ReleaseNamespace}, secret); err != nil {
+		return err
+	}
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[utils.InitialClusterIDKey] = []byte(clusterID)
+	return m.Client.Update(ctx, secret)
+}
+
+func (m *ClusterIDMigrator) updateStatus(ctx context.Context, status map[string]string) error {
+	status[MigrationLastTransitionTimeKey] = time.Now().UTC().Format(time.RFC3339)
+
+	configMap := &corev1.ConfigMap{}
+	err := m.APIReader.Get(ctx, types.NamespacedName{Name: ClusterIDMigrationConfigMapName, Namespace: m.Config.ReleaseNamespace}, configMap)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		configMap.Name = ClusterIDMigrationConfigMapName
+		configMap.Namespace = m.Config.ReleaseNamespace
+		configMap.Labels = map[string]string{common.ManagedByBTPOperatorLabel: "true"}
+		configMap.Data = status
+		return m.Client.Create(ctx, configMap)
+	}
+
+	configMap.Data = status
+	return m.Client.Update(ctx, configMap)
+}
@@ -0,0 +1,130 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	"github.com/SAP/sap-btp-service-operator/client/sm"
+	"github.com/SAP/sap-btp-service-operator/client/sm/smfakes"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	"github.com/SAP/sap-btp-service-operator/internal/config"
+	"github.com/SAP/sap-btp-service-operator/internal/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Cluster ID migration", func() {
+	const (
+		releaseNamespace = "release-namespace"
+		oldClusterID     = "old-cluster-id"
+		newClusterID     = "new-cluster-id"
+	)
+
+	var (
+		ctx       context.Context
+		k8sClient client.Client
+		smClient  *smfakes.FakeClient
+		migrator  *ClusterIDMigrator
+	)
+
+	getStatus := func() map[string]string {
+		configMap := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ClusterIDMigrationConfigMapName, Namespace: releaseNamespace}, configMap)).To(Succeed())
+		return configMap.Data
+	}
+
+	getInitialClusterID := func() string {
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: utils.ClusterIDSecretName, Namespace: releaseNamespace}, secret)).To(Succeed())
+		return string(secret.Data[utils.InitialClusterIDKey])
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		k8sClient = fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: utils.ClusterIDSecretName, Namespace: releaseNamespace},
+			Data:       map[string][]byte{utils.InitialClusterIDKey: []byte(oldClusterID)},
+		}).Build()
+
+		smClient = &smfakes.FakeClient{}
+		smClient.ListInstancesReturns(&smClientTypes.ServiceInstances{ServiceInstances: []smClientTypes.ServiceInstance{{ID: "instance-1"}, {ID: "instance-2"}}}, nil)
+		smClient.ListBindingsReturns(&smClientTypes.ServiceBindings{ServiceBindings: []smClientTypes.ServiceBinding{{ID: "binding-1"}}}, nil)
+
+		migrator = &ClusterIDMigrator{
+			Client:    k8sClient,
+			APIReader: k8sClient,
+			Log:       ctrl.Log.WithName("migration"),
+			Config: config.Config{
+				ReleaseNamespace: releaseNamespace,
+				ClusterID:        newClusterID,
+				InitialClusterID: oldClusterID,
+			},
+			GetSMClients: func(ctx context.Context, k8sClient client.Reader) ([]sm.Client, error) {
+				return []sm.Client{smClient}, nil
+			},
+		}
+	})
+
+	It("should relabel the resources of the initial cluster and update the cluster secret", func() {
+		Expect(migrator.Migrate(ctx)).To(Succeed())
+
+		Expect(smClient.ListInstancesArgsForCall(0).LabelQuery).To(ConsistOf(fmt.Sprintf("%s eq '%s'", common.ClusterIDLabel, oldClusterID)))
+		Expect(smClient.ListBindingsArgsForCall(0).LabelQuery).To(ConsistOf(fmt.Sprintf("%s eq '%s'", common.ClusterIDLabel, oldClusterID)))
+
+		Expect(smClient.UpdateInstanceLabelsCallCount()).To(Equal(4))
+		id, changes := smClient.UpdateInstanceLabelsArgsForCall(0)
+		Expect(id).To(Equal("instance-1"))
+		Expect(changes).To(ConsistOf(&smClientTypes.LabelChange{Key: common.ClusterIDLabel, Operation: smClientTypes.AddLabelOperation, Values: []string{newClusterID}}))
+		id, changes = smClient.UpdateInstanceLabelsArgsForCall(1)
+		Expect(id).To(Equal("instance-1"))
+		Expect(changes).To(ConsistOf(&smClientTypes.LabelChange{Key: common.ClusterIDLabel, Operation: smClientTypes.RemoveLabelOperation, Values: []string{oldClusterID}}))
+
+		Expect(smClient.UpdateBindingLabelsCallCount()).To(Equal(2))
+		id, _ = smClient.UpdateBindingLabelsArgsForCall(0)
+		Expect(id).To(Equal("binding-1"))
+
+		status := getStatus()
+		Expect(status[MigrationPhaseKey]).To(Equal(MigrationSucceeded))
+		Expect(status[MigrationFromKey]).To(Equal(oldClusterID))
+		Expect(status[MigrationToKey]).To(Equal(newClusterID))
+		Expect(status[MigrationInstancesKey]).To(Equal("2"))
+		Expect(status[MigrationBindingsKey]).To(Equal("1"))
+		Expect(getInitialClusterID()).To(Equal(newClusterID))
+	})
+
+	When("relabeling fails", func() {
+		BeforeEach(func() {
+			smClient.UpdateInstanceLabelsReturnsOnCall(0, nil, fmt.Errorf("sm is down"))
+		})
+
+		It("should report the failure and keep the initial cluster ID", func() {
+			err := migrator.Migrate(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("sm is down"))
+
+			status := getStatus()
+			Expect(status[MigrationPhaseKey]).To(Equal(MigrationFailed))
+			Expect(status[MigrationMessageKey]).To(ContainSubstring("instance-1"))
+			Expect(status[MigrationInstancesKey]).To(Equal("1"))
+			Expect(getInitialClusterID()).To(Equal(oldClusterID))
+		})
+	})
+
+	When("the cluster secret is missing", func() {
+		BeforeEach(func() {
+			Expect(k8sClient.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: utils.ClusterIDSecretName, Namespace: releaseNamespace}})).To(Succeed())
+		})
+
+		It("should fail the migration", func() {
+			Expect(migrator.Migrate(ctx)).ToNot(Succeed())
+			Expect(getStatus()[MigrationPhaseKey]).To(Equal(MigrationFailed))
+		})
+	})
+})
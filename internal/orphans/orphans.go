@@ -0,0 +1,116 @@
+package orphans
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	"github.com/SAP/sap-btp-service-operator/client/sm"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	"github.com/SAP/sap-btp-service-operator/internal/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	InstanceKind = "ServiceInstance"
+	BindingKind  = "ServiceBinding"
+
+	DeletionPending = "pending"
+)
+
+// Orphan is a Service Manager resource labeled with the cluster ID that has no matching custom resource in the cluster
+type Orphan struct {
+	Kind      string `json:"kind"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	K8sName   string `json:"k8sName,omitempty"`
+	FirstSeen string `json:"firstSeen,omitempty"`
+	// Deletion is DeletionPending while Service Manager deletes the orphan asynchronously
+	Deletion string `json:"deletion,omitempty"`
+	// DeletionOperation is the URL of the asynchronous deletion operation
+	DeletionOperation string `json:"deletionOperation,omitempty"`
+}
+
+// Find returns the instances and bindings of the given cluster ID in Service Manager that have no matching
+// ServiceInstance/ServiceBinding in the cluster. A resource is matched by its ID in the custom resource status
+// or, while the custom resource is still being created, by its namespace and name labels.
+// When namespaces is not empty only resources that belong to one of these namespaces are considered.
+func Find(ctx context.Context, k8sClient client.Reader, smClient sm.Client, clusterID string, namespaces []string) ([]Orphan, error) {
+	params := &sm.Parameters{
+		LabelQuery: []string{fmt.Sprintf("%s eq '%s'", common.ClusterIDLabel, clusterID)},
+	}
+
+	instanceList := &v1.ServiceInstanceList{}
+	if err := k8sClient.List(ctx, instanceList); err != nil {
+		return nil, err
+	}
+	knownInstances := make(map[string]bool)
+	for _, instance := range instanceList.Items {
+		knownInstances[instance.Status.InstanceID] = true
+		knownInstances[objectKey(instance.Namespace, instance.Name)] = true
+	}
+
+	bindingList := &v1.ServiceBindingList{}
+	if err := k8sClient.List(ctx, bindingList); err != nil {
+		return nil, err
+	}
+	knownBindings := make(map[string]bool)
+	for _, binding := range bindingList.Items {
+		knownBindings[binding.Status.BindingID] = true
+		knownBindings[objectKey(binding.Namespace, binding.Name)] = true
+	}
+
+	smInstances, err := smClient.ListInstances(params)
+	if err != nil {
+		return nil, err
+	}
+	var orphans []Orphan
+	for _, instance := range smInstances.ServiceInstances {
+		if orphan := toOrphan(InstanceKind, instance.ID, instance.Name, instance.Labels, knownInstances, namespaces); orphan != nil {
+			orphans = append(orphans, *orphan)
+		}
+	}
+
+	smBindings, err := smClient.ListBindings(params)
+	if err != nil {
+		return nil, err
+	}
+	for _, binding := range smBindings.ServiceBindings {
+		if orphan := toOrphan(BindingKind, binding.ID, binding.Name, binding.Labels, knownBindings, namespaces); orphan != nil {
+			orphans = append(orphans, *orphan)
+		}
+	}
+
+	return orphans, nil
+}
+
+func toOrphan(kind, id, name string, labels smClientTypes.Labels, known map[string]bool, namespaces []string) *Orphan {
+	namespace := firstLabelValue(labels, common.NamespaceLabel)
+	k8sName := firstLabelValue(labels, common.K8sNameLabel)
+	if len(namespaces) > 0 && !utils.SliceContains(namespaces, namespace) {
+		return nil
+	}
+	if known[id] || (len(k8sName) > 0 && known[objectKey(namespace, k8sName)]) {
+		return nil
+	}
+	return &Orphan{
+		Kind:      kind,
+		ID:        id,
+		Name:      name,
+		Namespace: namespace,
+		K8sName:   k8sName,
+	}
+}
+
+func objectKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func firstLabelValue(labels smClientTypes.Labels, key string) string {
+	if len(labels[key]) > 0 {
+		return labels[key][0]
+	}
+	return ""
+}
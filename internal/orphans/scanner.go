@@ -0,0 +1,281 @@
+package orphans
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	"github.com/SAP/sap-btp-service-operator/client/sm"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	"github.com/SAP/sap-btp-service-operator/internal/config"
+	"github.com/SAP/sap-btp-service-operator/internal/utils"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	OrphansConfigMapName = "sap-btp-operator-orphans"
+
+	OrphansKey      = "orphans"
+	LastScanTimeKey = "lastScanTime"
+)
+
+var (
+	orphanedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sap_btp_operator_orphaned_resources",
+		Help: "Number of Service Manager resources of this cluster without a matching custom resource",
+	}, []string{"kind"})
+
+	pendingOrphans = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sap_btp_operator_orphaned_resources_pending_deletion",
+		Help: "Number of orphaned Service Manager resources whose asynchronous deletion is in progress",
+	}, []string{"kind"})
+
+	deletedOrphans = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sap_btp_operator_orphaned_resources_deleted_total",
+		Help: "Number of orphaned Service Manager resources deleted by the operator",
+	}, []string{"kind"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(orphanedResources, pendingOrphans, deletedOrphans)
+}
+
+// Scanner periodically looks for orphaned Service Manager resources of the cluster.
+// The orphans are reported in a config map in the release namespace and as metrics,
+// and when cleanup is enabled they are deleted from Service Manager once the grace period is over.
+type Scanner struct {
+	Client       client.Client
+	APIReader    client.Reader
+	Log          logr.Logger
+	Config       config.Config
+	GetSMClients func(ctx context.Context, k8sClient client.Reader) ([]sm.Client, error)
+}
+
+// Start scans for orphans every scan interval until the context is cancelled
+func (s *Scanner) Start(ctx context.Context) error {
+	ctx = context.WithValue(ctx, utils.LogKey{}, s.Log)
+	ticker := time.NewTicker(s.Config.OrphanScanInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.Scan(ctx); err != nil {
+			s.Log.Error(err, "orphans scan failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan reports the current orphans and deletes the ones whose grace period is over if cleanup is enabled
+func (s *Scanner) Scan(ctx context.Context) error {
+	smClients, err := s.GetSMClients(ctx, s.Client)
+	if err != nil {
+		return err
+	}
+
+	configMap, previous, err := s.getReport(ctx)
+	if err != nil {
+		return err
+	}
+
+	var namespaces []string
+	if !s.Config.AllowClusterAccess {
+		namespaces = append(namespaces, s.Config.AllowedNamespaces...)
+		namespaces = append(namespaces, s.Config.ReleaseNamespace)
+	}
+
+	now := time.Now().UTC()
+	var errs []error
+	var report []Orphan
+	counts := map[string]int{InstanceKind: 0, BindingKind: 0}
+	pendingCounts := map[string]int{InstanceKind: 0, BindingKind: 0}
+	found := make(map[string]bool)
+	findFailed := false
+	for _, smClient := range smClients {
+		orphans, err := Find(ctx, s.Client, smClient, s.Config.ClusterID, namespaces)
+		if err != nil {
+			errs = append(errs, err)
+			findFailed = true
+			continue
+		}
+
+		// bindings are found after the instances, deleting them first allows deleting their instance in the same scan
+		for i := len(orphans) - 1; i >= 0; i-- {
+			orphan := orphans[i]
+			found[orphan.ID] = true
+			orphan.FirstSeen = now.Format(time.RFC3339)
+			if previousOrphan, ok := previous[orphan.ID]; ok {
+				orphan.FirstSeen = previousOrphan.FirstSeen
+				orphan.DeletionOperation = previousOrphan.DeletionOperation
+			}
+
+			deletionInProgress := len(orphan.DeletionOperation) > 0 && s.deletionInProgress(smClient, orphan)
+			if deletionInProgress {
+				orphan.Deletion = DeletionPending
+			} else {
+				orphan.DeletionOperation = ""
+			}
+
+			if !deletionInProgress && s.Config.EnableOrphanCleanup && s.gracePeriodOver(orphan, now) {
+				operationURL, err := s.delete(smClient, orphan)
+				switch {
+				case err != nil:
+					errs = append(errs, fmt.Errorf("failed to delete orphaned %s %s: %w", orphan.Kind, orphan.ID, err))
+				case len(operationURL) == 0:
+					continue
+				default:
+					// the orphan is counted as deleted once it is no longer found
+					orphan.Deletion = DeletionPending
+					orphan.DeletionOperation = operationURL
+				}
+			}
+
+			if orphan.Deletion == DeletionPending {
+				pendingCounts[orphan.Kind]++
+			} else {
+				counts[orphan.Kind]++
+			}
+			report = append(report, orphan)
+		}
+	}
+
+	// an orphan whose deletion was pending and is no longer found was deleted, unless it was not found because the scan failed
+	for id, orphan := range previous {
+		if len(orphan.DeletionOperation) == 0 || found[id] {
+			continue
+		}
+		if findFailed {
+			pendingCounts[orphan.Kind]++
+			report = append(report, orphan)
+			continue
+		}
+		s.Log.Info(fmt.Sprintf("orphaned %s %s (%s) of namespace %s was deleted", orphan.Kind, orphan.ID, orphan.Name, orphan.Namespace))
+		deletedOrphans.WithLabelValues(orphan.Kind).Inc()
+	}
+
+	for kind, count := range counts {
+		orphanedResources.WithLabelValues(kind).Set(float64(count))
+	}
+	for kind, count := range pendingCounts {
+		pendingOrphans.WithLabelValues(kind).Set(float64(count))
+	}
+	if len(report) > 0 {
+		s.Log.Info(fmt.Sprintf("found %d orphaned instances and %d orphaned bindings of cluster %s, the deletion of %d instances and %d bindings is pending",
+			counts[InstanceKind], counts[BindingKind], s.Config.ClusterID, pendingCounts[InstanceKind], pendingCounts[BindingKind]))
+	}
+
+	if err := s.updateReport(ctx, configMap, report, now); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Scanner) gracePeriodOver(orphan Orphan, now time.Time) bool {
+	firstSeen, err := time.Parse(time.RFC3339, orphan.FirstSeen)
+	if err != nil {
+		return false
+	}
+	return now.Sub(firstSeen) >= s.Config.OrphanGracePeriod
+}
+
+// delete deletes the orphan from Service Manager, and returns the URL of the operation if it is deleted asynchronously
+func (s *Scanner) delete(smClient sm.Client, orphan Orphan) (string, error) {
+	s.Log.Info(fmt.Sprintf("deleting orphaned %s %s (%s) of namespace %s", orphan.Kind, orphan.ID, orphan.Name, orphan.Namespace))
+	var operationURL string
+	var err error
+	if orphan.Kind == InstanceKind {
+		operationURL, err = smClient.Deprovision(orphan.ID, nil, "")
+	} else {
+		operationURL, err = smClient.Unbind(orphan.ID, nil, "")
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(operationURL) == 0 {
+		deletedOrphans.WithLabelValues(orphan.Kind).Inc()
+	}
+	return operationURL, nil
+}
+
+// deletionInProgress returns true if the asynchronous deletion of the orphan is still in progress,
+// false if it failed or its operation is gone, in which case the orphan can be deleted again
+func (s *Scanner) deletionInProgress(smClient sm.Client, orphan Orphan) bool {
+	operation, err := smClient.Status(orphan.DeletionOperation, nil)
+	if err != nil {
+		var smError *sm.ServiceManagerError
+		if errors.As(err, &smError) && smError.StatusCode == http.StatusNotFound {
+			return false
+		}
+		s.Log.Error(err, fmt.Sprintf("failed to get the deletion operation of orphaned %s %s", orphan.Kind, orphan.ID))
+		return true
+	}
+	switch operation.State {
+	case smClientTypes.PENDING, smClientTypes.INPROGRESS:
+		return true
+	case smClientTypes.FAILED:
+		s.Log.Info(fmt.Sprintf("deletion of orphaned %s %s failed: %s", orphan.Kind, orphan.ID, operation.Description))
+	}
+	return false
+}
+
+// getReport returns the report config map (nil if it doesn't exist yet) with the reported orphans by ID
+func (s *Scanner) getReport(ctx context.Context) (*corev1.ConfigMap, map[string]Orphan, error) {
+	reported := make(map[string]Orphan)
+	configMap := &corev1.ConfigMap{}
+	err := s.APIReader.Get(ctx, types.NamespacedName{Name: OrphansConfigMapName, Namespace: s.Config.ReleaseNamespace}, configMap)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, reported, nil
+		}
+		return nil, nil, err
+	}
+
+	var orphans []Orphan
+	if len(configMap.Data[OrphansKey]) > 0 {
+		if err := json.Unmarshal([]byte(configMap.Data[OrphansKey]), &orphans); err != nil {
+			s.Log.Error(err, "failed to parse orphans report, resetting it")
+		}
+	}
+	for _, orphan := range orphans {
+		reported[orphan.ID] = orphan
+	}
+	return configMap, reported, nil
+}
+
+func (s *Scanner) updateReport(ctx context.Context, configMap *corev1.ConfigMap, orphans []Orphan, now time.Time) error {
+	if orphans == nil {
+		orphans = []Orphan{}
+	}
+	orphansJSON, err := json.MarshalIndent(orphans, "", "  ")
+	if err != nil {
+		return err
+	}
+	data := map[string]string{
+		OrphansKey:      string(orphansJSON),
+		LastScanTimeKey: now.Format(time.RFC3339),
+	}
+
+	if configMap == nil {
+		configMap = &corev1.ConfigMap{}
+		configMap.Name = OrphansConfigMapName
+		configMap.Namespace = s.Config.ReleaseNamespace
+		configMap.Labels = map[string]string{common.ManagedByBTPOperatorLabel: "true"}
+		configMap.Data = data
+		return s.Client.Create(ctx, configMap)
+	}
+	configMap.Data = data
+	return s.Client.Update(ctx, configMap)
+}
@@ -0,0 +1,241 @@
+package orphans
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	"github.com/SAP/sap-btp-service-operator/client/sm"
+	"github.com/SAP/sap-btp-service-operator/client/sm/smfakes"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	"github.com/SAP/sap-btp-service-operator/internal/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Orphans", func() {
+	const (
+		clusterID        = "cluster-id"
+		releaseNamespace = "release-namespace"
+	)
+
+	var (
+		ctx       context.Context
+		k8sClient client.Client
+		smClient  *smfakes.FakeClient
+	)
+
+	labels := func(namespace, name string) smClientTypes.Labels {
+		return smClientTypes.Labels{
+			common.NamespaceLabel: []string{namespace},
+			common.K8sNameLabel:   []string{name},
+			common.ClusterIDLabel: []string{clusterID},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		instance := &v1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "ns1"}}
+		instance.Status.InstanceID = "instance-id"
+		creatingInstance := &v1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Name: "creating-instance", Namespace: "ns1"}}
+		binding := &v1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "binding", Namespace: "ns1"}}
+		binding.Status.BindingID = "binding-id"
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(instance, creatingInstance, binding).Build()
+
+		smClient = &smfakes.FakeClient{}
+		smClient.ListInstancesReturns(&smClientTypes.ServiceInstances{ServiceInstances: []smClientTypes.ServiceInstance{
+			{ID: "instance-id", Name: "instance", Labels: labels("ns1", "instance")},
+			{ID: "creating-instance-id", Name: "creating-instance", Labels: labels("ns1", "creating-instance")},
+			{ID: "orphan-instance-id", Name: "orphan-instance", Labels: labels("ns2", "orphan-instance")},
+		}}, nil)
+		smClient.ListBindingsReturns(&smClientTypes.ServiceBindings{ServiceBindings: []smClientTypes.ServiceBinding{
+			{ID: "binding-id", Name: "binding", Labels: labels("ns1", "binding")},
+			{ID: "orphan-binding-id", Name: "orphan-binding", Labels: labels("ns1", "orphan-binding")},
+		}}, nil)
+	})
+
+	Describe("Find", func() {
+		It("should return SM resources without a matching custom resource", func() {
+			orphans, err := Find(ctx, k8sClient, smClient, clusterID, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(orphans).To(ConsistOf(
+				Orphan{Kind: InstanceKind, ID: "orphan-instance-id", Name: "orphan-instance", Namespace: "ns2", K8sName: "orphan-instance"},
+				Orphan{Kind: BindingKind, ID: "orphan-binding-id", Name: "orphan-binding", Namespace: "ns1", K8sName: "orphan-binding"},
+			))
+			Expect(smClient.ListInstancesArgsForCall(0).LabelQuery).To(ConsistOf(fmt.Sprintf("%s eq '%s'", common.ClusterIDLabel, clusterID)))
+		})
+
+		It("should ignore resources of other namespaces", func() {
+			orphans, err := Find(ctx, k8sClient, smClient, clusterID, []string{"ns1"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(orphans).To(HaveLen(1))
+			Expect(orphans[0].ID).To(Equal("orphan-binding-id"))
+		})
+
+		It("should fail when SM fails", func() {
+			smClient.ListBindingsReturns(nil, fmt.Errorf("sm error"))
+			_, err := Find(ctx, k8sClient, smClient, clusterID, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Scanner", func() {
+		var scanner *Scanner
+
+		getReport := func() []Orphan {
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: OrphansConfigMapName, Namespace: releaseNamespace}, configMap)).To(Succeed())
+			Expect(configMap.Data[LastScanTimeKey]).ToNot(BeEmpty())
+			var orphans []Orphan
+			Expect(json.Unmarshal([]byte(configMap.Data[OrphansKey]), &orphans)).To(Succeed())
+			return orphans
+		}
+
+		BeforeEach(func() {
+			scanner = &Scanner{
+				Client:    k8sClient,
+				APIReader: k8sClient,
+				Log:       ctrl.Log.WithName("orphans"),
+				Config: config.Config{
+					ClusterID:          clusterID,
+					ReleaseNamespace:   releaseNamespace,
+					AllowClusterAccess: true,
+					OrphanGracePeriod:  time.Hour,
+				},
+				GetSMClients: func(ctx context.Context, k8sClient client.Reader) ([]sm.Client, error) {
+					return []sm.Client{smClient}, nil
+				},
+			}
+		})
+
+		It("should report the orphans and keep their first seen time", func() {
+			Expect(scanner.Scan(ctx)).To(Succeed())
+			orphans := getReport()
+			Expect(orphans).To(HaveLen(2))
+			Expect(orphans[0].FirstSeen).ToNot(BeEmpty())
+			firstSeen := orphans[0].FirstSeen
+
+			time.Sleep(time.Second)
+			Expect(scanner.Scan(ctx)).To(Succeed())
+			orphans = getReport()
+			Expect(orphans).To(HaveLen(2))
+			Expect(orphans[0].FirstSeen).To(Equal(firstSeen))
+			Expect(smClient.DeprovisionCallCount()).To(BeZero())
+			Expect(smClient.UnbindCallCount()).To(BeZero())
+		})
+
+		When("cleanup is enabled", func() {
+			BeforeEach(func() {
+				scanner.Config.EnableOrphanCleanup = true
+			})
+
+			It("should not delete orphans before the grace period is over", func() {
+				Expect(scanner.Scan(ctx)).To(Succeed())
+				Expect(smClient.DeprovisionCallCount()).To(BeZero())
+				Expect(smClient.UnbindCallCount()).To(BeZero())
+			})
+
+			It("should delete orphans after the grace period", func() {
+				scanner.Config.OrphanGracePeriod = 0
+				deletedBindings := testutil.ToFloat64(deletedOrphans.WithLabelValues(BindingKind))
+				Expect(scanner.Scan(ctx)).To(Succeed())
+				Expect(testutil.ToFloat64(deletedOrphans.WithLabelValues(BindingKind))).To(Equal(deletedBindings + 1))
+
+				Expect(smClient.UnbindCallCount()).To(Equal(1))
+				id, _, _ := smClient.UnbindArgsForCall(0)
+				Expect(id).To(Equal("orphan-binding-id"))
+				Expect(smClient.DeprovisionCallCount()).To(Equal(1))
+				id, _, _ = smClient.DeprovisionArgsForCall(0)
+				Expect(id).To(Equal("orphan-instance-id"))
+				Expect(getReport()).To(BeEmpty())
+			})
+
+			When("SM deletes the orphans asynchronously", func() {
+				const (
+					instanceOperation = "/v1/service_instances/orphan-instance-id/operations/instance-operation"
+					bindingOperation  = "/v1/service_bindings/orphan-binding-id/operations/binding-operation"
+				)
+
+				BeforeEach(func() {
+					scanner.Config.OrphanGracePeriod = 0
+					smClient.DeprovisionReturns(instanceOperation, nil)
+					smClient.UnbindReturns(bindingOperation, nil)
+					smClient.StatusReturns(&smClientTypes.Operation{State: smClientTypes.INPROGRESS}, nil)
+				})
+
+				It("should report the orphans as pending until they are no longer found", func() {
+					deletedInstances := testutil.ToFloat64(deletedOrphans.WithLabelValues(InstanceKind))
+					deletedBindings := testutil.ToFloat64(deletedOrphans.WithLabelValues(BindingKind))
+					Expect(scanner.Scan(ctx)).To(Succeed())
+
+					orphans := getReport()
+					Expect(orphans).To(HaveLen(2))
+					for _, orphan := range orphans {
+						Expect(orphan.Deletion).To(Equal(DeletionPending))
+					}
+					Expect(orphans).To(ContainElement(HaveField("DeletionOperation", bindingOperation)))
+					Expect(orphans).To(ContainElement(HaveField("DeletionOperation", instanceOperation)))
+					Expect(testutil.ToFloat64(pendingOrphans.WithLabelValues(InstanceKind))).To(Equal(float64(1)))
+					Expect(testutil.ToFloat64(orphanedResources.WithLabelValues(InstanceKind))).To(BeZero())
+					Expect(testutil.ToFloat64(deletedOrphans.WithLabelValues(InstanceKind))).To(Equal(deletedInstances))
+
+					By("not deleting them again while the deletion is in progress")
+					Expect(scanner.Scan(ctx)).To(Succeed())
+					Expect(smClient.DeprovisionCallCount()).To(Equal(1))
+					Expect(smClient.UnbindCallCount()).To(Equal(1))
+					Expect(smClient.StatusArgsForCall(0)).To(BeElementOf(instanceOperation, bindingOperation))
+					Expect(getReport()).To(HaveLen(2))
+
+					By("counting them as deleted once they are no longer found")
+					smClient.ListInstancesReturns(&smClientTypes.ServiceInstances{ServiceInstances: []smClientTypes.ServiceInstance{
+						{ID: "instance-id", Name: "instance", Labels: labels("ns1", "instance")},
+					}}, nil)
+					smClient.ListBindingsReturns(&smClientTypes.ServiceBindings{ServiceBindings: []smClientTypes.ServiceBinding{
+						{ID: "binding-id", Name: "binding", Labels: labels("ns1", "binding")},
+					}}, nil)
+					Expect(scanner.Scan(ctx)).To(Succeed())
+					Expect(getReport()).To(BeEmpty())
+					Expect(testutil.ToFloat64(deletedOrphans.WithLabelValues(InstanceKind))).To(Equal(deletedInstances + 1))
+					Expect(testutil.ToFloat64(deletedOrphans.WithLabelValues(BindingKind))).To(Equal(deletedBindings + 1))
+					Expect(testutil.ToFloat64(pendingOrphans.WithLabelValues(InstanceKind))).To(BeZero())
+				})
+
+				It("should keep the pending orphans when the scan fails", func() {
+					Expect(scanner.Scan(ctx)).To(Succeed())
+					smClient.ListInstancesReturns(nil, fmt.Errorf("sm error"))
+					Expect(scanner.Scan(ctx)).ToNot(Succeed())
+					Expect(getReport()).To(HaveLen(2))
+				})
+
+				It("should delete the orphans again when the deletion failed", func() {
+					Expect(scanner.Scan(ctx)).To(Succeed())
+					smClient.StatusReturns(&smClientTypes.Operation{State: smClientTypes.FAILED, Description: "broker error"}, nil)
+					Expect(scanner.Scan(ctx)).To(Succeed())
+					Expect(smClient.DeprovisionCallCount()).To(Equal(2))
+					Expect(smClient.UnbindCallCount()).To(Equal(2))
+				})
+			})
+
+			It("should keep reporting orphans that failed to be deleted", func() {
+				scanner.Config.OrphanGracePeriod = 0
+				smClient.DeprovisionReturns("", fmt.Errorf("sm error"))
+				Expect(scanner.Scan(ctx)).ToNot(Succeed())
+
+				orphans := getReport()
+				Expect(orphans).To(HaveLen(1))
+				Expect(orphans[0].ID).To(Equal("orphan-instance-id"))
+			})
+		})
+	})
+})
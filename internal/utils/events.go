@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// RecordOperationEvent records an event of an operation in the state, the reason of the event is the condition reason of the state
+// and failed operations are recorded as warnings
+func RecordOperationEvent(recorder record.EventRecorder, object common.SAPBTPResource, operationType smClientTypes.OperationCategory, state smClientTypes.OperationState, message string) {
+	eventType := corev1.EventTypeNormal
+	if state == smClientTypes.FAILED {
+		eventType = corev1.EventTypeWarning
+	}
+	recorder.Event(object, eventType, GetConditionReason(operationType, state), message)
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+)
+
+var _ = Describe("Events", func() {
+	var recorder *record.FakeRecorder
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(1)
+	})
+
+	It("should record the operation with the condition reason of its state", func() {
+		RecordOperationEvent(recorder, &v1.ServiceInstance{}, smClientTypes.UPDATE, smClientTypes.INPROGRESS, "updating instance")
+		Expect(<-recorder.Events).To(Equal("Normal UpdateInProgress updating instance"))
+	})
+
+	It("should record a failed operation as warning", func() {
+		RecordOperationEvent(recorder, &v1.ServiceBinding{}, smClientTypes.DELETE, smClientTypes.FAILED, "unbind failed")
+		Expect(<-recorder.Events).To(Equal("Warning DeleteFailed unbind failed"))
+	})
+})
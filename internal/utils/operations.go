@@ -0,0 +1,70 @@
+package utils
+
+import (
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	"github.com/SAP/sap-btp-service-operator/client/sm"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	authv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperationsRecorder is a resource that keeps a history of its operations in Service Manager
+type OperationsRecorder interface {
+	GetOperations() []v1.Operation
+	SetOperations([]v1.Operation)
+	GetUserInfo() *authv1.UserInfo
+}
+
+// RecordOperationStarted records an async operation that is in progress in Service Manager
+func RecordOperationStarted(object OperationsRecorder, operationType smClientTypes.OperationCategory, operationURL string) {
+	addOperation(object, v1.Operation{
+		Type:      operationType,
+		ID:        sm.ExtractOperationID(operationURL),
+		State:     smClientTypes.INPROGRESS,
+		StartedAt: metav1.Now(),
+		User:      getOperationUser(object),
+	})
+}
+
+// RecordOperationCompleted records the final state of an operation, the latest operation of the type that is in progress is completed,
+// if there is none the operation was sync and it is recorded as started and completed now
+func RecordOperationCompleted(object OperationsRecorder, operationType smClientTypes.OperationCategory, operationID string, state smClientTypes.OperationState, description string) {
+	now := metav1.Now()
+	operations := object.GetOperations()
+	for i := len(operations) - 1; i >= 0; i-- {
+		if operations[i].Type == operationType && operations[i].CompletedAt == nil {
+			if len(operations[i].ID) == 0 {
+				operations[i].ID = operationID
+			}
+			operations[i].State = state
+			operations[i].Description = description
+			operations[i].CompletedAt = &now
+			return
+		}
+	}
+
+	addOperation(object, v1.Operation{
+		Type:        operationType,
+		ID:          operationID,
+		State:       state,
+		StartedAt:   now,
+		CompletedAt: &now,
+		Description: description,
+		User:        getOperationUser(object),
+	})
+}
+
+func addOperation(object OperationsRecorder, operation v1.Operation) {
+	operations := append(object.GetOperations(), operation)
+	if len(operations) > v1.MaxOperations {
+		operations = operations[len(operations)-v1.MaxOperations:]
+	}
+	object.SetOperations(operations)
+}
+
+func getOperationUser(object OperationsRecorder) string {
+	if userInfo := object.GetUserInfo(); userInfo != nil {
+		return userInfo.Username
+	}
+	return ""
+}
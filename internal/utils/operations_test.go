@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"fmt"
+
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	authv1 "k8s.io/api/authentication/v1"
+)
+
+var _ = Describe("Operations", func() {
+	var instance *v1.ServiceInstance
+
+	BeforeEach(func() {
+		instance = &v1.ServiceInstance{Spec: v1.ServiceInstanceSpec{UserInfo: &authv1.UserInfo{Username: "test-user"}}}
+	})
+
+	It("should complete the async operation that is in progress", func() {
+		RecordOperationStarted(instance, smClientTypes.UPDATE, "/v1/service_instances/1234/operations/5678")
+		Expect(instance.Status.Operations).To(HaveLen(1))
+		Expect(instance.Status.Operations[0].ID).To(Equal("5678"))
+		Expect(instance.Status.Operations[0].State).To(Equal(smClientTypes.INPROGRESS))
+		Expect(instance.Status.Operations[0].CompletedAt).To(BeNil())
+
+		RecordOperationCompleted(instance, smClientTypes.UPDATE, "5678", smClientTypes.FAILED, "update failed")
+		Expect(instance.Status.Operations).To(HaveLen(1))
+		operation := instance.Status.Operations[0]
+		Expect(operation.Type).To(Equal(smClientTypes.UPDATE))
+		Expect(operation.State).To(Equal(smClientTypes.FAILED))
+		Expect(operation.Description).To(Equal("update failed"))
+		Expect(operation.User).To(Equal("test-user"))
+		Expect(operation.CompletedAt).NotTo(BeNil())
+	})
+
+	It("should record a sync operation as started and completed", func() {
+		RecordOperationCompleted(instance, smClientTypes.CREATE, "", smClientTypes.SUCCEEDED, "")
+		Expect(instance.Status.Operations).To(HaveLen(1))
+		Expect(instance.Status.Operations[0].StartedAt).To(Equal(*instance.Status.Operations[0].CompletedAt))
+		Expect(instance.Status.Operations[0].User).To(Equal("test-user"))
+	})
+
+	It("should keep only the latest operations", func() {
+		for i := 0; i < v1.MaxOperations+2; i++ {
+			RecordOperationCompleted(instance, smClientTypes.UPDATE, fmt.Sprintf("op-%d", i), smClientTypes.SUCCEEDED, "")
+		}
+		Expect(instance.Status.Operations).To(HaveLen(v1.MaxOperations))
+		Expect(instance.Status.Operations[0].ID).To(Equal("op-2"))
+		Expect(instance.Status.Operations[v1.MaxOperations-1].ID).To(Equal(fmt.Sprintf("op-%d", v1.MaxOperations+1)))
+	})
+})
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// IsPausedAnnotationSet returns true if the paused annotation of the object is true
+func IsPausedAnnotationSet(obj metav1.Object) bool {
+	return strings.EqualFold(obj.GetAnnotations()[common.PausedAnnotation], "true")
+}
+
+// PausedAnnotationChanged returns a predicate of the updates of objects whose paused annotation was set or removed
+func PausedAnnotationChanged() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return IsPausedAnnotationSet(e.ObjectOld) != IsPausedAnnotationSet(e.ObjectNew)
+		},
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// HandlePaused sets the Paused condition of a resource whose reconciliation is paused by the paused annotation of the resource or its namespace,
+// and removes it once the reconciliation is resumed. It returns true if the reconciliation should be skipped, the reconciliation of a
+// paused resource that is marked for deletion is skipped unless allowDelete is true.
+func HandlePaused(ctx context.Context, k8sClient client.Client, object common.SAPBTPResource, allowDelete bool) (bool, error) {
+	log := GetLogger(ctx)
+	reason, message, err := getPausedReason(ctx, k8sClient, object)
+	if err != nil {
+		log.Error(err, "failed to check if the reconciliation is paused")
+		return false, err
+	}
+
+	conditions := object.GetConditions()
+	if len(reason) == 0 {
+		if meta.FindStatusCondition(conditions, common.ConditionPaused) == nil {
+			return false, nil
+		}
+		log.Info("reconciliation is resumed, removing paused condition")
+		meta.RemoveStatusCondition(&conditions, common.ConditionPaused)
+		object.SetConditions(conditions)
+		return false, UpdateStatus(ctx, k8sClient, object)
+	}
+
+	if allowDelete && !object.GetDeletionTimestamp().IsZero() {
+		log.Info("reconciliation is paused but deletion is allowed, deleting resource")
+		return false, nil
+	}
+
+	log.Info(fmt.Sprintf("reconciliation is paused, %s", message))
+	if cond := meta.FindStatusCondition(conditions, common.ConditionPaused); cond != nil && cond.Reason == reason && cond.ObservedGeneration == object.GetGeneration() {
+		return true, nil
+	}
+	meta.SetStatusCondition(&conditions, metav1.Condition{
+		Type:               common.ConditionPaused,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: object.GetGeneration(),
+	})
+	object.SetConditions(conditions)
+	return true, UpdateStatus(ctx, k8sClient, object)
+}
+
+func getPausedReason(ctx context.Context, k8sClient client.Client, object common.SAPBTPResource) (string, string, error) {
+	if IsPausedAnnotationSet(object) {
+		return common.Paused, fmt.Sprintf("the %s annotation of the resource is set", common.PausedAnnotation), nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := k8sClient.Get(ctx, apimachinerytypes.NamespacedName{Name: object.GetNamespace()}, namespace); err != nil {
+		return "", "", client.IgnoreNotFound(err)
+	}
+	if IsPausedAnnotationSet(namespace) {
+		return common.PausedNamespace, fmt.Sprintf("the %s annotation of namespace %s is set", common.PausedAnnotation, namespace.Name), nil
+	}
+	return "", "", nil
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Pause", func() {
+	var (
+		pauseClient client.Client
+		namespace   *corev1.Namespace
+		instance    *v1.ServiceInstance
+	)
+
+	BeforeEach(func() {
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "paused-namespace"}}
+		instance = &v1.ServiceInstance{ObjectMeta: metav1.ObjectMeta{Name: "paused-instance", Namespace: namespace.Name}}
+	})
+
+	JustBeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1.AddToScheme(scheme)).To(Succeed())
+		pauseClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespace, instance).WithStatusSubresource(instance).Build()
+	})
+
+	It("should not skip the reconciliation of a resource that is not paused", func() {
+		paused, err := HandlePaused(ctx, pauseClient, instance, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(paused).To(BeFalse())
+		Expect(meta.FindStatusCondition(instance.GetConditions(), common.ConditionPaused)).To(BeNil())
+	})
+
+	When("the resource is paused", func() {
+		BeforeEach(func() {
+			instance.Annotations = map[string]string{common.PausedAnnotation: "true"}
+		})
+
+		It("should skip the reconciliation and set the paused condition", func() {
+			paused, err := HandlePaused(ctx, pauseClient, instance, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(paused).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(instance.GetConditions(), common.ConditionPaused)).To(BeTrue())
+			Expect(meta.FindStatusCondition(instance.GetConditions(), common.ConditionPaused).Reason).To(Equal(common.Paused))
+		})
+
+		It("should remove the paused condition once it is resumed", func() {
+			_, err := HandlePaused(ctx, pauseClient, instance, false)
+			Expect(err).ToNot(HaveOccurred())
+
+			instance.Annotations = nil
+			paused, err := HandlePaused(ctx, pauseClient, instance, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(paused).To(BeFalse())
+			Expect(meta.FindStatusCondition(instance.GetConditions(), common.ConditionPaused)).To(BeNil())
+		})
+
+		When("the resource is marked for deletion", func() {
+			BeforeEach(func() {
+				instance.Finalizers = []string{common.FinalizerName}
+				instance.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+			})
+
+			It("should skip the deletion unless it is allowed", func() {
+				paused, err := HandlePaused(ctx, pauseClient, instance, false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(paused).To(BeTrue())
+
+				paused, err = HandlePaused(ctx, pauseClient, instance, true)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(paused).To(BeFalse())
+			})
+		})
+	})
+
+	When("the namespace is paused", func() {
+		BeforeEach(func() {
+			namespace.Annotations = map[string]string{common.PausedAnnotation: "True"}
+		})
+
+		It("should skip the reconciliation of the resources of the namespace", func() {
+			paused, err := HandlePaused(ctx, pauseClient, instance, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(paused).To(BeTrue())
+			Expect(meta.FindStatusCondition(instance.GetConditions(), common.ConditionPaused).Reason).To(Equal(common.PausedNamespace))
+		})
+	})
+})
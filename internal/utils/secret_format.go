@@ -0,0 +1,236 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	DOTENV     format = "dotenv"
+	PROPERTIES format = "properties"
+	YAML       format = "yaml"
+)
+
+var defaultSecretFormatKeys = map[string]string{
+	v1.SecretFormatDotenv:     "credentials.env",
+	v1.SecretFormatProperties: "credentials.properties",
+	v1.SecretFormatYAML:       "credentials.yaml",
+}
+
+var invalidSecretKeyChars = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+var invalidEnvKeyChars = regexp.MustCompile(`[^_a-zA-Z0-9]`)
+
+// flatCredential is a credential with the joined keys of its path in the credentials
+type flatCredential struct {
+	key   string
+	value interface{}
+}
+
+// FormatCredentials stores the credentials according to the secret format of the binding,
+// it returns the secret data and its metadata properties
+func FormatCredentials(credentialsJSON json.RawMessage, secretFormat *v1.SecretFormat) (map[string][]byte, []SecretMetadataProperty, error) {
+	decoder := json.NewDecoder(bytes.NewReader(credentialsJSON))
+	decoder.UseNumber()
+	var credentialsMap map[string]interface{}
+	if err := decoder.Decode(&credentialsMap); err != nil {
+		return nil, nil, err
+	}
+
+	if secretFormat.Type == v1.SecretFormatYAML {
+		data, err := yaml.Marshal(convertKeyCase(credentialsMap, secretFormat.KeyCase))
+		if err != nil {
+			return nil, nil, err
+		}
+		return fileCredentials(secretFormat, data, YAML)
+	}
+
+	credentials, err := flattenCredentials(credentialsMap, secretFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch secretFormat.Type {
+	case v1.SecretFormatDotenv:
+		var buf bytes.Buffer
+		for _, credential := range credentials {
+			fmt.Fprintf(&buf, "%s=\"%s\"\n", credential.key, escapeDotenvValue(formatValue(credential.value)))
+		}
+		return fileCredentials(secretFormat, buf.Bytes(), DOTENV)
+	case v1.SecretFormatProperties:
+		var buf bytes.Buffer
+		for _, credential := range credentials {
+			fmt.Fprintf(&buf, "%s=%s\n", escapeProperty(credential.key, true), escapeProperty(formatValue(credential.value), false))
+		}
+		return fileCredentials(secretFormat, buf.Bytes(), PROPERTIES)
+	case v1.SecretFormatFlat:
+		normalized := make(map[string][]byte)
+		metadata := make([]SecretMetadataProperty, 0, len(credentials))
+		for _, credential := range credentials {
+			typpe := JSON
+			if _, ok := credential.value.(string); ok {
+				typpe = TEXT
+			}
+			normalized[credential.key] = []byte(formatValue(credential.value))
+			metadata = append(metadata, SecretMetadataProperty{
+				Name:   credential.key,
+				Format: string(typpe),
+			})
+		}
+		return normalized, metadata, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported secret format type %s", secretFormat.Type)
+}
+
+func fileCredentials(secretFormat *v1.SecretFormat, data []byte, typpe format) (map[string][]byte, []SecretMetadataProperty, error) {
+	key := secretFormat.Key
+	if len(key) == 0 {
+		key = defaultSecretFormatKeys[secretFormat.Type]
+	}
+	return map[string][]byte{key: data}, []SecretMetadataProperty{
+		{
+			Name:      key,
+			Format:    string(typpe),
+			Container: true,
+		},
+	}, nil
+}
+
+// flattenCredentials returns the credentials that are not objects sorted by their joined keys
+func flattenCredentials(credentialsMap map[string]interface{}, secretFormat *v1.SecretFormat) ([]flatCredential, error) {
+	separator := "_"
+	if secretFormat.Type == v1.SecretFormatProperties {
+		separator = "."
+	}
+	if secretFormat.Separator != nil {
+		separator = *secretFormat.Separator
+	}
+
+	var credentials []flatCredential
+	var flatten func(prefix string, m map[string]interface{})
+	flatten = func(prefix string, m map[string]interface{}) {
+		for key, value := range m {
+			key = prefix + strings.Replace(key, " ", "_", -1)
+			if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+				flatten(key+separator, nested)
+				continue
+			}
+			credentials = append(credentials, flatCredential{key: key, value: value})
+		}
+	}
+	flatten("", credentialsMap)
+	sortCredentials(credentials)
+
+	keys := make(map[string]string, len(credentials))
+	for i := range credentials {
+		original := credentials[i].key
+		key := convertCase(original, secretFormat.KeyCase)
+		switch secretFormat.Type {
+		case v1.SecretFormatFlat:
+			key = invalidSecretKeyChars.ReplaceAllString(key, "_")
+		case v1.SecretFormatDotenv:
+			key = invalidEnvKeyChars.ReplaceAllString(key, "_")
+		}
+		if other, ok := keys[key]; ok {
+			return nil, fmt.Errorf("the credentials %s and %s have the same key %s in the %s secret format", other, original, key, secretFormat.Type)
+		}
+		keys[key] = original
+		credentials[i].key = key
+	}
+
+	sortCredentials(credentials)
+	return credentials, nil
+}
+
+func sortCredentials(credentials []flatCredential) {
+	sort.Slice(credentials, func(i, j int) bool {
+		return credentials[i].key < credentials[j].key
+	})
+}
+
+// convertKeyCase converts the keys of the nested objects of the value
+func convertKeyCase(value interface{}, keyCase string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, nested := range v {
+			converted[convertCase(key, keyCase)] = convertKeyCase(nested, keyCase)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, nested := range v {
+			converted[i] = convertKeyCase(nested, keyCase)
+		}
+		return converted
+	}
+	return value
+}
+
+func convertCase(key, keyCase string) string {
+	switch keyCase {
+	case v1.KeyCaseUpper:
+		return strings.ToUpper(key)
+	case v1.KeyCaseLower:
+		return strings.ToLower(key)
+	}
+	return key
+}
+
+// formatValue returns strings as is and other values as JSON
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func escapeDotenvValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`).Replace(value)
+}
+
+// escapeProperty escapes a key or a value of a Java properties file as java.util.Properties#store does
+func escapeProperty(s string, isKey bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch r {
+		case ' ':
+			if i == 0 || isKey {
+				b.WriteString(`\ `)
+			} else {
+				b.WriteRune(r)
+			}
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':', '#', '!', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		default:
+			if r < 0x20 || r > 0x7e {
+				for _, unit := range utf16.Encode([]rune{r}) {
+					fmt.Fprintf(&b, `\u%04X`, unit)
+				}
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
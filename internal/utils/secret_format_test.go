@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"encoding/json"
+
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("Secret format", func() {
+	var credentialsJSON json.RawMessage
+
+	BeforeEach(func() {
+		credentialsJSON = []byte(`{"url":"https://my-service.com","port":8443,"enabled":true,"uaa":{"clientid":"id","client secret":"a \"b\"\nc"},"tags":["a","b"]}`)
+	})
+
+	Context("flat", func() {
+		It("should store every credential under its own key", func() {
+			res, metadata, err := FormatCredentials(credentialsJSON, &v1.SecretFormat{Type: v1.SecretFormatFlat})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[string][]byte{
+				"url":               []byte("https://my-service.com"),
+				"port":              []byte("8443"),
+				"enabled":           []byte("true"),
+				"uaa_clientid":      []byte("id"),
+				"uaa_client_secret": []byte("a \"b\"\nc"),
+				"tags":              []byte(`["a","b"]`),
+			}))
+			Expect(metadata).To(ContainElements(
+				SecretMetadataProperty{Name: "uaa_clientid", Format: string(TEXT)},
+				SecretMetadataProperty{Name: "port", Format: string(JSON)},
+				SecretMetadataProperty{Name: "tags", Format: string(JSON)},
+			))
+			Expect(metadata).To(HaveLen(6))
+		})
+
+		It("should use the key case and the separator", func() {
+			res, metadata, err := FormatCredentials(credentialsJSON, &v1.SecretFormat{Type: v1.SecretFormatFlat, KeyCase: v1.KeyCaseUpper, Separator: pointer.String("__")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(HaveKeyWithValue("UAA__CLIENTID", []byte("id")))
+			Expect(metadata).To(ContainElement(SecretMetadataProperty{Name: "UAA__CLIENTID", Format: string(TEXT)}))
+		})
+
+		It("should fail if two credentials have the same key", func() {
+			_, _, err := FormatCredentials([]byte(`{"uaa_url":"a","uaa":{"url":"b"}}`), &v1.SecretFormat{Type: v1.SecretFormatFlat})
+			Expect(err).To(MatchError("the credentials uaa_url and uaa_url have the same key uaa_url in the flat secret format"))
+		})
+	})
+
+	Context("dotenv", func() {
+		It("should store the credentials in a single .env file", func() {
+			res, metadata, err := FormatCredentials(credentialsJSON, &v1.SecretFormat{Type: v1.SecretFormatDotenv, KeyCase: v1.KeyCaseUpper})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(1))
+			Expect(string(res["credentials.env"])).To(Equal(`ENABLED="true"
+PORT="8443"
+TAGS="[\"a\",\"b\"]"
+UAA_CLIENTID="id"
+UAA_CLIENT_SECRET="a \"b\"\nc"
+URL="https://my-service.com"
+`))
+			Expect(metadata).To(Equal([]SecretMetadataProperty{{Name: "credentials.env", Format: string(DOTENV), Container: true}}))
+		})
+	})
+
+	Context("properties", func() {
+		It("should store the credentials in a single .properties file", func() {
+			res, metadata, err := FormatCredentials(credentialsJSON, &v1.SecretFormat{Type: v1.SecretFormatProperties, Key: "application.properties"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(res["application.properties"])).To(Equal(`enabled=true
+port=8443
+tags=["a","b"]
+uaa.client_secret=a "b"\nc
+uaa.clientid=id
+url=https\://my-service.com
+`))
+			Expect(metadata).To(Equal([]SecretMetadataProperty{{Name: "application.properties", Format: string(PROPERTIES), Container: true}}))
+		})
+	})
+
+	Context("yaml", func() {
+		It("should store the credentials in a single YAML file", func() {
+			res, metadata, err := FormatCredentials(credentialsJSON, &v1.SecretFormat{Type: v1.SecretFormatYAML, KeyCase: v1.KeyCaseUpper})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(res["credentials.yaml"])).To(Equal(`ENABLED: true
+PORT: 8443
+TAGS:
+- a
+- b
+UAA:
+  CLIENT SECRET: |-
+    a "b"
+    c
+  CLIENTID: id
+URL: https://my-service.com
+`))
+			Expect(metadata).To(Equal([]SecretMetadataProperty{{Name: "credentials.yaml", Format: string(YAML), Container: true}}))
+		})
+	})
+})
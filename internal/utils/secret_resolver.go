@@ -20,6 +20,8 @@ import (
 const (
 	SAPBTPOperatorSecretName    = "sap-btp-service-operator"
 	SAPBTPOperatorTLSSecretName = "sap-btp-service-operator-tls"
+	ClusterIDSecretName         = "sap-btp-operator-clusterid"
+	InitialClusterIDKey         = "INITIAL_CLUSTER_ID"
 )
 
 var secretsClient secretClient
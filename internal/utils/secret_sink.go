@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SecretSinkSecretName is the name of the secret that configures the secret sink of the bindings of a namespace or of the cluster
+const SecretSinkSecretName = "sap-btp-secret-sink"
+
+// SecretSink stores the secrets of the service bindings
+type SecretSink interface {
+	// Get returns the secret of the binding, or a not found error if it does not exist
+	Get(ctx context.Context, binding *v1.ServiceBinding) (*corev1.Secret, error)
+	// Store creates or updates the secret of the binding, and returns true if it was created
+	Store(ctx context.Context, binding *v1.ServiceBinding, secret *corev1.Secret) (bool, error)
+	// Delete deletes the secret of the binding if it exists
+	Delete(ctx context.Context, binding *v1.ServiceBinding) error
+}
+
+// GetSecretSink returns the secret sink of spec.secretSink of the binding, or of the sap-btp-secret-sink secret of its namespace or the cluster,
+// the Kubernetes secret sink if none is configured. A sink secret with enforce: "true" rejects a spec.secretSink of another type
+func GetSecretSink(ctx context.Context, binding *v1.ServiceBinding) (SecretSink, error) {
+	sinkSecret, err := secretsClient.getSecretSinkSecret(ctx, binding.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	// the secret of a deleted binding is cleaned up from the sink it was stored in
+	if binding.DeletionTimestamp.IsZero() {
+		if err := v1.ValidateSecretSink(binding, sinkSecret); err != nil {
+			return nil, err
+		}
+	}
+
+	sinkType := binding.Spec.SecretSink
+	if len(sinkType) == 0 && sinkSecret != nil {
+		sinkType = string(sinkSecret.Data["type"])
+	}
+
+	switch sinkType {
+	case "", v1.SecretSinkKubernetes:
+		return &KubernetesSecretSink{Client: secretsClient.Client}, nil
+	case v1.SecretSinkKV:
+		if sinkSecret == nil {
+			return nil, fmt.Errorf("the %s secret sink is not configured, the %s secret was not found", v1.SecretSinkKV, SecretSinkSecretName)
+		}
+		return NewKVSecretSink(sinkSecret)
+	}
+	return nil, fmt.Errorf("unsupported secret sink type %s", sinkType)
+}
+
+// GetSecretSinkSecret returns the secret sink secret of the namespace or of the cluster, nil if there is none
+func GetSecretSinkSecret(ctx context.Context, namespace string) (*corev1.Secret, error) {
+	return secretsClient.getSecretSinkSecret(ctx, namespace)
+}
+
+// getSecretSinkSecret returns the secret sink secret of the namespace or of the cluster, nil if there is none,
+// it is resolved like the secret of the Service Manager credentials
+func (sr *secretClient) getSecretSinkSecret(ctx context.Context, namespace string) (*corev1.Secret, error) {
+	keys := []types.NamespacedName{
+		{Namespace: sr.ManagementNamespace, Name: fmt.Sprintf("%s-%s", namespace, SecretSinkSecretName)},
+		{Namespace: sr.ReleaseNamespace, Name: SecretSinkSecretName},
+	}
+	if sr.EnableNamespaceSecrets {
+		keys = append([]types.NamespacedName{{Namespace: namespace, Name: SecretSinkSecretName}}, keys...)
+	}
+
+	for _, key := range keys {
+		secret := &corev1.Secret{}
+		err := sr.getWithClientFallback(ctx, key, secret)
+		if err == nil {
+			return secret, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// KubernetesSecretSink stores the binding secrets as Kubernetes secrets in the namespace of the binding
+type KubernetesSecretSink struct {
+	Client client.Client
+}
+
+func (s *KubernetesSecretSink) Get(ctx context.Context, binding *v1.ServiceBinding) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := GetSecretWithFallback(ctx, types.NamespacedName{Namespace: binding.Namespace, Name: binding.Spec.SecretName}, secret)
+	return secret, err
+}
+
+func (s *KubernetesSecretSink) Store(ctx context.Context, binding *v1.ServiceBinding, secret *corev1.Secret) (bool, error) {
+	log := GetLogger(ctx)
+	dbSecret := &corev1.Secret{}
+	if err := s.Client.Get(ctx, types.NamespacedName{Name: binding.Spec.SecretName, Namespace: binding.Namespace}, dbSecret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+
+		log.Info("Creating binding secret", "name", secret.Name)
+		if err := s.Client.Create(ctx, secret); err != nil {
+			if !apierrors.IsAlreadyExists(err) {
+				return false, err
+			}
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if getSecretType(dbSecret) != getSecretType(secret) {
+		// the type of a secret is immutable
+		log.Info("Recreating binding secret with a new type", "name", secret.Name, "type", getSecretType(secret))
+		if err := s.Client.Delete(ctx, dbSecret); err != nil && !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		return false, s.Client.Create(ctx, secret)
+	}
+
+	log.Info("Updating existing binding secret", "name", secret.Name)
+	dbSecret.Data = secret.Data
+	dbSecret.StringData = secret.StringData
+	dbSecret.Labels = secret.Labels
+	dbSecret.Annotations = secret.Annotations
+	return false, s.Client.Update(ctx, dbSecret)
+}
+
+func (s *KubernetesSecretSink) Delete(ctx context.Context, binding *v1.ServiceBinding) error {
+	log := GetLogger(ctx)
+	bindingSecret := &corev1.Secret{}
+	if err := s.Client.Get(ctx, types.NamespacedName{
+		Namespace: binding.Namespace,
+		Name:      binding.Spec.SecretName,
+	}, bindingSecret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch binding secret")
+			return err
+		}
+
+		// secret not found, nothing more to do
+		return nil
+	}
+	bindingSecret = bindingSecret.DeepCopy()
+
+	if err := s.Client.Delete(ctx, bindingSecret); err != nil {
+		log.Error(err, "Failed to delete binding secret")
+		return err
+	}
+	return nil
+}
+
+func getSecretType(secret *corev1.Secret) corev1.SecretType {
+	if len(secret.Type) == 0 {
+		return corev1.SecretTypeOpaque
+	}
+	return secret.Type
+}
@@ -0,0 +1,273 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	"github.com/SAP/sap-btp-service-operator/internal/httputil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultKVMountPath  = "secret"
+	defaultKVPathPrefix = "sap-btp-service-operator"
+	// the custom metadata keys of the binding that stored the secret
+	kvOwnerUIDKey  = "services.cloud.sap.com/binding-uid"
+	kvOwnerNameKey = "services.cloud.sap.com/binding-name"
+)
+
+// KVSecretSink stores the binding secrets in a key-value store with the HTTP API of the Vault KV secrets engine version 2,
+// the data of a secret is stored under <mountPath>/data/<pathPrefix>/<namespace>/<secretName> and its annotations as custom metadata,
+// together with the UID of the binding so that a secret is never overwritten or deleted by another binding with the same secret name
+type KVSecretSink struct {
+	Address    string
+	Token      string
+	MountPath  string
+	PathPrefix string
+	HTTPClient *http.Client
+}
+
+// KVSecretSinkError is an error of a request to the key-value store, the status code is 0 if no response was received
+type KVSecretSinkError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *KVSecretSinkError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *KVSecretSinkError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransient returns true if the request may succeed when it is retried, i.e. no response was received,
+// or the key-value store is unavailable (5xx) or rate limits the requests (429)
+func (e *KVSecretSinkError) IsTransient() bool {
+	return e.StatusCode == 0 || e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+type kvSecret struct {
+	Data     map[string]string `json:"data"`
+	Metadata *kvMetadata       `json:"metadata,omitempty"`
+}
+
+type kvMetadata struct {
+	CustomMetadata map[string]string `json:"custom_metadata"`
+}
+
+// NewKVSecretSink returns a KV secret sink configured by the address, token, mountPath and pathPrefix keys of the secret sink secret,
+// the TLS connection to the key-value store is verified with the PEM certificates of the caCert key if set, or not at all if insecureSkipVerify is "true"
+func NewKVSecretSink(sinkSecret *corev1.Secret) (*KVSecretSink, error) {
+	httpClient := httputil.BuildHTTPClient(string(sinkSecret.Data["insecureSkipVerify"]) == "true")
+	httpClient.Timeout = 30 * time.Second
+	if caCert := sinkSecret.Data["caCert"]; len(caCert) > 0 {
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("the caCert key of the %s secret has no valid PEM certificate", SecretSinkSecretName)
+		}
+		transport := httpClient.Transport.(*http.Transport)
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.RootCAs = rootCAs
+	}
+
+	sink := &KVSecretSink{
+		Address:    strings.TrimSuffix(string(sinkSecret.Data["address"]), "/"),
+		Token:      string(sinkSecret.Data["token"]),
+		MountPath:  string(sinkSecret.Data["mountPath"]),
+		PathPrefix: string(sinkSecret.Data["pathPrefix"]),
+		HTTPClient: httpClient,
+	}
+	if len(sink.Address) == 0 || len(sink.Token) == 0 {
+		return nil, fmt.Errorf("the %s secret must have the address and token keys for the %s secret sink", SecretSinkSecretName, v1.SecretSinkKV)
+	}
+	if len(sink.MountPath) == 0 {
+		sink.MountPath = defaultKVMountPath
+	}
+	if len(sink.PathPrefix) == 0 {
+		sink.PathPrefix = defaultKVPathPrefix
+	}
+	return sink, nil
+}
+
+// SecretPath returns the path of the secret of the binding in the key-value store
+func (s *KVSecretSink) SecretPath(binding *v1.ServiceBinding) string {
+	return path.Join(s.MountPath, s.PathPrefix, binding.Namespace, binding.Spec.SecretName)
+}
+
+func (s *KVSecretSink) Get(ctx context.Context, binding *v1.ServiceBinding) (*corev1.Secret, error) {
+	stored, err := s.read(ctx, binding)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return nil, apierrors.NewNotFound(corev1.Resource("secrets"), binding.Spec.SecretName)
+	}
+	if !isKVSecretOwnedBy(stored, binding) {
+		return nil, s.ownedByOtherBindingError(stored, binding)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: binding.Spec.SecretName, Namespace: binding.Namespace},
+		Data:       make(map[string][]byte, len(stored.Data)),
+	}
+	for key, value := range stored.Data {
+		secret.Data[key] = []byte(value)
+	}
+	if stored.Metadata != nil {
+		for key, value := range stored.Metadata.CustomMetadata {
+			if key == kvOwnerUIDKey || key == kvOwnerNameKey {
+				continue
+			}
+			if secret.Annotations == nil {
+				secret.Annotations = map[string]string{}
+			}
+			secret.Annotations[key] = value
+		}
+	}
+	return secret, nil
+}
+
+// GetOtherOwner returns the name of the binding that stored the secret of the binding if it is another binding, empty otherwise
+func (s *KVSecretSink) GetOtherOwner(ctx context.Context, binding *v1.ServiceBinding) (string, error) {
+	stored, err := s.read(ctx, binding)
+	if err != nil || stored == nil || isKVSecretOwnedBy(stored, binding) {
+		return "", err
+	}
+	return stored.Metadata.CustomMetadata[kvOwnerNameKey], nil
+}
+
+func (s *KVSecretSink) Store(ctx context.Context, binding *v1.ServiceBinding, secret *corev1.Secret) (bool, error) {
+	log := GetLogger(ctx)
+	stored, err := s.read(ctx, binding)
+	if err != nil {
+		return false, err
+	}
+	if stored != nil && !isKVSecretOwnedBy(stored, binding) {
+		return false, s.ownedByOtherBindingError(stored, binding)
+	}
+
+	data := make(map[string]string, len(secret.Data)+len(secret.StringData))
+	for key, value := range secret.Data {
+		data[key] = string(value)
+	}
+	for key, value := range secret.StringData {
+		data[key] = value
+	}
+
+	log.Info("Storing binding secret in the key-value store", "path", s.SecretPath(binding))
+	if _, err := s.do(ctx, http.MethodPost, s.apiPath("data", binding), kvSecret{Data: data}, nil); err != nil {
+		return false, err
+	}
+	customMetadata := make(map[string]string, len(secret.Annotations)+2)
+	for key, value := range secret.Annotations {
+		customMetadata[key] = value
+	}
+	customMetadata[kvOwnerUIDKey] = string(binding.UID)
+	customMetadata[kvOwnerNameKey] = binding.Name
+	if _, err := s.do(ctx, http.MethodPost, s.apiPath("metadata", binding), kvMetadata{CustomMetadata: customMetadata}, nil); err != nil {
+		return false, err
+	}
+	return stored == nil, nil
+}
+
+func (s *KVSecretSink) Delete(ctx context.Context, binding *v1.ServiceBinding) error {
+	log := GetLogger(ctx)
+	stored, err := s.read(ctx, binding)
+	if err != nil || stored == nil {
+		return err
+	}
+	if !isKVSecretOwnedBy(stored, binding) {
+		log.Info("The binding secret in the key-value store belongs to another binding, not deleting it", "path", s.SecretPath(binding))
+		return nil
+	}
+
+	// deleting the metadata deletes all the versions of the secret
+	_, err = s.do(ctx, http.MethodDelete, s.apiPath("metadata", binding), nil, nil)
+	return err
+}
+
+// read returns the secret of the binding in the key-value store, nil if it does not exist
+func (s *KVSecretSink) read(ctx context.Context, binding *v1.ServiceBinding) (*kvSecret, error) {
+	response := struct {
+		Data kvSecret `json:"data"`
+	}{}
+	found, err := s.do(ctx, http.MethodGet, s.apiPath("data", binding), nil, &response)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &response.Data, nil
+}
+
+func (s *KVSecretSink) ownedByOtherBindingError(stored *kvSecret, binding *v1.ServiceBinding) error {
+	return fmt.Errorf("the secret %s in the key-value store belongs to another binding %s, choose a different secret name", s.SecretPath(binding), stored.Metadata.CustomMetadata[kvOwnerNameKey])
+}
+
+// isKVSecretOwnedBy returns true if the secret was stored by the binding, secrets stored without an owner belong to any binding
+func isKVSecretOwnedBy(stored *kvSecret, binding *v1.ServiceBinding) bool {
+	if stored.Metadata == nil {
+		return true
+	}
+	ownerUID, ok := stored.Metadata.CustomMetadata[kvOwnerUIDKey]
+	return !ok || ownerUID == string(binding.UID)
+}
+
+func (s *KVSecretSink) apiPath(endpoint string, binding *v1.ServiceBinding) string {
+	return "/" + path.Join("v1", s.MountPath, endpoint, s.PathPrefix, binding.Namespace, binding.Spec.SecretName)
+}
+
+// do sends a request to the key-value store, it returns false if the secret was not found
+func (s *KVSecretSink) do(ctx context.Context, method, apiPath string, body interface{}, result interface{}) (bool, error) {
+	var reader io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return false, err
+		}
+		reader = bytes.NewReader(bodyBytes)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, s.Address+apiPath, reader)
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("X-Vault-Token", s.Token)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := s.HTTPClient.Do(request)
+	if err != nil {
+		return false, &KVSecretSinkError{Err: err}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		err := fmt.Errorf("key-value store request %s %s failed with status %d", method, apiPath, response.StatusCode)
+		if responseBody, _ := io.ReadAll(response.Body); len(bytes.TrimSpace(responseBody)) > 0 {
+			err = fmt.Errorf("%w: %s", err, bytes.TrimSpace(responseBody))
+		}
+		return false, &KVSecretSinkError{StatusCode: response.StatusCode, Err: err}
+	}
+	if result != nil {
+		return true, json.NewDecoder(response.Body).Decode(result)
+	}
+	return true, nil
+}
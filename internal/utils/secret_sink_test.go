@@ -0,0 +1,320 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	"github.com/SAP/sap-btp-service-operator/internal/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// kvStore is a stand-in for the KV secrets engine version 2
+type kvStore struct {
+	mutex    sync.Mutex
+	data     map[string]map[string]string
+	metadata map[string]map[string]string
+}
+
+func newKVStore() (*kvStore, *httptest.Server) {
+	store := &kvStore{data: map[string]map[string]string{}, metadata: map[string]map[string]string{}}
+	return store, httptest.NewServer(store)
+}
+
+func (s *kvStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if r.Header.Get("X-Vault-Token") != "token" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	// /v1/<mount>/<data|metadata>/<path>
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/v1/"), "/", 3)
+	if len(parts) != 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	endpoint, path := parts[1], parts[0]+"/"+parts[2]
+	switch {
+	case endpoint == "data" && r.Method == http.MethodGet:
+		data, ok := s.data[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": kvSecret{Data: data, Metadata: &kvMetadata{CustomMetadata: s.metadata[path]}}})
+	case endpoint == "data" && r.Method == http.MethodPost:
+		body := kvSecret{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		s.data[path] = body.Data
+	case endpoint == "metadata" && r.Method == http.MethodPost:
+		body := kvMetadata{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		s.metadata[path] = body.CustomMetadata
+	case endpoint == "metadata" && r.Method == http.MethodDelete:
+		delete(s.data, path)
+		delete(s.metadata, path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+var _ = Describe("Secret sink", func() {
+	var (
+		ctx          context.Context
+		sinkClient   client.Client
+		binding      *v1.ServiceBinding
+		store        *kvStore
+		server       *httptest.Server
+		kvSinkSecret *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store, server = newKVStore()
+		binding = &v1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "binding", Namespace: "test"},
+			Spec:       v1.ServiceBindingSpec{SecretName: "binding-secret"},
+		}
+		kvSinkSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-" + SecretSinkSecretName, Namespace: "management"},
+			Data:       map[string][]byte{"type": []byte(v1.SecretSinkKV), "address": []byte(server.URL), "token": []byte("token")},
+		}
+		sinkClient = fake.NewClientBuilder().Build()
+		InitializeSecretsClient(sinkClient, nil, config.Config{ManagementNamespace: "management", ReleaseNamespace: "release"})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("GetSecretSink", func() {
+		It("should return the Kubernetes secret sink if none is configured", func() {
+			sink, err := GetSecretSink(ctx, binding)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sink).To(BeAssignableToTypeOf(&KubernetesSecretSink{}))
+		})
+
+		It("should return the secret sink of the namespace", func() {
+			Expect(sinkClient.Create(ctx, kvSinkSecret)).To(Succeed())
+			sink, err := GetSecretSink(ctx, binding)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sink).To(BeAssignableToTypeOf(&KVSecretSink{}))
+			Expect(sink.(*KVSecretSink).SecretPath(binding)).To(Equal("secret/sap-btp-service-operator/test/binding-secret"))
+		})
+
+		It("should return the secret sink of the binding", func() {
+			Expect(sinkClient.Create(ctx, kvSinkSecret)).To(Succeed())
+			binding.Spec.SecretSink = v1.SecretSinkKubernetes
+			sink, err := GetSecretSink(ctx, binding)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sink).To(BeAssignableToTypeOf(&KubernetesSecretSink{}))
+		})
+
+		It("should fail if the binding overrides an enforced secret sink", func() {
+			kvSinkSecret.Data[v1.SecretSinkEnforceKey] = []byte("true")
+			Expect(sinkClient.Create(ctx, kvSinkSecret)).To(Succeed())
+			binding.Spec.SecretSink = v1.SecretSinkKubernetes
+			_, err := GetSecretSink(ctx, binding)
+			Expect(err).To(MatchError("spec.secretSink Kubernetes is not allowed, the KV secret sink is enforced for the namespace test"))
+
+			By("returning the secret sink of the binding to delete its secret")
+			now := metav1.Now()
+			binding.DeletionTimestamp = &now
+			sink, err := GetSecretSink(ctx, binding)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sink).To(BeAssignableToTypeOf(&KubernetesSecretSink{}))
+		})
+
+		It("should fail if the KV secret sink is not configured", func() {
+			binding.Spec.SecretSink = v1.SecretSinkKV
+			_, err := GetSecretSink(ctx, binding)
+			Expect(err).To(MatchError("the KV secret sink is not configured, the sap-btp-secret-sink secret was not found"))
+		})
+	})
+
+	Context("KubernetesSecretSink", func() {
+		It("should store, get and delete the secret", func() {
+			sink := &KubernetesSecretSink{Client: sinkClient}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "binding-secret", Namespace: "test"},
+				Data:       map[string][]byte{"key": []byte("value")},
+			}
+			created, err := sink.Store(ctx, binding, secret)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created).To(BeTrue())
+
+			secret.Data = map[string][]byte{"key": []byte("new value")}
+			created, err = sink.Store(ctx, binding, secret)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created).To(BeFalse())
+			stored, err := sink.Get(ctx, binding)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Data).To(HaveKeyWithValue("key", []byte("new value")))
+
+			Expect(sink.Delete(ctx, binding)).To(Succeed())
+			_, err = sink.Get(ctx, binding)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(sink.Delete(ctx, binding)).To(Succeed())
+		})
+	})
+
+	Context("KVSecretSink", func() {
+		var sink *KVSecretSink
+
+		BeforeEach(func() {
+			var err error
+			sink, err = NewKVSecretSink(kvSinkSecret)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail without an address or a token", func() {
+			_, err := NewKVSecretSink(&corev1.Secret{Data: map[string][]byte{"address": []byte(server.URL)}})
+			Expect(err).To(MatchError("the sap-btp-secret-sink secret must have the address and token keys for the KV secret sink"))
+		})
+
+		When("the key-value store is served over TLS", func() {
+			var tlsServer *httptest.Server
+
+			BeforeEach(func() {
+				tlsServer = httptest.NewTLSServer(store)
+				kvSinkSecret.Data["address"] = []byte(tlsServer.URL)
+			})
+
+			AfterEach(func() {
+				tlsServer.Close()
+			})
+
+			It("should fail to verify the server without its CA certificate", func() {
+				sink, err := NewKVSecretSink(kvSinkSecret)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = sink.Get(ctx, binding)
+				Expect(err).To(HaveOccurred())
+				Expect(apierrors.IsNotFound(err)).To(BeFalse())
+			})
+
+			It("should verify the server with the caCert key", func() {
+				kvSinkSecret.Data["caCert"] = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw})
+				sink, err := NewKVSecretSink(kvSinkSecret)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = sink.Get(ctx, binding)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("should not verify the server with the insecureSkipVerify key", func() {
+				kvSinkSecret.Data["insecureSkipVerify"] = []byte("true")
+				sink, err := NewKVSecretSink(kvSinkSecret)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = sink.Get(ctx, binding)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("should fail with an invalid caCert key", func() {
+				kvSinkSecret.Data["caCert"] = []byte("invalid")
+				_, err := NewKVSecretSink(kvSinkSecret)
+				Expect(err).To(MatchError("the caCert key of the sap-btp-secret-sink secret has no valid PEM certificate"))
+			})
+		})
+
+		It("should store the data and the annotations of the secret", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "binding-secret", Namespace: "test", Annotations: map[string]string{"binding": "binding"}},
+				Data:       map[string][]byte{"key": []byte("value")},
+				StringData: map[string]string{"other": "other value"},
+			}
+			created, err := sink.Store(ctx, binding, secret)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created).To(BeTrue())
+			Expect(store.data).To(HaveKeyWithValue("secret/sap-btp-service-operator/test/binding-secret", map[string]string{"key": "value", "other": "other value"}))
+
+			stored, err := sink.Get(ctx, binding)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Name).To(Equal("binding-secret"))
+			Expect(stored.Data).To(Equal(map[string][]byte{"key": []byte("value"), "other": []byte("other value")}))
+			Expect(stored.Annotations).To(Equal(map[string]string{"binding": "binding"}))
+
+			created, err = sink.Store(ctx, binding, secret)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created).To(BeFalse())
+		})
+
+		It("should return a not found error for a secret that does not exist", func() {
+			_, err := sink.Get(ctx, binding)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should delete the secret", func() {
+			_, err := sink.Store(ctx, binding, &corev1.Secret{Data: map[string][]byte{"key": []byte("value")}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sink.Delete(ctx, binding)).To(Succeed())
+			Expect(store.data).To(BeEmpty())
+			Expect(sink.Delete(ctx, binding)).To(Succeed())
+		})
+
+		It("should not overwrite or delete the secret of another binding", func() {
+			binding.UID = "binding-uid"
+			_, err := sink.Store(ctx, binding, &corev1.Secret{Data: map[string][]byte{"key": []byte("value")}})
+			Expect(err).ToNot(HaveOccurred())
+
+			otherBinding := binding.DeepCopy()
+			otherBinding.Name = "other-binding"
+			otherBinding.UID = "other-binding-uid"
+			owner, err := sink.GetOtherOwner(ctx, otherBinding)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(owner).To(Equal("binding"))
+			_, err = sink.Store(ctx, otherBinding, &corev1.Secret{Data: map[string][]byte{"key": []byte("other value")}})
+			Expect(err).To(MatchError("the secret secret/sap-btp-service-operator/test/binding-secret in the key-value store belongs to another binding binding, choose a different secret name"))
+			Expect(sink.Delete(ctx, otherBinding)).To(Succeed())
+			Expect(store.data).To(HaveKeyWithValue("secret/sap-btp-service-operator/test/binding-secret", map[string]string{"key": "value"}))
+
+			owner, err = sink.GetOtherOwner(ctx, binding)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(owner).To(BeEmpty())
+			Expect(sink.Delete(ctx, binding)).To(Succeed())
+			Expect(store.data).To(BeEmpty())
+		})
+
+		It("should fail on an error of the key-value store", func() {
+			sink.Token = "wrong"
+			_, err := sink.Get(ctx, binding)
+			Expect(err).To(MatchError("key-value store request GET /v1/secret/data/sap-btp-service-operator/test/binding-secret failed with status 403"))
+			var sinkErr *KVSecretSinkError
+			Expect(errors.As(err, &sinkErr)).To(BeTrue())
+			Expect(sinkErr.IsTransient()).To(BeFalse())
+		})
+
+		It("should classify the errors of the key-value store", func() {
+			for statusCode, transient := range map[int]bool{
+				0:                              true,
+				http.StatusTooManyRequests:     true,
+				http.StatusServiceUnavailable:  true,
+				http.StatusInternalServerError: true,
+				http.StatusBadRequest:          false,
+				http.StatusForbidden:           false,
+			} {
+				Expect((&KVSecretSinkError{StatusCode: statusCode}).IsTransient()).To(Equal(transient), fmt.Sprintf("status %d", statusCode))
+			}
+
+			server.Close()
+			_, err := sink.Get(ctx, binding)
+			var sinkErr *KVSecretSinkError
+			Expect(errors.As(err, &sinkErr)).To(BeTrue())
+			Expect(sinkErr.IsTransient()).To(BeTrue())
+		})
+	})
+})
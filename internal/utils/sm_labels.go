@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	"github.com/SAP/sap-btp-service-operator/internal/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetSyncedSMLabels returns the Service Manager labels of a resource according to the label and annotation mappings of the configuration,
+// the labels the operator sets on every resource can't be mapped
+func GetSyncedSMLabels(obj metav1.Object, cfg config.Config) map[string]string {
+	smLabels := make(map[string]string)
+	addSMLabels(smLabels, obj.GetLabels(), cfg.SMLabelsFromLabels)
+	addSMLabels(smLabels, obj.GetAnnotations(), cfg.SMLabelsFromAnnotations)
+	if len(smLabels) == 0 {
+		return nil
+	}
+	return smLabels
+}
+
+func addSMLabels(smLabels, values, mapping map[string]string) {
+	for key, smKey := range mapping {
+		if smKey == common.NamespaceLabel || smKey == common.K8sNameLabel || smKey == common.ClusterIDLabel {
+			continue
+		}
+		if value, ok := values[key]; ok && len(value) > 0 {
+			smLabels[smKey] = value
+		}
+	}
+}
+
+// AddSyncedSMLabels adds the synced Service Manager labels to the labels of a resource that is created in Service Manager
+func AddSyncedSMLabels(labels smClientTypes.Labels, syncedLabels map[string]string) smClientTypes.Labels {
+	for key, value := range syncedLabels {
+		labels[key] = []string{value}
+	}
+	return labels
+}
+
+// GetSMLabelChanges returns the label changes that replace the synced Service Manager labels with the desired ones
+func GetSMLabelChanges(synced, desired map[string]string) []*smClientTypes.LabelChange {
+	var changes []*smClientTypes.LabelChange
+	for _, key := range slices.Sorted(maps.Keys(synced)) {
+		if value, ok := desired[key]; !ok || value != synced[key] {
+			changes = append(changes, &smClientTypes.LabelChange{Operation: smClientTypes.RemoveLabelOperation, Key: key, Values: []string{synced[key]}})
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(desired)) {
+		if value, ok := synced[key]; !ok || value != desired[key] {
+			changes = append(changes, &smClientTypes.LabelChange{Operation: smClientTypes.AddLabelOperation, Key: key, Values: []string{desired[key]}})
+		}
+	}
+	return changes
+}
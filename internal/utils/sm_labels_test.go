@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	smClientTypes "github.com/SAP/sap-btp-service-operator/client/sm/types"
+	"github.com/SAP/sap-btp-service-operator/internal/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SM labels", func() {
+	Context("GetSyncedSMLabels", func() {
+		var cfg config.Config
+
+		BeforeEach(func() {
+			cfg = config.Config{
+				SMLabelsFromLabels:      map[string]string{"team": "team", "example.com/stage": "stage", "other": common.ClusterIDLabel},
+				SMLabelsFromAnnotations: map[string]string{"example.com/cost-center": "cost_center"},
+			}
+		})
+
+		It("should map the labels and annotations of the resource", func() {
+			instance := &v1.ServiceInstance{}
+			instance.Labels = map[string]string{"team": "a-team", "example.com/stage": "", "other": "value", "unmapped": "value"}
+			instance.Annotations = map[string]string{"example.com/cost-center": "1234"}
+			Expect(GetSyncedSMLabels(instance, cfg)).To(Equal(map[string]string{"team": "a-team", "cost_center": "1234"}))
+		})
+
+		It("should return nil if there are no mapped labels", func() {
+			Expect(GetSyncedSMLabels(&v1.ServiceBinding{}, cfg)).To(BeNil())
+		})
+	})
+
+	Context("GetSMLabelChanges", func() {
+		It("should add, replace and remove labels", func() {
+			changes := GetSMLabelChanges(map[string]string{"team": "a-team", "stage": "dev", "cost_center": "1234"},
+				map[string]string{"team": "b-team", "cost_center": "1234", "region": "eu"})
+			Expect(changes).To(Equal([]*smClientTypes.LabelChange{
+				{Operation: smClientTypes.RemoveLabelOperation, Key: "stage", Values: []string{"dev"}},
+				{Operation: smClientTypes.RemoveLabelOperation, Key: "team", Values: []string{"a-team"}},
+				{Operation: smClientTypes.AddLabelOperation, Key: "region", Values: []string{"eu"}},
+				{Operation: smClientTypes.AddLabelOperation, Key: "team", Values: []string{"b-team"}},
+			}))
+		})
+
+		It("should return no changes if the labels are synced", func() {
+			Expect(GetSMLabelChanges(map[string]string{"team": "a-team"}, map[string]string{"team": "a-team"})).To(BeEmpty())
+			Expect(GetSMLabelChanges(nil, nil)).To(BeEmpty())
+		})
+	})
+})
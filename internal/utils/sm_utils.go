@@ -17,6 +17,55 @@ func (ic *InvalidCredentialsError) Error() string {
 }
 
 func GetSMClient(ctx context.Context, serviceInstance *v1.ServiceInstance) (sm.Client, error) {
+	clientConfig, err := GetSMClientConfig(ctx, serviceInstance)
+	if err != nil {
+		return nil, err
+	}
+	return sm.NewClient(ctx, clientConfig, nil)
+}
+
+// GetSMClientsForCluster returns a client for every distinct Service Manager credentials in use by the cluster,
+// the cluster default credentials are always returned first
+func GetSMClientsForCluster(ctx context.Context, k8sClient client.Reader) ([]sm.Client, error) {
+	log := GetLogger(ctx)
+	instances := &v1.ServiceInstanceList{}
+	if err := k8sClient.List(ctx, instances); err != nil {
+		return nil, err
+	}
+
+	defaultInstance := v1.ServiceInstance{}
+	defaultInstance.Namespace = secretsClient.ReleaseNamespace
+	candidates := append([]v1.ServiceInstance{defaultInstance}, instances.Items...)
+
+	var clients []sm.Client
+	seen := make(map[string]bool)
+	for i := range candidates {
+		clientConfig, err := GetSMClientConfig(ctx, &candidates[i])
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			log.Info(fmt.Sprintf("skipping credentials of instance %s in namespace %s: %s", candidates[i].Name, candidates[i].Namespace, err.Error()))
+			continue
+		}
+
+		key := clientConfig.URL + "/" + clientConfig.ClientID
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		smClient, err := sm.NewClient(ctx, clientConfig, nil)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, smClient)
+	}
+	return clients, nil
+}
+
+// GetSMClientConfig resolves the Service Manager credentials used by the given service instance
+func GetSMClientConfig(ctx context.Context, serviceInstance *v1.ServiceInstance) (*sm.ClientConfig, error) {
 	log := GetLogger(ctx)
 	var err error
 
@@ -74,5 +123,5 @@ func GetSMClient(ctx context.Context, serviceInstance *v1.ServiceInstance) (sm.C
 		clientConfig.TLSPrivateKey = string(tlsSecret.Data[corev1.TLSPrivateKeyKey])
 	}
 
-	return sm.NewClient(ctx, clientConfig, nil)
+	return clientConfig, nil
 }
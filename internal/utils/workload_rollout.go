@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RolloutWorkloads sets the secret hash annotation on the pod template of the workloads, which rolls them out
+func RolloutWorkloads(ctx context.Context, k8sClient client.Client, namespace string, workloads []v1.WorkloadReference, secretHash string) error {
+	log := GetLogger(ctx)
+	objects, err := getWorkloads(ctx, k8sClient, namespace, workloads)
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		template := podTemplate(object)
+		if template.Annotations[common.SecretHashAnnotation] == secretHash {
+			continue
+		}
+
+		patch := client.MergeFrom(object.DeepCopyObject().(client.Object))
+		if template.Annotations == nil {
+			template.Annotations = map[string]string{}
+		}
+		template.Annotations[common.SecretHashAnnotation] = secretHash
+		log.Info(fmt.Sprintf("rolling out %s %s", workloadKind(object), object.GetName()))
+		if err := k8sClient.Patch(ctx, object, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetWorkloadsRolloutStatus returns true if all the workloads are rolled out, otherwise a message about the first workload that is not
+func GetWorkloadsRolloutStatus(ctx context.Context, k8sClient client.Client, namespace string, workloads []v1.WorkloadReference) (bool, string, error) {
+	objects, err := getWorkloads(ctx, k8sClient, namespace, workloads)
+	if err != nil {
+		return false, "", err
+	}
+
+	for _, object := range objects {
+		if done, message := rolloutStatus(object); !done {
+			return false, fmt.Sprintf("waiting for %s %s to roll out: %s", workloadKind(object), object.GetName(), message), nil
+		}
+	}
+	return true, "", nil
+}
+
+func getWorkloads(ctx context.Context, k8sClient client.Client, namespace string, workloads []v1.WorkloadReference) ([]client.Object, error) {
+	var objects []client.Object
+	for _, workload := range workloads {
+		if len(workload.Name) > 0 {
+			object := newWorkload(workload.Kind)
+			if object == nil {
+				return nil, fmt.Errorf("unsupported workload kind %s", workload.Kind)
+			}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: workload.Name}, object); err != nil {
+				if apierrors.IsNotFound(err) {
+					// there is nothing to roll out
+					continue
+				}
+				return nil, err
+			}
+			objects = append(objects, object)
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(workload.Selector)
+		if err != nil {
+			return nil, err
+		}
+		if selector.Empty() {
+			// an empty selector would roll out all the workloads of the namespace
+			selector = labels.Nothing()
+		}
+		items, err := listWorkloads(ctx, k8sClient, workload.Kind, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, items...)
+	}
+	return objects, nil
+}
+
+func newWorkload(kind string) client.Object {
+	switch kind {
+	case v1.WorkloadKindDeployment:
+		return &appsv1.Deployment{}
+	case v1.WorkloadKindStatefulSet:
+		return &appsv1.StatefulSet{}
+	case v1.WorkloadKindDaemonSet:
+		return &appsv1.DaemonSet{}
+	}
+	return nil
+}
+
+func listWorkloads(ctx context.Context, k8sClient client.Client, kind string, opts ...client.ListOption) ([]client.Object, error) {
+	var objects []client.Object
+	switch kind {
+	case v1.WorkloadKindDeployment:
+		list := &appsv1.DeploymentList{}
+		if err := k8sClient.List(ctx, list, opts...); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+	case v1.WorkloadKindStatefulSet:
+		list := &appsv1.StatefulSetList{}
+		if err := k8sClient.List(ctx, list, opts...); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+	case v1.WorkloadKindDaemonSet:
+		list := &appsv1.DaemonSetList{}
+		if err := k8sClient.List(ctx, list, opts...); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %s", kind)
+	}
+	return objects, nil
+}
+
+func podTemplate(object client.Object) *corev1.PodTemplateSpec {
+	switch o := object.(type) {
+	case *appsv1.Deployment:
+		return &o.Spec.Template
+	case *appsv1.StatefulSet:
+		return &o.Spec.Template
+	case *appsv1.DaemonSet:
+		return &o.Spec.Template
+	}
+	return nil
+}
+
+func workloadKind(object client.Object) string {
+	switch object.(type) {
+	case *appsv1.Deployment:
+		return v1.WorkloadKindDeployment
+	case *appsv1.StatefulSet:
+		return v1.WorkloadKindStatefulSet
+	case *appsv1.DaemonSet:
+		return v1.WorkloadKindDaemonSet
+	}
+	return ""
+}
+
+// rolloutStatus returns true if the workload is rolled out, as kubectl rollout status does, otherwise a message about the progress
+func rolloutStatus(object client.Object) (bool, string) {
+	switch o := object.(type) {
+	case *appsv1.Deployment:
+		if o.Generation > o.Status.ObservedGeneration {
+			return false, "the update is not observed yet"
+		}
+		replicas := int32(1)
+		if o.Spec.Replicas != nil {
+			replicas = *o.Spec.Replicas
+		}
+		if o.Status.UpdatedReplicas < replicas {
+			return false, fmt.Sprintf("%d of %d updated replicas", o.Status.UpdatedReplicas, replicas)
+		}
+		if o.Status.Replicas > o.Status.UpdatedReplicas {
+			return false, fmt.Sprintf("%d old replicas are pending termination", o.Status.Replicas-o.Status.UpdatedReplicas)
+		}
+		if o.Status.AvailableReplicas < o.Status.UpdatedReplicas {
+			return false, fmt.Sprintf("%d of %d updated replicas are available", o.Status.AvailableReplicas, o.Status.UpdatedReplicas)
+		}
+	case *appsv1.StatefulSet:
+		if o.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+			// the pods are updated only when they are deleted
+			return true, ""
+		}
+		if o.Generation > o.Status.ObservedGeneration {
+			return false, "the update is not observed yet"
+		}
+		replicas := int32(1)
+		if o.Spec.Replicas != nil {
+			replicas = *o.Spec.Replicas
+		}
+		if o.Status.ReadyReplicas < replicas {
+			return false, fmt.Sprintf("%d of %d replicas are ready", o.Status.ReadyReplicas, replicas)
+		}
+		if o.Spec.UpdateStrategy.RollingUpdate != nil && o.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
+			partitioned := replicas - *o.Spec.UpdateStrategy.RollingUpdate.Partition
+			if o.Status.UpdatedReplicas < partitioned {
+				return false, fmt.Sprintf("%d of %d updated replicas", o.Status.UpdatedReplicas, partitioned)
+			}
+			return true, ""
+		}
+		if o.Status.UpdateRevision != o.Status.CurrentRevision {
+			return false, fmt.Sprintf("%d of %d updated replicas", o.Status.UpdatedReplicas, replicas)
+		}
+	case *appsv1.DaemonSet:
+		if o.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+			// the pods are updated only when they are deleted
+			return true, ""
+		}
+		if o.Generation > o.Status.ObservedGeneration {
+			return false, "the update is not observed yet"
+		}
+		if o.Status.UpdatedNumberScheduled < o.Status.DesiredNumberScheduled {
+			return false, fmt.Sprintf("%d of %d updated pods", o.Status.UpdatedNumberScheduled, o.Status.DesiredNumberScheduled)
+		}
+		if o.Status.NumberAvailable < o.Status.DesiredNumberScheduled {
+			return false, fmt.Sprintf("%d of %d updated pods are available", o.Status.NumberAvailable, o.Status.DesiredNumberScheduled)
+		}
+	}
+	return true, ""
+}
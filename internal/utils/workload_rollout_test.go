@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/SAP/sap-btp-service-operator/api/common"
+	v1 "github.com/SAP/sap-btp-service-operator/api/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Workload rollout", func() {
+	var (
+		ctx             context.Context
+		workloadsClient client.Client
+		deployment      *appsv1.Deployment
+		statefulSet     *appsv1.StatefulSet
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "test", Labels: map[string]string{"tier": "backend"}, Generation: 1},
+		}
+		statefulSet = &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "test", Labels: map[string]string{"tier": "backend"}, Generation: 1},
+		}
+		workloadsClient = fake.NewClientBuilder().WithObjects(deployment, statefulSet).WithStatusSubresource(deployment, statefulSet).Build()
+	})
+
+	getDeployment := func() *appsv1.Deployment {
+		result := &appsv1.Deployment{}
+		Expect(workloadsClient.Get(ctx, types.NamespacedName{Namespace: "test", Name: "app"}, result)).To(Succeed())
+		return result
+	}
+
+	getStatefulSet := func() *appsv1.StatefulSet {
+		result := &appsv1.StatefulSet{}
+		Expect(workloadsClient.Get(ctx, types.NamespacedName{Namespace: "test", Name: "db"}, result)).To(Succeed())
+		return result
+	}
+
+	Context("RolloutWorkloads", func() {
+		It("should set the secret hash on the pod template of a workload by name", func() {
+			workloads := []v1.WorkloadReference{{Kind: v1.WorkloadKindDeployment, Name: "app"}}
+			Expect(RolloutWorkloads(ctx, workloadsClient, "test", workloads, "hash")).To(Succeed())
+			Expect(getDeployment().Spec.Template.Annotations).To(HaveKeyWithValue(common.SecretHashAnnotation, "hash"))
+			Expect(getStatefulSet().Spec.Template.Annotations).ToNot(HaveKey(common.SecretHashAnnotation))
+		})
+
+		It("should set the secret hash on the pod template of workloads by selector", func() {
+			workloads := []v1.WorkloadReference{{Kind: v1.WorkloadKindStatefulSet, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "backend"}}}}
+			Expect(RolloutWorkloads(ctx, workloadsClient, "test", workloads, "hash")).To(Succeed())
+			Expect(getStatefulSet().Spec.Template.Annotations).To(HaveKeyWithValue(common.SecretHashAnnotation, "hash"))
+			Expect(getDeployment().Spec.Template.Annotations).ToNot(HaveKey(common.SecretHashAnnotation))
+		})
+
+		It("should not roll out any workload for an empty selector", func() {
+			workloads := []v1.WorkloadReference{{Kind: v1.WorkloadKindDeployment, Selector: &metav1.LabelSelector{}}}
+			Expect(RolloutWorkloads(ctx, workloadsClient, "test", workloads, "hash")).To(Succeed())
+			Expect(getDeployment().Spec.Template.Annotations).ToNot(HaveKey(common.SecretHashAnnotation))
+		})
+
+		It("should ignore a workload that does not exist", func() {
+			workloads := []v1.WorkloadReference{{Kind: v1.WorkloadKindDaemonSet, Name: "agent"}}
+			Expect(RolloutWorkloads(ctx, workloadsClient, "test", workloads, "hash")).To(Succeed())
+		})
+	})
+
+	Context("GetWorkloadsRolloutStatus", func() {
+		workloads := []v1.WorkloadReference{{Kind: v1.WorkloadKindDeployment, Name: "app"}}
+
+		It("should return a message until the deployment is rolled out", func() {
+			done, message, err := GetWorkloadsRolloutStatus(ctx, workloadsClient, "test", workloads)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeFalse())
+			Expect(message).To(Equal("waiting for Deployment app to roll out: the update is not observed yet"))
+
+			current := getDeployment()
+			current.Status = appsv1.DeploymentStatus{ObservedGeneration: current.Generation, Replicas: 2, UpdatedReplicas: 1, AvailableReplicas: 1}
+			Expect(workloadsClient.Status().Update(ctx, current)).To(Succeed())
+			done, message, err = GetWorkloadsRolloutStatus(ctx, workloadsClient, "test", workloads)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeFalse())
+			Expect(message).To(Equal("waiting for Deployment app to roll out: 1 old replicas are pending termination"))
+
+			current = getDeployment()
+			current.Status = appsv1.DeploymentStatus{ObservedGeneration: current.Generation, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+			Expect(workloadsClient.Status().Update(ctx, current)).To(Succeed())
+			done, _, err = GetWorkloadsRolloutStatus(ctx, workloadsClient, "test", workloads)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeTrue())
+		})
+
+		It("should not wait for a stateful set with the OnDelete strategy", func() {
+			current := getStatefulSet()
+			current.Spec.UpdateStrategy.Type = appsv1.OnDeleteStatefulSetStrategyType
+			Expect(workloadsClient.Update(ctx, current)).To(Succeed())
+			done, _, err := GetWorkloadsRolloutStatus(ctx, workloadsClient, "test", []v1.WorkloadReference{{Kind: v1.WorkloadKindStatefulSet, Name: "db"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeTrue())
+		})
+	})
+})
@@ -20,6 +20,8 @@ import (
 	"context"
 	"flag"
 	"os"
+	// embed the time zone database for the time zones of the credentials rotation policies
+	_ "time/tzdata"
 
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
@@ -40,6 +42,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 
 	"github.com/SAP/sap-btp-service-operator/internal/config"
+	"github.com/SAP/sap-btp-service-operator/internal/migration"
+	"github.com/SAP/sap-btp-service-operator/internal/orphans"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -50,6 +54,7 @@ import (
 	"github.com/SAP/sap-btp-service-operator/api/common"
 	servicesv1 "github.com/SAP/sap-btp-service-operator/api/v1"
 	"github.com/SAP/sap-btp-service-operator/api/v1/webhooks"
+	servicesv1alpha1 "github.com/SAP/sap-btp-service-operator/api/v1alpha1"
 	"github.com/SAP/sap-btp-service-operator/controllers"
 	// +kubebuilder:scaffold:imports
 )
@@ -63,6 +68,7 @@ func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 
 	_ = servicesv1.AddToScheme(scheme)
+	_ = servicesv1alpha1.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -136,8 +142,8 @@ func main() {
 	if len(config.Get().InitialClusterID) == 0 {
 		setupLog.Info("cluster secret not found, creating it")
 		createClusterSecret(mgr.GetClient())
-	} else if config.Get().InitialClusterID != config.Get().ClusterID {
-		panic(fmt.Sprintf("ClusterID changed, which is not supported. Please redeploy with --set cluster.id=%s", config.Get().InitialClusterID))
+	} else if config.Get().InitialClusterID != config.Get().ClusterID && !config.Get().EnableClusterIDMigration {
+		panic(fmt.Sprintf("ClusterID changed, which is not supported. Please redeploy with --set cluster.id=%s, or enable the cluster ID migration with --set manager.enable_cluster_id_migration=true", config.Get().InitialClusterID))
 	}
 
 	var nonCachedClient client.Client
@@ -152,6 +158,33 @@ func main() {
 
 	utils.InitializeSecretsClient(mgr.GetClient(), nonCachedClient, config.Get())
 
+	if len(config.Get().InitialClusterID) > 0 && config.Get().InitialClusterID != config.Get().ClusterID {
+		setupLog.Info(fmt.Sprintf("cluster ID changed from %s to %s, starting migration", config.Get().InitialClusterID, config.Get().ClusterID))
+		if err = mgr.Add(&migration.ClusterIDMigrator{
+			Client:       mgr.GetClient(),
+			APIReader:    mgr.GetAPIReader(),
+			Log:          ctrl.Log.WithName("migration").WithName("ClusterID"),
+			Config:       config.Get(),
+			GetSMClients: utils.GetSMClientsForCluster,
+		}); err != nil {
+			setupLog.Error(err, "unable to add cluster ID migration")
+			os.Exit(1)
+		}
+	}
+
+	if config.Get().OrphanScanInterval > 0 {
+		if err = mgr.Add(&orphans.Scanner{
+			Client:       mgr.GetClient(),
+			APIReader:    mgr.GetAPIReader(),
+			Log:          ctrl.Log.WithName("orphans"),
+			Config:       config.Get(),
+			GetSMClients: utils.GetSMClientsForCluster,
+		}); err != nil {
+			setupLog.Error(err, "unable to add orphans scanner")
+			os.Exit(1)
+		}
+	}
+
 	if err = (&controllers.ServiceInstanceReconciler{
 		Client:      mgr.GetClient(),
 		Log:         ctrl.Log.WithName("controllers").WithName("ServiceInstance"),
@@ -164,12 +197,13 @@ func main() {
 		os.Exit(1)
 	}
 	if err = (&controllers.ServiceBindingReconciler{
-		Client:      mgr.GetClient(),
-		Log:         ctrl.Log.WithName("controllers").WithName("ServiceBinding"),
-		Scheme:      mgr.GetScheme(),
-		Config:      config.Get(),
-		Recorder:    mgr.GetEventRecorderFor("ServiceBinding"),
-		GetSMClient: utils.GetSMClient,
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName("ServiceBinding"),
+		Scheme:        mgr.GetScheme(),
+		Config:        config.Get(),
+		Recorder:      mgr.GetEventRecorderFor("ServiceBinding"),
+		GetSMClient:   utils.GetSMClient,
+		GetSecretSink: utils.GetSecretSink,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ServiceBinding")
 		os.Exit(1)
@@ -185,7 +219,10 @@ func main() {
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		mgr.GetWebhookServer().Register("/mutate-services-cloud-sap-com-v1-serviceinstance", &webhook.Admission{Handler: &webhooks.ServiceInstanceDefaulter{Decoder: admission.NewDecoder(mgr.GetScheme())}})
 		mgr.GetWebhookServer().Register("/mutate-services-cloud-sap-com-v1-servicebinding", &webhook.Admission{Handler: &webhooks.ServiceBindingDefaulter{Decoder: admission.NewDecoder(mgr.GetScheme())}})
-		if err = (&servicesv1.ServiceBinding{}).SetupWebhookWithManager(mgr); err != nil {
+		if err = (&servicesv1.ServiceBinding{}).SetupWebhookWithManager(mgr, servicesv1.ServiceBindingWebhookOptions{
+			GetSecretSinkSecret:         utils.GetSecretSinkSecret,
+			EnforceServiceBindingGrants: config.Get().EnforceServiceBindingGrants,
+		}); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "ServiceBinding")
 			os.Exit(1)
 		}
@@ -215,10 +252,10 @@ func main() {
 
 func createClusterSecret(client client.Client) {
 	clusterSecret := &v1.Secret{}
-	clusterSecret.Name = "sap-btp-operator-clusterid"
+	clusterSecret.Name = utils.ClusterIDSecretName
 	clusterSecret.Namespace = config.Get().ReleaseNamespace
 	clusterSecret.Labels = map[string]string{common.ManagedByBTPOperatorLabel: "true", common.ClusterSecretLabel: "true"}
-	clusterSecret.StringData = map[string]string{"INITIAL_CLUSTER_ID": config.Get().ClusterID}
+	clusterSecret.StringData = map[string]string{utils.InitialClusterIDKey: config.Get().ClusterID}
 	clusterSecret.Labels = map[string]string{common.ManagedByBTPOperatorLabel: "true"}
 	if err := client.Create(context.Background(), clusterSecret); err != nil {
 		setupLog.Error(err, "failed to create cluster secret")
@@ -0,0 +1,21 @@
+{{/*
+The CA bundle of the webhook server certificate, empty when cert-manager injects it
+*/}}
+{{- define "sap-btp-operator.caBundle" -}}
+{{- if .Values.manager.certificates.selfSigned -}}
+{{ .Values.manager.certificates.selfSigned.caBundle }}
+{{- else if .Values.manager.certificates.gardenerCertManager -}}
+{{ .Values.manager.certificates.gardenerCertManager.caBundle }}
+{{- end -}}
+{{- end -}}
+
+{{/*
+A mapping of Kubernetes label or annotation keys to Service Manager label keys in the key:value,key:value format of the operator configuration
+*/}}
+{{- define "sap-btp-operator.smLabelsMapping" -}}
+{{- $pairs := list -}}
+{{- range $key, $smKey := . -}}
+{{- $pairs = append $pairs (printf "%s:%s" $key $smKey) -}}
+{{- end -}}
+{{ join "," $pairs }}
+{{- end -}}